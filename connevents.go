@@ -0,0 +1,43 @@
+package main
+
+import (
+	"time"
+
+	"github.com/goxray/desktop/internal/connlist"
+	"github.com/goxray/desktop/internal/events"
+	"github.com/goxray/desktop/window"
+)
+
+// recentEventsLimit is the number of connection events kept for the dashboard.
+const recentEventsLimit = 50
+
+// ConnectionEvents exposes recent connection events to the settings window.
+type ConnectionEvents struct {
+	log *events.Log
+}
+
+func NewConnectionEvents(log *events.Log) *ConnectionEvents {
+	return &ConnectionEvents{log: log}
+}
+
+func (e *ConnectionEvents) RecentEvents(n int) []window.ConnectionEvent {
+	recent := e.log.Recent(n)
+	res := make([]window.ConnectionEvent, 0, len(recent))
+	for _, ev := range recent {
+		res = append(res, window.ConnectionEvent{Time: ev.Time, Label: ev.Label, Kind: string(ev.Kind), Err: ev.Err})
+	}
+
+	return res
+}
+
+// logConnectionEvent adds connect or disconnect result of the item to the log.
+func logConnectionEvent(log *events.Log, item *connlist.Item, kind events.Kind, err error) {
+	ev := events.Event{Time: time.Now(), ItemID: item.ID(), Label: item.Label(), Kind: kind}
+	if err != nil {
+		ev.Err = err.Error()
+		if kind == events.KindConnected {
+			ev.Kind = events.KindFailed
+		}
+	}
+	log.Add(ev)
+}
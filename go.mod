@@ -5,11 +5,15 @@ go 1.25.6
 require (
 	fyne.io/fyne/v2 v2.5.3
 	fyne.io/systray v1.11.0
-	github.com/ajstarks/fc v0.0.0-20240825205253-42aeab80ccce
 	github.com/getlantern/elevate v0.0.0-20220903142053-479ab992b264
+	github.com/google/uuid v1.6.0
+	github.com/goxray/core v0.0.5
 	github.com/goxray/tun v0.0.9
+	github.com/jackpal/gateway v1.1.1
 	github.com/lilendian0x00/xray-knife/v3 v3.27.64
 	github.com/stretchr/testify v1.11.1
+	github.com/vishvananda/netlink v1.3.1
+	github.com/xtls/xray-core v1.260118.0
 	go.uber.org/mock v0.6.0
 )
 
@@ -41,10 +45,7 @@ require (
 	github.com/go-text/typesetting v0.2.1 // indirect
 	github.com/godbus/dbus/v5 v5.1.0 // indirect
 	github.com/google/btree v1.1.3 // indirect
-	github.com/google/uuid v1.6.0 // indirect
 	github.com/gorilla/websocket v1.5.3 // indirect
-	github.com/goxray/core v0.0.5 // indirect
-	github.com/jackpal/gateway v1.1.1 // indirect
 	github.com/jeandeaual/go-locale v0.0.0-20241217141322-fcc2cadd6f08 // indirect
 	github.com/jsummers/gobmp v0.0.0-20230614200233-a9de23ed2e25 // indirect
 	github.com/juju/ratelimit v1.0.2 // indirect
@@ -69,11 +70,9 @@ require (
 	github.com/srwiley/rasterx v0.0.0-20220730225603-2ab79fcdd4ef // indirect
 	github.com/stretchr/objx v0.5.2 // indirect
 	github.com/v2fly/ss-bloomring v0.0.0-20210312155135-28617310f63e // indirect
-	github.com/vishvananda/netlink v1.3.1 // indirect
 	github.com/vishvananda/netns v0.0.5 // indirect
 	github.com/xjasonlyu/tun2socks/v2 v2.6.1-0.20260111053224-8fae79e88939 // indirect
 	github.com/xtls/reality v0.0.0-20251014195629-e4eec4520535 // indirect
-	github.com/xtls/xray-core v1.260118.0 // indirect
 	github.com/yuin/goldmark v1.7.8 // indirect
 	go.uber.org/atomic v1.11.0 // indirect
 	go.uber.org/multierr v1.11.0 // indirect
@@ -0,0 +1,198 @@
+/*
+Package audit evaluates connection configurations against security rules and explains what is wrong with them.
+Rules work on the flat config maps of connections, where keys start with an uppercase letter, e.g. "TLS".
+*/
+package audit
+
+import (
+	"cmp"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Severity of a finding, higher is worse.
+type Severity int
+
+const (
+	Info Severity = iota
+	Warning
+	Critical
+)
+
+func (s Severity) String() string {
+	switch s {
+	case Info:
+		return "info"
+	case Warning:
+		return "warning"
+	case Critical:
+		return "critical"
+	default:
+		return "unknown"
+	}
+}
+
+// Config is a flat connection config, see connlist.Item.XRayConfig.
+type Config map[string]string
+
+// Get returns the first non-empty value of keys, keys are matched case-insensitively.
+func (c Config) Get(keys ...string) string {
+	for _, key := range keys {
+		for k, v := range c {
+			if v != "" && strings.EqualFold(k, key) {
+				return v
+			}
+		}
+	}
+
+	return ""
+}
+
+// Protocol returns the lowercase protocol of the config.
+func (c Config) Protocol() string {
+	return strings.ToLower(c.Get("Protocol"))
+}
+
+// Security returns the lowercase transport security, "none" if there is none.
+func (c Config) Security() string {
+	for _, key := range []string{"TLS", "Security"} {
+		switch v := strings.ToLower(c.Get(key)); v {
+		case "tls", "reality", "xtls":
+			return v
+		}
+	}
+
+	return "none"
+}
+
+// Rule checks a config for a single problem. Title and Explanation are constant, so they can be translated.
+type Rule struct {
+	ID          string
+	Severity    Severity
+	Title       string // Short, fits a badge.
+	Explanation string
+	Match       func(c Config) bool
+}
+
+// Finding is a rule matched by a config.
+type Finding struct {
+	Rule        string
+	Severity    Severity
+	Title       string
+	Explanation string
+}
+
+// Rules is a set of rules, other rules can be appended to Default.
+type Rules []Rule
+
+// Audit returns findings of the config, the most severe first.
+func (r Rules) Audit(c Config) []Finding {
+	var findings []Finding
+	for _, rule := range r {
+		if rule.Match(c) {
+			findings = append(findings, Finding{
+				Rule: rule.ID, Severity: rule.Severity, Title: rule.Title, Explanation: rule.Explanation,
+			})
+		}
+	}
+	slices.SortStableFunc(findings, func(a, b Finding) int { return cmp.Compare(b.Severity, a.Severity) })
+
+	return findings
+}
+
+// proxies are protocols with a TLS transport, chains and balancers are audited through their members.
+var proxies = []string{"vless", "vmess", "trojan"}
+
+var (
+	NoTLS = Rule{
+		ID:       "no-tls",
+		Severity: Critical,
+		Title:    "No TLS",
+		Explanation: "The connection to the server is not protected by TLS. It is easy to detect and block, and " +
+			"anyone on the way can read traffic that the protocol itself does not encrypt.",
+		Match: func(c Config) bool {
+			return slices.Contains(proxies, c.Protocol()) && c.Security() == "none"
+		},
+	}
+	AllowInsecure = Rule{
+		ID:       "allow-insecure",
+		Severity: Critical,
+		Title:    "Insecure TLS",
+		Explanation: "The server certificate is not verified, so anyone on the way can impersonate the server " +
+			"and read the traffic.",
+		Match: func(c Config) bool {
+			v, _ := strconv.ParseBool(c.Get("AllowInsecure", "Insecure"))
+			return v && c.Security() != "none"
+		},
+	}
+	VMessAlterID = Rule{
+		ID:       "vmess-alter-id",
+		Severity: Warning,
+		Title:    "Legacy VMess",
+		Explanation: "VMess with alter ID above 0 uses the legacy MD5 authentication which is deprecated and " +
+			"detectable, alter ID 0 enables AEAD.",
+		Match: func(c Config) bool {
+			aid, _ := strconv.Atoi(c.Get("Aid"))
+			return c.Protocol() == "vmess" && aid > 0
+		},
+	}
+	DeprecatedCipher = Rule{
+		ID:       "deprecated-cipher",
+		Severity: Warning,
+		Title:    "Deprecated cipher",
+		Explanation: "The cipher is deprecated: stream ciphers without authentication can be altered and " +
+			"detected, and no encryption at all relies on TLS only.",
+		Match: func(c Config) bool {
+			switch c.Protocol() {
+			case "shadowsocks", "ss":
+				cipher := strings.ToLower(c.Get("Encryption", "Method", "Cipher"))
+				return cipher != "" && !aeadShadowsocks(cipher)
+			case "vmess":
+				return slices.Contains([]string{"none", "zero", "aes-128-cfb"}, strings.ToLower(c.Get("Scy")))
+			default:
+				return false
+			}
+		},
+	}
+	PlainHTTP = Rule{
+		ID:       "plain-http",
+		Severity: Warning,
+		Title:    "Plain HTTP",
+		Explanation: "The transport is plain HTTP without TLS, requests including host and path are visible " +
+			"to anyone on the way.",
+		Match: func(c Config) bool {
+			if !slices.Contains(proxies, c.Protocol()) || c.Security() != "none" {
+				return false
+			}
+			network := strings.ToLower(c.Get("Network"))
+			header := strings.ToLower(c.Get("HeaderType"))
+			if c.Protocol() == "vmess" {
+				header = strings.ToLower(c.Get("Type")) // Vmess keeps the network in Network and the header in Type.
+			} else if t := strings.ToLower(c.Get("Type")); t != "" {
+				network = t
+			}
+
+			return header == "http" || slices.Contains([]string{"ws", "http", "h2", "httpupgrade", "xhttp"}, network)
+		},
+	}
+	MissingFingerprint = Rule{
+		ID:       "missing-fingerprint",
+		Severity: Info,
+		Title:    "No fingerprint",
+		Explanation: "No TLS fingerprint is set, the TLS handshake looks like a Go program rather than a browser " +
+			"and is easier to detect.",
+		Match: func(c Config) bool {
+			return c.Security() != "none" && c.Get("TlsFingerprint", "Fp", "Fingerprint") == ""
+		},
+	}
+)
+
+// Default are the built-in rules.
+var Default = Rules{NoTLS, AllowInsecure, VMessAlterID, DeprecatedCipher, PlainHTTP, MissingFingerprint}
+
+// aeadShadowsocks reports whether the shadowsocks cipher is an AEAD one, including 2022 ciphers, "none" is not.
+func aeadShadowsocks(cipher string) bool {
+	return strings.HasPrefix(cipher, "2022-") || strings.HasSuffix(cipher, "-gcm") ||
+		strings.HasSuffix(cipher, "-poly1305")
+}
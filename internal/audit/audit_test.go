@@ -0,0 +1,78 @@
+package audit
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func ruleIDs(findings []Finding) []string {
+	var ids []string
+	for _, f := range findings {
+		ids = append(ids, f.Rule)
+	}
+
+	return ids
+}
+
+func TestDefault(t *testing.T) {
+	for name, tc := range map[string]struct {
+		config Config
+		want   []string
+	}{
+		"secure vless": {
+			config: Config{"Protocol": "vless", "TLS": "reality", "Security": "reality", "TlsFingerprint": "chrome", "Type": "tcp"},
+		},
+		"vless without tls over ws": {
+			config: Config{"Protocol": "vless", "TLS": "none", "Type": "ws"},
+			want:   []string{"no-tls", "plain-http"},
+		},
+		"trojan with http header": {
+			config: Config{"Protocol": "trojan", "TLS": "none", "Type": "tcp", "HeaderType": "http"},
+			want:   []string{"no-tls", "plain-http"},
+		},
+		"insecure tls without fingerprint": {
+			config: Config{"Protocol": "trojan", "TLS": "tls", "AllowInsecure": "1", "Type": "ws"},
+			want:   []string{"allow-insecure", "missing-fingerprint"},
+		},
+		"legacy vmess": {
+			config: Config{"Protocol": "vmess", "TLS": "tls", "Aid": "64", "Scy": "zero", "Fp": "chrome", "Network": "ws", "Type": "none"},
+			want:   []string{"vmess-alter-id", "deprecated-cipher"},
+		},
+		"vmess with http header": {
+			config: Config{"Protocol": "vmess", "TLS": "", "Aid": "0", "Scy": "auto", "Network": "tcp", "Type": "http"},
+			want:   []string{"no-tls", "plain-http"},
+		},
+		"stream shadowsocks": {
+			config: Config{"Protocol": "shadowsocks", "Encryption": "aes-256-cfb"},
+			want:   []string{"deprecated-cipher"},
+		},
+		"aead shadowsocks": {
+			config: Config{"Protocol": "shadowsocks", "Encryption": "2022-blake3-aes-128-gcm"},
+		},
+		"chain": {
+			config: Config{"Protocol": "chain", "Chain": "a → b"},
+		},
+	} {
+		require.Equal(t, tc.want, ruleIDs(Default.Audit(tc.config)), name)
+	}
+}
+
+func TestRules_Audit(t *testing.T) {
+	custom := Rule{ID: "custom", Severity: Info, Match: func(c Config) bool { return c.Get("port") == "80" }}
+	rules := append(Rules{custom}, Default...)
+
+	findings := rules.Audit(Config{"Protocol": "vless", "TLS": "none", "Port": "80"})
+	require.Equal(t, []string{"no-tls", "custom"}, ruleIDs(findings), "the most severe first")
+	require.Equal(t, Finding{Rule: "no-tls", Severity: Critical, Title: NoTLS.Title, Explanation: NoTLS.Explanation},
+		findings[0])
+}
+
+func TestConfig(t *testing.T) {
+	c := Config{"Protocol": "VLESS", "TLS": "none", "Security": "tls", "fp": "", "Fp": "chrome"}
+	require.Equal(t, "vless", c.Protocol())
+	require.Equal(t, "tls", c.Security())
+	require.Equal(t, "chrome", c.Get("FP"))
+	require.Equal(t, "none", Config{}.Security())
+	require.Equal(t, "critical", Critical.String())
+}
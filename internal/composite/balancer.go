@@ -0,0 +1,110 @@
+package composite
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/xtls/xray-core/infra/conf"
+	"github.com/xtls/xray-core/infra/conf/cfgcommon/duration"
+)
+
+// Strategy is xray balancer strategy used to pick an outbound for each connection.
+type Strategy string
+
+const (
+	StrategyRandom     Strategy = "random"
+	StrategyRoundRobin Strategy = "roundRobin"
+	StrategyLeastPing  Strategy = "leastPing"
+)
+
+// HealthCheckURL is probed to check that a connection is alive.
+const HealthCheckURL = "https://www.google.com/generate_204"
+
+const (
+	balancerTag       = "balancer"
+	memberTagPrefix   = "member-"
+	healthCheckPeriod = time.Minute
+)
+
+var (
+	ErrBalancerTooSmall = errors.New("balancer must have at least two members")
+	ErrUnknownStrategy  = errors.New("unknown balancer strategy")
+)
+
+// Strategies lists all supported balancer strategies.
+func Strategies() []Strategy {
+	return []Strategy{StrategyRandom, StrategyRoundRobin, StrategyLeastPing}
+}
+
+// ParseStrategy validates strategy name, empty name defaults to StrategyRandom.
+func ParseStrategy(s string) (Strategy, error) {
+	if s == "" {
+		return StrategyRandom, nil
+	}
+	if !slices.Contains(Strategies(), Strategy(s)) {
+		return "", fmt.Errorf("%w: %s", ErrUnknownStrategy, s)
+	}
+
+	return Strategy(s), nil
+}
+
+// MemberTag returns outbound tag of the i-th balancer member, use it to query Client.Outbound.
+func MemberTag(i int) string {
+	return fmt.Sprintf("%s%d", memberTagPrefix, i)
+}
+
+// Balancer returns Builder that combines links into a single balancer, every link is a separate outbound.
+// Members are probed by observatory, so dead ones are skipped by all strategies.
+func Balancer(strategy Strategy) Builder {
+	return func(links []string) (*conf.Config, []string, error) {
+		if len(links) < 2 {
+			return nil, nil, ErrBalancerTooSmall
+		}
+
+		cfg := &conf.Config{OutboundConfigs: make([]conf.OutboundDetourConfig, 0, len(links))}
+		direct := make([]string, 0, len(links))
+		for i, link := range links {
+			ob, general, err := outbound(link, MemberTag(i))
+			if err != nil {
+				return nil, nil, fmt.Errorf("member %d: %w", i+1, err)
+			}
+
+			cfg.OutboundConfigs = append(cfg.OutboundConfigs, *ob)
+			direct = append(direct, general.Address)
+		}
+
+		// Route everything from the TUN inbound to the balancer.
+		rule, err := json.Marshal(map[string]any{
+			"type":        "field",
+			"inboundTag":  []string{inboundTag},
+			"balancerTag": balancerTag,
+		})
+		if err != nil {
+			return nil, nil, fmt.Errorf("marshal routing rule: %w", err)
+		}
+		cfg.RouterConfig = &conf.RouterConfig{
+			RuleList: []json.RawMessage{rule},
+			Balancers: []*conf.BalancingRule{{
+				Tag:       balancerTag,
+				Selectors: conf.StringList{memberTagPrefix},
+				Strategy:  conf.StrategyConfig{Type: string(strategy)},
+			}},
+		}
+		cfg.Observatory = &conf.ObservatoryConfig{
+			SubjectSelector: []string{memberTagPrefix},
+			ProbeURL:        HealthCheckURL,
+			ProbeInterval:   duration.Duration(healthCheckPeriod),
+		}
+		// Per member traffic counters.
+		cfg.Stats = &conf.StatsConfig{}
+		cfg.Policy = &conf.PolicyConfig{System: &conf.SystemPolicy{
+			StatsOutboundUplink:   true,
+			StatsOutboundDownlink: true,
+		}}
+
+		return cfg, direct, nil
+	}
+}
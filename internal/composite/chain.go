@@ -0,0 +1,50 @@
+package composite
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/xtls/xray-core/infra/conf"
+)
+
+const chainTagPrefix = "hop-"
+
+var ErrChainTooShort = errors.New("chain must have at least two hops")
+
+// Chain builds xray config where links[0] is the exit server and every hop dials through the next one
+// using dialerProxy, the last link is the entry (jump) server and the only one dialed directly.
+func Chain(links []string) (*conf.Config, []string, error) {
+	if len(links) < 2 {
+		return nil, nil, ErrChainTooShort
+	}
+
+	cfg := &conf.Config{OutboundConfigs: make([]conf.OutboundDetourConfig, 0, len(links))}
+	entry := ""
+	for i, link := range links {
+		ob, general, err := outbound(link, chainTag(i))
+		if err != nil {
+			return nil, nil, fmt.Errorf("hop %d: %w", i+1, err)
+		}
+
+		if i == len(links)-1 {
+			entry = general.Address
+		} else {
+			if ob.StreamSetting == nil {
+				ob.StreamSetting = &conf.StreamConfig{}
+			}
+			if ob.StreamSetting.SocketSettings == nil {
+				ob.StreamSetting.SocketSettings = &conf.SocketConfig{}
+			}
+			ob.StreamSetting.SocketSettings.DialerProxy = chainTag(i + 1)
+		}
+
+		// The first outbound is the default one, so all traffic leaves through the exit hop.
+		cfg.OutboundConfigs = append(cfg.OutboundConfigs, *ob)
+	}
+
+	return cfg, []string{entry}, nil
+}
+
+func chainTag(i int) string {
+	return fmt.Sprintf("%s%d", chainTagPrefix, i)
+}
@@ -0,0 +1,319 @@
+package composite
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"os"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/goxray/core/network/route"
+	"github.com/goxray/core/network/tun"
+	"github.com/goxray/core/pipe2socks"
+	"github.com/jackpal/gateway"
+	"github.com/lilendian0x00/xray-knife/v3/pkg/xray"
+	"github.com/xtls/xray-core/app/observatory"
+	"github.com/xtls/xray-core/core"
+	"github.com/xtls/xray-core/features/extension"
+	"github.com/xtls/xray-core/features/stats"
+	"github.com/xtls/xray-core/infra/conf"
+)
+
+const (
+	disconnectTimeout = 30 * time.Second
+	inboundTag        = "tun-in"
+)
+
+var (
+	// tunAddress matches the address used by goxray/tun, only one connection is active at a time.
+	tunAddress = &net.IPNet{IP: net.IPv4(192, 18, 0, 1), Mask: net.IPv4Mask(255, 255, 255, 255)}
+	// routesToTUN will route all system traffic through the TUN.
+	routesToTUN = []*route.Addr{
+		route.MustParseAddr("0.0.0.0/1"),
+		route.MustParseAddr("128.0.0.0/1"),
+	}
+)
+
+// Client is a VPN client for composite configurations, it mimics goxray/tun client behaviour.
+type Client struct {
+	build  Builder
+	logger *slog.Logger
+
+	mu         sync.Mutex // Guards inst as it is queried for stats concurrently.
+	inst       *core.Instance
+	tunnel     *tunnelMetrics
+	exceptions []route.Opts
+	stop       func()
+	stopped    chan error // Result of the tunnel of the current connection, buffered so the tunnel never blocks.
+}
+
+// NewClient creates client that builds xray configuration with build on every Connect.
+func NewClient(build Builder, logger *slog.Logger) *Client {
+	if logger == nil {
+		logger = slog.New(slog.NewTextHandler(os.Stdout, nil))
+	}
+
+	return &Client{
+		build:  build,
+		logger: logger,
+	}
+}
+
+// Connect builds xray config from target (see JoinLinks), starts it and routes all traffic through it.
+func (c *Client) Connect(target string) (err error) {
+	// Clean up everything that was set up before the failure.
+	defer func() {
+		if err != nil {
+			c.cleanup()
+		}
+	}()
+
+	cfg, direct, err := c.build(SplitLinks(target))
+	if err != nil {
+		return fmt.Errorf("build xray config: %w", err)
+	}
+
+	proxyAddr, err := c.startXray(cfg)
+	if err != nil {
+		return err
+	}
+	c.logger.Debug("xray core instance started", "inbound", proxyAddr)
+
+	gw, err := gateway.DiscoverGateway()
+	if err != nil {
+		return fmt.Errorf("discover gateway: %w", err)
+	}
+	// Servers dialed directly must bypass the TUN device, otherwise we get a loop.
+	for _, addr := range direct {
+		ip, err := net.ResolveIPAddr("ip", addr)
+		if err != nil {
+			return fmt.Errorf("xray address not resolvable: %w", err)
+		}
+
+		opts := route.Opts{Gateway: gw, Routes: []*route.Addr{route.MustParseAddr(ip.String() + "/32")}}
+		_ = route.Delete(opts) // In case previous run failed.
+		if err := route.Add(opts); err != nil {
+			return fmt.Errorf("add xray server route exception: %w", err)
+		}
+		c.exceptions = append(c.exceptions, opts)
+	}
+
+	ifc, err := tun.New("", 1500)
+	if err != nil {
+		return fmt.Errorf("create tun: %w", err)
+	}
+	c.tunnel = &tunnelMetrics{ReadWriteCloser: ifc}
+	if err = ifc.Up(tunAddress, tunAddress.IP); err != nil {
+		return fmt.Errorf("setup interface: %w", err)
+	}
+	if err = route.Add(route.Opts{IfName: ifc.Name(), Routes: routesToTUN}); err != nil {
+		return fmt.Errorf("add route: %w", err)
+	}
+
+	pipe, err := pipe2socks.NewPipe(pipe2socks.DefaultOpts)
+	if err != nil {
+		return fmt.Errorf("tun2socks new pipe: %w", err)
+	}
+
+	var ctx context.Context
+	ctx, c.stop = context.WithCancel(context.Background())
+	// A new channel per connection, so a result left after a timed out Disconnect is not read by the next one.
+	stopped := make(chan error, 1)
+	c.stopped = stopped
+	go func() {
+		stopped <- pipe.Copy(ctx, c.tunnel, proxyAddr)
+	}()
+	c.logger.Debug("composite client connected")
+
+	return nil
+}
+
+// Disconnect stops the tunnel and cleans up routes, it blocks till the tunnel is done or ctx is cancelled.
+func (c *Client) Disconnect(ctx context.Context) error {
+	if c.stop == nil {
+		return nil // not connected
+	}
+
+	c.stop()
+	c.stop = nil
+	err := c.cleanup()
+
+	ctx, cancel := context.WithTimeout(ctx, disconnectTimeout)
+	defer cancel()
+	select {
+	case tunErr := <-c.stopped:
+		err = errors.Join(tunErr, err)
+	case <-ctx.Done():
+		err = errors.Join(ctx.Err(), err)
+	}
+
+	if err != nil {
+		c.logger.Error("composite client disconnect encountered failures", "err", err)
+
+		return err
+	}
+
+	return nil
+}
+
+// BytesRead returns number of bytes read from TUN device.
+func (c *Client) BytesRead() int {
+	if c.tunnel == nil {
+		return 0
+	}
+
+	return int(c.tunnel.read.Load())
+}
+
+// BytesWritten returns number of bytes written to TUN device.
+func (c *Client) BytesWritten() int {
+	if c.tunnel == nil {
+		return 0
+	}
+
+	return int(c.tunnel.written.Load())
+}
+
+// OutboundStats represents traffic and health check results of a single outbound.
+type OutboundStats struct {
+	Uplink   int64
+	Downlink int64
+	// Observed is false until the outbound is probed by observatory, Alive and Delay are meaningless till then.
+	Observed bool
+	Alive    bool
+	Delay    time.Duration
+}
+
+// Outbound returns stats of the outbound with tag, zero value is returned if the client is not connected.
+// Traffic is counted only for builders that enable stats, e.g. Balancer.
+func (c *Client) Outbound(tag string) OutboundStats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	res := OutboundStats{}
+	if c.inst == nil {
+		return res
+	}
+
+	if m, ok := c.inst.GetFeature(stats.ManagerType()).(stats.Manager); ok {
+		if counter := m.GetCounter("outbound>>>" + tag + ">>>traffic>>>uplink"); counter != nil {
+			res.Uplink = counter.Value()
+		}
+		if counter := m.GetCounter("outbound>>>" + tag + ">>>traffic>>>downlink"); counter != nil {
+			res.Downlink = counter.Value()
+		}
+	}
+
+	if o, ok := c.inst.GetFeature(extension.ObservatoryType()).(extension.Observatory); ok {
+		msg, err := o.GetObservation(context.Background())
+		if result, ok := msg.(*observatory.ObservationResult); err == nil && ok {
+			for _, status := range result.GetStatus() {
+				if status.GetOutboundTag() != tag {
+					continue
+				}
+
+				res.Observed = true
+				res.Alive = status.GetAlive()
+				res.Delay = time.Duration(status.GetDelay()) * time.Millisecond
+			}
+		}
+	}
+
+	return res
+}
+
+// startXray adds local socks inbound to cfg and starts xray instance, returns inbound address.
+func (c *Client) startXray(cfg *conf.Config) (string, error) {
+	inbound := &xray.Socks{
+		Remark:  "GoXRay-TUN-Listener",
+		Address: "127.0.0.1",
+		Port:    strconv.Itoa(getFreePort()),
+	}
+	ib, err := inbound.BuildInboundDetourConfig()
+	if err != nil {
+		return "", fmt.Errorf("build inbound: %w", err)
+	}
+	ib.Tag = inboundTag
+	cfg.InboundConfigs = append(cfg.InboundConfigs, *ib)
+
+	built, err := cfg.Build()
+	if err != nil {
+		return "", fmt.Errorf("build xray config: %w", err)
+	}
+
+	inst, err := core.New(built)
+	if err != nil {
+		return "", fmt.Errorf("create xray core instance: %w", err)
+	}
+	c.mu.Lock()
+	c.inst = inst
+	c.mu.Unlock()
+	if err = inst.Start(); err != nil {
+		return "", fmt.Errorf("start xray core instance: %w", err)
+	}
+	time.Sleep(100 * time.Millisecond) // Sometimes XRay instance should have a bit more time to set up.
+
+	return net.JoinHostPort(inbound.Address, inbound.Port), nil
+}
+
+func (c *Client) cleanup() error {
+	var err error
+	if c.tunnel != nil {
+		err = errors.Join(err, c.tunnel.Close())
+	}
+	for _, opts := range c.exceptions {
+		err = errors.Join(err, route.Delete(opts))
+	}
+	c.exceptions = nil
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.inst != nil {
+		err = errors.Join(err, c.inst.Close())
+		c.inst = nil
+	}
+
+	return err
+}
+
+// tunnelMetrics wraps io.ReadWriteCloser with simple metrics.
+type tunnelMetrics struct {
+	io.ReadWriteCloser
+
+	read    atomic.Int64
+	written atomic.Int64
+}
+
+func (t *tunnelMetrics) Read(p []byte) (n int, err error) {
+	n, err = t.ReadWriteCloser.Read(p)
+	if err == nil {
+		t.read.Add(int64(n))
+	}
+
+	return n, err
+}
+
+func (t *tunnelMetrics) Write(p []byte) (n int, err error) {
+	n, err = t.ReadWriteCloser.Write(p)
+	if err == nil {
+		t.written.Add(int64(n))
+	}
+
+	return n, err
+}
+
+func getFreePort() int {
+	ln, err := net.Listen("tcp", "localhost:0")
+	if err != nil {
+		return 10808
+	}
+	defer ln.Close()
+
+	return ln.Addr().(*net.TCPAddr).Port
+}
@@ -0,0 +1,65 @@
+/*
+Package composite builds and runs xray configurations that combine several connection links
+into one connection, e.g. a chain of proxies where each hop dials through the next one.
+
+The goxray/tun client only accepts a single link, so Client sets up the TUN device and routes itself
+and feeds it with the xray instance created from the combined configuration.
+*/
+package composite
+
+import (
+	"fmt"
+	"strings"
+
+	xrayproto "github.com/lilendian0x00/xray-knife/v3/pkg/protocol"
+	"github.com/lilendian0x00/xray-knife/v3/pkg/xray"
+	"github.com/xtls/xray-core/infra/conf"
+)
+
+// LinkSeparator separates links in the target string passed to Client.Connect.
+const LinkSeparator = "\n"
+
+// Builder creates xray config from the list of links. It must also return addresses of the
+// servers that are dialed directly (without another outbound), so they can be routed around the TUN device.
+type Builder func(links []string) (cfg *conf.Config, direct []string, err error)
+
+// JoinLinks packs links into a single target string accepted by Client.Connect.
+func JoinLinks(links []string) string {
+	return strings.Join(links, LinkSeparator)
+}
+
+// SplitLinks is the reverse of JoinLinks.
+func SplitLinks(target string) []string {
+	links := make([]string, 0)
+	for _, l := range strings.Split(target, LinkSeparator) {
+		if l = strings.TrimSpace(l); l != "" {
+			links = append(links, l)
+		}
+	}
+
+	return links
+}
+
+// outbound parses link and builds xray outbound config for it with the provided tag.
+func outbound(link, tag string) (*conf.OutboundDetourConfig, xrayproto.GeneralConfig, error) {
+	proto, err := (&xray.Core{}).CreateProtocol(link)
+	if err != nil {
+		return nil, xrayproto.GeneralConfig{}, fmt.Errorf("invalid xray link: %s", err)
+	}
+	if err := proto.Parse(); err != nil {
+		return nil, xrayproto.GeneralConfig{}, fmt.Errorf("invalid xray link: %s", err)
+	}
+
+	builder, ok := proto.(xray.Protocol)
+	if !ok {
+		return nil, xrayproto.GeneralConfig{}, fmt.Errorf("unsupported protocol %T", proto)
+	}
+
+	ob, err := builder.BuildOutboundDetourConfig(false)
+	if err != nil {
+		return nil, xrayproto.GeneralConfig{}, fmt.Errorf("build outbound: %w", err)
+	}
+	ob.Tag = tag
+
+	return ob, proto.ConvertToGeneralConfig(), nil
+}
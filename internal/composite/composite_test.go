@@ -0,0 +1,66 @@
+package composite
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	sampleVlessLink  = "vless://h1px412i-9138-s9m5-9b86-d47d74dd8541@127.0.0.1:8080?type=tcp&security=reality&pbk=4442383675fc0fb574c3e50abbe7d4c5&fp=chrome&sni=yahoo.com&sid=0c&spx=%2F&flow=xtls-rprx-vision#Myremark"
+	sampleTrojanLink = "trojan://password@127.0.0.2:443?security=tls&sni=example.com&type=tcp#Jump"
+)
+
+func TestLinks(t *testing.T) {
+	links := []string{sampleVlessLink, sampleTrojanLink}
+
+	require.Equal(t, links, SplitLinks(JoinLinks(links)))
+	require.Empty(t, SplitLinks(""))
+	require.Equal(t, []string{"a", "b"}, SplitLinks(" a \n\n b\n"))
+}
+
+func TestChain(t *testing.T) {
+	_, _, err := Chain([]string{sampleVlessLink})
+	require.ErrorIs(t, err, ErrChainTooShort)
+
+	_, _, err = Chain([]string{sampleVlessLink, "invalid"})
+	require.ErrorContains(t, err, "hop 2: invalid xray link")
+
+	cfg, direct, err := Chain([]string{sampleVlessLink, sampleTrojanLink})
+	require.NoError(t, err)
+	require.Equal(t, []string{"127.0.0.2"}, direct) // Only the entry server is dialed directly.
+	require.Len(t, cfg.OutboundConfigs, 2)
+
+	exit, entry := cfg.OutboundConfigs[0], cfg.OutboundConfigs[1]
+	require.Equal(t, "hop-0", exit.Tag)
+	require.Equal(t, "hop-1", entry.Tag)
+	require.Equal(t, "hop-1", exit.StreamSetting.SocketSettings.DialerProxy)
+	require.True(t, entry.StreamSetting == nil || entry.StreamSetting.SocketSettings == nil)
+}
+
+func TestBalancer(t *testing.T) {
+	strategy, err := ParseStrategy("")
+	require.NoError(t, err)
+	require.Equal(t, StrategyRandom, strategy)
+	_, err = ParseStrategy("fastest")
+	require.ErrorIs(t, err, ErrUnknownStrategy)
+
+	_, _, err = Balancer(StrategyLeastPing)([]string{sampleVlessLink})
+	require.ErrorIs(t, err, ErrBalancerTooSmall)
+
+	cfg, direct, err := Balancer(StrategyLeastPing)([]string{sampleVlessLink, sampleTrojanLink})
+	require.NoError(t, err)
+	require.Equal(t, []string{"127.0.0.1", "127.0.0.2"}, direct)
+	require.Len(t, cfg.OutboundConfigs, 2)
+	require.Equal(t, MemberTag(0), cfg.OutboundConfigs[0].Tag)
+	require.Equal(t, MemberTag(1), cfg.OutboundConfigs[1].Tag)
+
+	require.Len(t, cfg.RouterConfig.Balancers, 1)
+	require.Equal(t, string(StrategyLeastPing), cfg.RouterConfig.Balancers[0].Strategy.Type)
+	require.Len(t, cfg.RouterConfig.RuleList, 1)
+	require.NotNil(t, cfg.Observatory)
+	require.True(t, cfg.Policy.System.StatsOutboundUplink)
+
+	_, err = cfg.Build()
+	require.NoError(t, err)
+}
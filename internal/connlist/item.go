@@ -5,15 +5,32 @@ import (
 	"encoding/json"
 	"fmt"
 	"log/slog"
+	"maps"
 	"os"
 	"slices"
 	"strings"
+	"sync/atomic"
+	"time"
 
+	"fyne.io/fyne/v2/data/binding"
+	"github.com/google/uuid"
 	vpn "github.com/goxray/tun/pkg/client"
 	xrayproto "github.com/lilendian0x00/xray-knife/v3/pkg/protocol"
 	xray3 "github.com/lilendian0x00/xray-knife/v3/pkg/xray"
 
-	"github.com/goxray/desktop/internal/netchart"
+	"github.com/goxray/desktop/internal/composite"
+)
+
+// ProtocolChain is the XRayConfig "Protocol" value of chained items.
+const ProtocolChain = "chain"
+
+// DisconnectReason tells why an item is disconnected.
+type DisconnectReason string
+
+const (
+	DisconnectUser      DisconnectReason = "user"
+	DisconnectReconnect DisconnectReason = "reconnect"
+	DisconnectShutdown  DisconnectReason = "shutdown"
 )
 
 type Client interface {
@@ -26,42 +43,72 @@ type Client interface {
 // Item is a combine that is passed (via interface segregation) throughout the system to apply
 // centralized changes to connections with the smallest overhead as possible.
 type Item struct {
+	id         string
 	label      string
 	link       string
+	hops       []string // IDs of chained items, hops[i] dials through hops[i+1]. Empty for other items.
+	members    []string // IDs of balanced items. Empty for other items.
+	strategy   string   // Balancer strategy.
 	xconfigMap map[string]string
 	active     bool
+	history    []Version // Previous versions, the newest first.
 
 	parent   *Collection
 	client   Client
 	recorder NetworkRecorder
+
+	updates     binding.Int // Incremented after each sample of recorder.
+	reconnects  atomic.Int64
+	connectedAt atomic.Int64                 // Unix nanoseconds of the last successful connect, 0 if disconnected.
+	health      atomic.Pointer[healthResult] // Result of the last HealthCheck, nil if not checked yet.
 }
 
-func newItem(label, link string, parent *Collection) (*Item, error) {
+func newItem(id, label, link string, parent *Collection) (*Item, error) {
 	itm := &Item{
-		label: label,
-		link:  link,
+		id:     id,
+		label:  label,
+		link:   link,
+		parent: parent,
 	}
 	if err := itm.init(); err != nil {
 		return nil, err
 	}
-	itm.parent = parent
 
 	return itm, nil
 }
 
-func (c *Item) init() error {
-	proto, err := (&xray3.Core{}).CreateProtocol(c.Link())
-	if err != nil {
-		return fmt.Errorf("invalid xray link: %s", err)
+func newChainItem(id, label string, hops []string, parent *Collection) (*Item, error) {
+	itm := &Item{
+		id:     id,
+		label:  label,
+		hops:   hops,
+		parent: parent,
 	}
-	if err := proto.Parse(); err != nil {
-		return fmt.Errorf("invalid xray link: %s", err)
+	if err := itm.init(); err != nil {
+		return nil, err
 	}
 
-	c.xconfigMap, err = c.xrayBaseConfigToMap(proto)
+	return itm, nil
+}
+
+func newID() string {
+	return uuid.NewString()
+}
+
+func (c *Item) init() error {
+	switch {
+	case c.IsChain():
+		return c.initChain()
+	case c.IsBalancer():
+		return c.initBalancer()
+	}
+
+	// Keep the config of the previous version if the link is invalid, Update restores its link.
+	config, err := ParseXRayConfig(c.Link())
 	if err != nil {
-		return fmt.Errorf("parse xray config to map: %s", err)
+		return err
 	}
+	c.xconfigMap = config
 
 	cl, err := vpn.NewClientWithOpts(vpn.Config{
 		Logger: slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelDebug})),
@@ -70,53 +117,239 @@ func (c *Item) init() error {
 		return fmt.Errorf("create vpn client: %v", err)
 	}
 	c.client = cl
+	c.initRecorder()
+
+	return nil
+}
 
-	c.recorder = netchart.NewRecorder(c.client)
-	c.recorder.Start()
+func (c *Item) initChain() error {
+	c.xconfigMap = map[string]string{"Protocol": ProtocolChain}
+	c.client = composite.NewClient(composite.Chain,
+		slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelDebug})))
+	c.initRecorder()
 
 	return nil
 }
 
+// Update changes item link and label, composite items ignore link. The previous version is kept in History.
 func (c *Item) Update(link, label string) error {
-	c.link = link
-	c.label = label
+	prev := Version{Time: time.Now(), Label: c.Label(), Link: c.Link()}
+	if c.IsComposite() {
+		c.setVersion(label, prev.Link)
+		c.pushHistory(prev)
+		c.parent.onChange()
+
+		return nil
+	}
+
+	c.setVersion(label, link)
 	if err := c.init(); err != nil {
+		c.setVersion(prev.Label, prev.Link)
+		return err
+	}
+	c.pushHistory(prev)
+	c.parent.onChange()
+	return nil
+}
+
+// UpdateChain replaces hops of the chained item.
+func (c *Item) UpdateChain(label string, hops ...*Item) error {
+	if !c.IsChain() {
+		return ErrNotChain
+	}
+
+	ids := make([]string, 0, len(hops))
+	for _, hop := range hops {
+		ids = append(ids, hop.ID())
+	}
+	if err := c.parent.validateChain(c.id, ids); err != nil {
 		return err
 	}
+
+	c.parent.mu.Lock()
+	c.label = label
+	c.hops = ids
+	c.parent.mu.Unlock()
 	c.parent.onChange()
+
 	return nil
 }
 
+// ID returns session persistent identifier of the item.
+func (c *Item) ID() string {
+	return c.id
+}
+
+// IsChain reports whether the item is a chain of other items.
+func (c *Item) IsChain() bool {
+	return len(c.hops) > 0
+}
+
+// HopIDs returns IDs of the chained items in dial order.
+func (c *Item) HopIDs() []string {
+	return slices.Clone(c.hops)
+}
+
+// Chain returns chained items in dial order: each hop dials through the next one.
+// Hops that no longer exist are skipped.
+func (c *Item) Chain() []*Item {
+	hops := make([]*Item, 0, len(c.hops))
+	for _, id := range c.hops {
+		if hop := c.parent.ByID(id); hop != nil {
+			hops = append(hops, hop)
+		}
+	}
+
+	return hops
+}
+
+// ChainLabels returns labels of the chained items in dial order.
+func (c *Item) ChainLabels() []string {
+	labels := make([]string, 0, len(c.hops))
+	for _, hop := range c.Chain() {
+		labels = append(labels, hop.Label())
+	}
+
+	return labels
+}
+
 func (c *Item) Active() bool {
+	c.parent.mu.RLock()
+	defer c.parent.mu.RUnlock()
+
 	return c.active
 }
 
 func (c *Item) SetActive(active bool) {
+	c.parent.mu.Lock()
 	c.active = active
+	c.parent.mu.Unlock()
 	c.parent.onChange()
 }
 
 func (c *Item) Connect() error {
+	start := time.Now()
+	err := c.connect()
+	c.parent.onConnect(c, time.Since(start), err)
+	if err == nil {
+		c.connectedAt.Store(time.Now().UnixNano())
+		c.parent.hub.Activate(c.recorder, c.onSample)
+	}
+
+	return err
+}
+
+// ConnectedAt returns time of the last successful connect, zero if not connected.
+func (c *Item) ConnectedAt() time.Time {
+	ns := c.connectedAt.Load()
+	if ns == 0 {
+		return time.Time{}
+	}
+
+	return time.Unix(0, ns)
+}
+
+func (c *Item) connect() error {
+	if c.IsChain() {
+		leaves, err := c.parent.flatten(c.id)
+		if err != nil {
+			return err
+		}
+		links := make([]string, 0, len(leaves))
+		for _, leaf := range leaves {
+			links = append(links, leaf.Link())
+		}
+
+		return c.client.Connect(composite.JoinLinks(links))
+	}
+
+	if c.IsBalancer() {
+		links, err := c.balancerLinks()
+		if err != nil {
+			return err
+		}
+
+		return c.client.Connect(composite.JoinLinks(links))
+	}
+
 	return c.client.Connect(c.Link())
 }
 
 func (c *Item) Disconnect() error {
-	return c.client.Disconnect(context.Background())
+	return c.DisconnectWith(DisconnectUser)
+}
+
+// DisconnectWith disconnects the item, reason is reported to Collection.OnDisconnect.
+func (c *Item) DisconnectWith(reason DisconnectReason) error {
+	c.parent.hub.Deactivate(c.recorder) // Record the last traffic before the client is closed.
+	c.connectedAt.Store(0)
+
+	err := c.client.Disconnect(context.Background())
+	c.parent.onDisconnect(c, reason, err)
+
+	return err
 }
 
 func (c *Item) Label() string {
+	c.parent.mu.RLock()
+	defer c.parent.mu.RUnlock()
+
 	return c.label
 }
 
 func (c *Item) Link() string {
+	c.parent.mu.RLock()
+	defer c.parent.mu.RUnlock()
+
 	return c.link
 }
 
+// setVersion changes label and link of the item.
+func (c *Item) setVersion(label, link string) {
+	c.parent.mu.Lock()
+	defer c.parent.mu.Unlock()
+
+	c.label, c.link = label, link
+}
+
+// XRayConfig returns a copy of the parsed config, it is safe to modify.
 func (c *Item) XRayConfig() map[string]string {
-	return c.xconfigMap
+	config := maps.Clone(c.xconfigMap)
+	if c.IsBalancer() {
+		return c.balancerConfig(config)
+	}
+
+	if c.IsChain() {
+		// Chain config depends on hops which can change any time.
+		config["Chain"] = strings.Join(c.ChainLabels(), " → ")
+		config["Address"] = ""
+		if leaves, err := c.parent.flatten(c.id); err == nil {
+			config["Address"] = leaves[len(leaves)-1].XRayConfig()["Address"] // Entry server.
+		}
+	}
+
+	return config
+}
+
+// ParseXRayConfig parses the link to the config returned by XRayConfig, e.g. to preview changes before Update.
+func ParseXRayConfig(link string) (map[string]string, error) {
+	proto, err := (&xray3.Core{}).CreateProtocol(link)
+	if err != nil {
+		return nil, fmt.Errorf("invalid xray link: %s", err)
+	}
+	if err := proto.Parse(); err != nil {
+		return nil, fmt.Errorf("invalid xray link: %s", err)
+	}
+
+	xmap, err := xrayBaseConfigToMap(proto)
+	if err != nil {
+		return nil, fmt.Errorf("parse xray config to map: %s", err)
+	}
+
+	return xmap, nil
 }
 
-func (c *Item) xrayBaseConfigToMap(proto xrayproto.Protocol) (map[string]string, error) {
+func xrayBaseConfigToMap(proto xrayproto.Protocol) (map[string]string, error) {
 	x := proto.ConvertToGeneralConfig()
 	fmt.Printf("xrayBaseConfigToMap: %+v\n", x)
 	xmap := map[string]string{
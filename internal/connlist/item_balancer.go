@@ -0,0 +1,122 @@
+package connlist
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/goxray/desktop/internal/composite"
+)
+
+// ProtocolBalancer is the XRayConfig "Protocol" value of balancer items.
+const ProtocolBalancer = "balancer"
+
+func newBalancerItem(id, label string, members []string, strategy string, parent *Collection) (*Item, error) {
+	itm := &Item{
+		id:       id,
+		label:    label,
+		members:  members,
+		strategy: strategy,
+		parent:   parent,
+	}
+	if err := itm.init(); err != nil {
+		return nil, err
+	}
+
+	return itm, nil
+}
+
+func (c *Item) initBalancer() error {
+	strategy, err := composite.ParseStrategy(c.strategy)
+	if err != nil {
+		return err
+	}
+	c.strategy = string(strategy)
+
+	c.xconfigMap = map[string]string{"Protocol": ProtocolBalancer, "Strategy": c.strategy}
+	c.client = composite.NewClient(composite.Balancer(strategy),
+		slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelDebug})))
+	c.initRecorder()
+
+	return nil
+}
+
+// IsBalancer reports whether the item balances connections between several other items.
+func (c *Item) IsBalancer() bool {
+	return len(c.members) > 0
+}
+
+// IsComposite reports whether the item is combined from other items and has no link of its own.
+func (c *Item) IsComposite() bool {
+	return c.IsChain() || c.IsBalancer()
+}
+
+// MemberIDs returns IDs of the balanced items.
+func (c *Item) MemberIDs() []string {
+	return slices.Clone(c.members)
+}
+
+// Strategy returns balancer strategy, empty for other items.
+func (c *Item) Strategy() string {
+	return c.strategy
+}
+
+// Members returns labels of the balanced items, missing items are labeled with their IDs.
+func (c *Item) Members() []string {
+	labels := make([]string, 0, len(c.members))
+	for _, id := range c.members {
+		if member := c.parent.ByID(id); member != nil {
+			labels = append(labels, member.Label())
+		} else {
+			labels = append(labels, id)
+		}
+	}
+
+	return labels
+}
+
+// MemberTraffic returns number of bytes for uplink and downlink of the i-th member in the current session.
+func (c *Item) MemberTraffic(i int) (read int, written int) {
+	cl, ok := c.client.(*composite.Client)
+	if !ok {
+		return 0, 0
+	}
+	stats := cl.Outbound(composite.MemberTag(i))
+
+	return int(stats.Uplink), int(stats.Downlink)
+}
+
+// MemberHealth returns the last health check result of the i-th member, observed is false if not checked yet.
+func (c *Item) MemberHealth(i int) (observed, alive bool, delay time.Duration) {
+	cl, ok := c.client.(*composite.Client)
+	if !ok {
+		return false, false, 0
+	}
+	stats := cl.Outbound(composite.MemberTag(i))
+
+	return stats.Observed, stats.Alive, stats.Delay
+}
+
+// balancerLinks resolves balancer members into links in members order.
+func (c *Item) balancerLinks() ([]string, error) {
+	links := make([]string, 0, len(c.members))
+	for _, id := range c.members {
+		member := c.parent.ByID(id)
+		if member == nil {
+			return nil, fmt.Errorf("%w: %s", ErrMemberNotFound, id)
+		}
+		links = append(links, member.Link())
+	}
+
+	return links, nil
+}
+
+// balancerConfig adds members to config, they can change any time.
+func (c *Item) balancerConfig(config map[string]string) map[string]string {
+	config["Members"] = strings.Join(c.Members(), ", ")
+
+	return config
+}
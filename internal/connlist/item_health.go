@@ -0,0 +1,74 @@
+package connlist
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/goxray/desktop/internal/composite"
+)
+
+var ErrHealthCheck = errors.New("health check failed")
+
+type healthResult struct {
+	alive bool
+	delay time.Duration
+}
+
+// HealthCheck probes composite.HealthCheckURL, as all system traffic is routed through the active connection
+// it tells whether the connection is still alive.
+func (c *Item) HealthCheck(ctx context.Context) error {
+	start := time.Now()
+	err := c.healthCheck(ctx)
+	c.health.Store(&healthResult{alive: err == nil, delay: time.Since(start)})
+
+	return err
+}
+
+// LastHealthCheck returns the result of the last HealthCheck, observed is false if not checked yet.
+func (c *Item) LastHealthCheck() (observed, alive bool, delay time.Duration) {
+	res := c.health.Load()
+	if res == nil {
+		return false, false, 0
+	}
+
+	return true, res.alive, res.delay
+}
+
+func (c *Item) healthCheck(ctx context.Context) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, composite.HealthCheckURL, nil)
+	if err != nil {
+		return fmt.Errorf("create request: %w", err)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrHealthCheck, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusNoContent {
+		return fmt.Errorf("%w: unexpected status %s", ErrHealthCheck, resp.Status)
+	}
+
+	return nil
+}
+
+// Reconnect disconnects and connects the item again, e.g. to restore routes after network change.
+func (c *Item) Reconnect() error {
+	c.reconnects.Add(1)
+	// Routes may be already gone after the network change, so it is fine for disconnect to fail.
+	if err := c.DisconnectWith(DisconnectReconnect); err != nil {
+		slog.Warn("disconnect before reconnect failed", "label", c.Label(), "err", err)
+	}
+
+	return c.Connect()
+}
+
+// Reconnects returns how many times the item was reconnected.
+func (c *Item) Reconnects() int {
+	return int(c.reconnects.Load())
+}
@@ -0,0 +1,49 @@
+package connlist
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+)
+
+// HistoryLimit is the number of previous versions kept per item.
+const HistoryLimit = 20
+
+var ErrNoVersion = errors.New("version not found")
+
+// Version is a previous link and label of an item.
+type Version struct {
+	Time  time.Time `json:"time"`
+	Label string    `json:"label"`
+	Link  string    `json:"link,omitempty"`
+}
+
+// History returns previous versions of the item, the newest first.
+func (c *Item) History() []Version {
+	return slices.Clone(c.history)
+}
+
+// SetHistory replaces previous versions of the item, versions over HistoryLimit are dropped.
+func (c *Item) SetHistory(history []Version) {
+	c.history = slices.Clone(history[:min(len(history), HistoryLimit)])
+}
+
+// Rollback restores the version i of History, the current version is kept in history.
+func (c *Item) Rollback(i int) error {
+	if i < 0 || i >= len(c.history) {
+		return fmt.Errorf("%w: %d", ErrNoVersion, i)
+	}
+	v := c.history[i]
+
+	return c.Update(v.Link, v.Label)
+}
+
+// pushHistory records the previous version if it differs from the current one.
+func (c *Item) pushHistory(prev Version) {
+	if prev.Label == c.Label() && prev.Link == c.Link() {
+		return
+	}
+	c.history = slices.Insert(c.history, 0, prev)
+	c.history = c.history[:min(len(c.history), HistoryLimit)]
+}
@@ -4,11 +4,15 @@ package connlist
 
 import (
 	"time"
+
+	"fyne.io/fyne/v2/data/binding"
+
+	"github.com/goxray/desktop/internal/netchart"
 )
 
 type NetworkRecorder interface {
-	// Start should start recording data.
-	Start()
+	// Sample should record traffic since the previous sample and return number of bytes transferred.
+	Sample(now time.Time) (read, written int)
 	// Read should return values for uplink for each previous RecordInterval.
 	// Number of values returned must match Written.
 	Read() []float64
@@ -20,6 +24,34 @@ type NetworkRecorder interface {
 	// BytesWritten should return the total number of bytes for downlink.
 	BytesWritten() int
 	RecordInterval() time.Duration
+	// Range should return uplink and downlink values covering the last span and their interval.
+	Range(span time.Duration) (read, written []float64, interval time.Duration)
+	// Unit should return the unit of recorded values.
+	Unit() netchart.Unit
+}
+
+// initRecorder creates recorder of the client traffic, it is sampled by the collection hub while the item is
+// connected. Traffic is reported to Collection.OnTraffic.
+func (c *Item) initRecorder() {
+	c.recorder = netchart.NewRecorder(c.client)
+	if c.updates == nil {
+		c.updates = binding.NewInt()
+	}
+}
+
+// Updates notifies listeners after each sample of the item traffic, it is only sampled while connected.
+func (c *Item) Updates() binding.DataItem {
+	return c.updates
+}
+
+// onSample reports traffic of each sample with traffic and notifies listeners, it is called by the hub
+// without locks held.
+func (c *Item) onSample(read, written int) {
+	if read > 0 || written > 0 {
+		c.parent.onTraffic(c, read, written)
+	}
+	n, _ := c.updates.Get()
+	_ = c.updates.Set(n + 1)
 }
 
 func (c *Item) Read() []float64 {
@@ -41,3 +73,11 @@ func (c *Item) BytesWritten() int {
 func (c *Item) RecordInterval() time.Duration {
 	return c.recorder.RecordInterval()
 }
+
+func (c *Item) Range(span time.Duration) (read, written []float64, interval time.Duration) {
+	return c.recorder.Range(span)
+}
+
+func (c *Item) Unit() netchart.Unit {
+	return c.recorder.Unit()
+}
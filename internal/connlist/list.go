@@ -2,24 +2,52 @@ package connlist
 
 import (
 	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/goxray/desktop/internal/composite"
+	"github.com/goxray/desktop/internal/netchart"
+)
+
+var (
+	ErrNotChain       = errors.New("item is not a chain")
+	ErrChainCycle     = errors.New("chain has a cycle")
+	ErrHopNotFound    = errors.New("chain hop not found")
+	ErrItemIsChainHop = errors.New("item is used in a chain")
+	ErrBalancerHop    = errors.New("balancer can not be chained")
+	ErrMemberNotFound = errors.New("balancer member not found")
+	ErrInvalidMember  = errors.New("only link items can be balanced")
+	ErrItemIsMember   = errors.New("item is used in a balancer")
 )
 
 // Collection represents a collection of items.
 // Is used to easily pass events and update the UI state in one place (on{*} methods).
+//
+// Items may be read concurrently, e.g. by the metrics endpoint, callbacks are called without locks held.
 type Collection struct {
+	mu    sync.RWMutex // Guards items, and label, link and active of each item.
 	items []*Item
+	hub   *netchart.Hub // Samples recorders of connected items.
 
-	onAdd    func(*Item)
-	onDelete func(*Item)
-	onSwap   func(*Item, *Item)
-	onChange func()
+	onAdd        func(*Item)
+	onDelete     func(*Item)
+	onSwap       func(*Item, *Item)
+	onChange     func()
+	onTraffic    func(item *Item, read, written int)
+	onConnect    func(item *Item, took time.Duration, err error)
+	onDisconnect func(item *Item, reason DisconnectReason, err error)
 }
 
 func New() *Collection {
-	items := &Collection{items: make([]*Item, 0)}
+	items := &Collection{items: make([]*Item, 0), hub: netchart.NewHub(netchart.DefaultResolutions[0].Interval)}
 	items.OnAdd(func(item *Item) {})
 	items.OnDelete(func(item *Item) {})
 	items.OnChange(func() {})
+	items.OnTraffic(func(*Item, int, int) {})
+	items.OnConnect(func(*Item, time.Duration, error) {})
+	items.OnDisconnect(func(*Item, DisconnectReason, error) {})
 
 	return items
 }
@@ -57,7 +85,26 @@ func (l *Collection) OnChange(onChange func()) {
 	l.onChange = onChange
 }
 
+// OnTraffic sets handler called periodically with bytes transferred by the item since the previous call.
+// It is called from recorder goroutines, so it must be safe for concurrent use.
+func (l *Collection) OnTraffic(onTraffic func(item *Item, read, written int)) {
+	l.onTraffic = onTraffic
+}
+
+// OnConnect sets handler called after each connection attempt with its duration and result.
+func (l *Collection) OnConnect(onConnect func(item *Item, took time.Duration, err error)) {
+	l.onConnect = onConnect
+}
+
+// OnDisconnect sets handler called after each disconnect with its reason and result.
+func (l *Collection) OnDisconnect(onDisconnect func(item *Item, reason DisconnectReason, err error)) {
+	l.onDisconnect = onDisconnect
+}
+
 func (l *Collection) All() []*Item {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
 	res := make([]*Item, 0, len(l.items))
 	for _, item := range l.items {
 		if item == nil {
@@ -69,27 +116,117 @@ func (l *Collection) All() []*Item {
 	return res
 }
 
+// ByID returns item with the provided ID or nil if not found.
+func (l *Collection) ByID(id string) *Item {
+	for _, item := range l.All() {
+		if item.ID() == id {
+			return item
+		}
+	}
+
+	return nil
+}
+
 func (l *Collection) AddItem(label, link string) error {
-	item, err := newItem(label, link, l)
+	_, err := l.LoadItem(newID(), label, link, nil, nil)
+
+	return err
+}
+
+// AddChain creates new item where hops[i] dials through hops[i+1], the last hop is the entry server.
+func (l *Collection) AddChain(label string, hops ...*Item) error {
+	id := newID()
+	ids := make([]string, 0, len(hops))
+	for _, hop := range hops {
+		ids = append(ids, hop.ID())
+	}
+	if err := l.validateChain(id, ids); err != nil {
+		return err
+	}
+	_, err := l.LoadItem(id, label, "", ids, nil)
+
+	return err
+}
+
+// LoadItem adds previously saved item with its previous versions and returns it, it is a chain if hops are not empty.
+// A new ID is generated if id is empty.
+//
+// Chain hops are not validated here as they may be loaded later, broken chains fail to connect instead.
+func (l *Collection) LoadItem(id, label, link string, hops []string, history []Version) (*Item, error) {
+	if id == "" {
+		id = newID()
+	}
+
+	var item *Item
+	var err error
+	if len(hops) > 0 {
+		item, err = newChainItem(id, label, hops, l)
+	} else {
+		item, err = newItem(id, label, link, l)
+	}
 	if err != nil {
+		return nil, err
+	}
+	item.SetHistory(history) // Before add, so handlers of the added item see the history.
+	l.add(item)
+
+	return item, nil
+}
+
+// AddBalancer creates new item balancing connections between members with the provided strategy.
+func (l *Collection) AddBalancer(label, strategy string, members ...*Item) error {
+	ids := make([]string, 0, len(members))
+	for _, member := range members {
+		ids = append(ids, member.ID())
+	}
+	if err := l.validateBalancer(ids); err != nil {
 		return err
 	}
 
-	l.items = append(l.items, item)
-	l.onAdd(item)
+	_, err := l.LoadBalancer(newID(), label, ids, strategy, nil)
 
-	return nil
+	return err
 }
 
-func (l *Collection) RemoveItem(del *Item) {
-	for i, item := range l.items {
-		if item == del {
-			l.remove(i)
+// LoadBalancer adds previously saved balancer item and returns it, members are not validated (see LoadItem).
+func (l *Collection) LoadBalancer(id, label string, members []string, strategy string, history []Version) (*Item, error) {
+	if id == "" {
+		id = newID()
+	}
+
+	item, err := newBalancerItem(id, label, members, strategy, l)
+	if err != nil {
+		return nil, err
+	}
+	item.SetHistory(history)
+	l.add(item)
+
+	return item, nil
+}
+
+// Dependents returns chains and balancers that use the item.
+func (l *Collection) Dependents(itm *Item) []*Item {
+	res := make([]*Item, 0)
+	for _, item := range l.All() {
+		if slices.Contains(item.hops, itm.ID()) || slices.Contains(item.members, itm.ID()) {
+			res = append(res, item)
 		}
 	}
+
+	return res
+}
+
+func (l *Collection) RemoveItem(del *Item) {
+	l.mu.RLock()
+	i := slices.Index(l.items, del)
+	l.mu.RUnlock()
+	if i >= 0 {
+		l.remove(i)
+	}
 }
 
 func (l *Collection) SwapItems(itm1 *Item, itm2 *Item) error {
+	l.mu.Lock()
 	id1, id2 := -1, -1
 	for i, item := range l.items {
 		if item == itm1 {
@@ -100,21 +237,113 @@ func (l *Collection) SwapItems(itm1 *Item, itm2 *Item) error {
 		}
 	}
 	if id1 == -1 || id2 == -1 {
+		l.mu.Unlock()
 		return errors.New("cannot swap items")
 	}
 
 	l.items[id1], l.items[id2] = l.items[id2], l.items[id1]
+	l.mu.Unlock()
 	l.onSwap(itm1, itm2)
 
 	return nil
 }
 
+func (l *Collection) add(item *Item) {
+	l.mu.Lock()
+	l.items = append(l.items, item)
+	l.mu.Unlock()
+	l.onAdd(item)
+}
+
+// validateBalancer checks that all members exist and have links of their own.
+func (l *Collection) validateBalancer(members []string) error {
+	if len(members) < 2 {
+		return composite.ErrBalancerTooSmall
+	}
+
+	for _, id := range members {
+		member := l.ByID(id)
+		if member == nil {
+			return fmt.Errorf("%w: %s", ErrMemberNotFound, id)
+		}
+		if member.IsComposite() {
+			return fmt.Errorf("%w: %q", ErrInvalidMember, member.Label())
+		}
+	}
+
+	return nil
+}
+
+// validateChain checks that all hops exist and the chain with ID id does not dial through itself.
+func (l *Collection) validateChain(id string, hops []string) error {
+	if len(hops) < 2 {
+		return composite.ErrChainTooShort
+	}
+
+	visiting := map[string]bool{id: true}
+	for _, hop := range hops {
+		if _, err := l.walk(hop, map[string][]string{id: hops}, visiting); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// flatten resolves item with ID id into link items in dial order.
+func (l *Collection) flatten(id string) ([]*Item, error) {
+	return l.walk(id, nil, map[string]bool{})
+}
+
+// walk resolves item into link items recursively, override replaces hops of the items by ID.
+func (l *Collection) walk(id string, override map[string][]string, visiting map[string]bool) ([]*Item, error) {
+	item := l.ByID(id)
+	if item == nil {
+		return nil, fmt.Errorf("%w: %s", ErrHopNotFound, id)
+	}
+
+	if item.IsBalancer() {
+		return nil, fmt.Errorf("%w: %q", ErrBalancerHop, item.Label())
+	}
+
+	hops, ok := override[id]
+	if !ok {
+		hops = item.hops
+	}
+	if len(hops) == 0 {
+		return []*Item{item}, nil
+	}
+
+	if visiting[id] {
+		return nil, fmt.Errorf("%w: %q", ErrChainCycle, item.Label())
+	}
+	visiting[id] = true
+	defer delete(visiting, id)
+
+	leaves := make([]*Item, 0, len(hops))
+	for _, hop := range hops {
+		sub, err := l.walk(hop, override, visiting)
+		if err != nil {
+			return nil, err
+		}
+		leaves = append(leaves, sub...)
+	}
+
+	return leaves, nil
+}
+
 func (l *Collection) remove(i int) {
-	if len(l.items) < i {
+	l.mu.Lock()
+	if i >= len(l.items) || l.items[i] == nil {
+		l.mu.Unlock()
 		return
 	}
+	item := l.items[i]
+	l.mu.Unlock()
 
-	l.onDelete(l.items[i])
+	l.onDelete(item)
+	l.mu.Lock()
 	l.items[i] = nil
+	l.mu.Unlock()
 	l.onChange()
 }
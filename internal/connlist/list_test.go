@@ -1,12 +1,15 @@
 package connlist
 
 import (
+	"context"
+	"errors"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/require"
 	"go.uber.org/mock/gomock"
 
+	"github.com/goxray/desktop/internal/composite"
 	"github.com/goxray/desktop/internal/connlist/mocks"
 )
 
@@ -43,7 +46,7 @@ func TestList_Recorder(t *testing.T) {
 	rec.EXPECT().Read().Return([]float64{1, 2, 3})
 	rec.EXPECT().Written().Return([]float64{3, 2, 1})
 
-	c, err := newItem("test", sampleVlessLink, New())
+	c, err := newItem(newID(), "test", sampleVlessLink, New())
 	require.NoError(t, err)
 	c.recorder = rec
 
@@ -52,4 +55,154 @@ func TestList_Recorder(t *testing.T) {
 	require.Equal(t, 4321, c.BytesWritten())
 	require.Equal(t, []float64{1, 2, 3}, c.Read())
 	require.Equal(t, []float64{3, 2, 1}, c.Written())
+	require.NotNil(t, c.Updates())
+}
+
+func TestList_Chain(t *testing.T) {
+	c := New()
+	require.NoError(t, c.AddItem("A", sampleVlessLink))
+	require.NoError(t, c.AddItem("B", sampleVlessLink))
+	require.NoError(t, c.AddItem("C", sampleVlessLink))
+	a, b, cc := c.All()[0], c.All()[1], c.All()[2]
+
+	require.ErrorIs(t, c.AddChain("short", a), composite.ErrChainTooShort)
+	require.NoError(t, c.AddChain("A via B", a, b))
+	chain := c.All()[3]
+	require.True(t, chain.IsChain())
+	require.False(t, a.IsChain())
+	require.Equal(t, []string{a.ID(), b.ID()}, chain.HopIDs())
+	require.Equal(t, ProtocolChain, chain.XRayConfig()["Protocol"])
+	require.Equal(t, "A → B", chain.XRayConfig()["Chain"])
+	require.Equal(t, []*Item{chain}, c.Dependents(b))
+	require.Empty(t, c.Dependents(cc))
+
+	// Chains can be nested and are flattened into links.
+	require.NoError(t, c.AddChain("nested", chain, cc))
+	nested := c.All()[4]
+	leaves, err := c.flatten(nested.ID())
+	require.NoError(t, err)
+	require.Equal(t, []*Item{a, b, cc}, leaves)
+
+	// Chain must not dial through itself.
+	require.ErrorIs(t, chain.UpdateChain("cycle", a, nested), ErrChainCycle)
+	require.ErrorIs(t, nested.UpdateChain("self", nested, a), ErrChainCycle)
+	require.ErrorIs(t, a.UpdateChain("plain", b, cc), ErrNotChain)
+	require.NoError(t, chain.UpdateChain("A via C", a, cc))
+	require.Equal(t, "A → C", chain.XRayConfig()["Chain"])
+
+	// Broken chains are detected when loaded from a savefile.
+	_, err = c.LoadItem("", "broken", "", []string{a.ID(), "missing"}, nil)
+	require.NoError(t, err)
+	_, err = c.flatten(c.All()[5].ID())
+	require.ErrorIs(t, err, ErrHopNotFound)
+}
+
+func TestList_Balancer(t *testing.T) {
+	c := New()
+	require.NoError(t, c.AddItem("A", sampleVlessLink))
+	require.NoError(t, c.AddItem("B", sampleVlessLink))
+	a, b := c.All()[0], c.All()[1]
+	require.NoError(t, c.AddChain("A via B", a, b))
+	chain := c.All()[2]
+
+	require.ErrorIs(t, c.AddBalancer("small", "random", a), composite.ErrBalancerTooSmall)
+	require.ErrorIs(t, c.AddBalancer("chained", "random", a, chain), ErrInvalidMember)
+	require.ErrorIs(t, c.AddBalancer("unknown", "fastest", a, b), composite.ErrUnknownStrategy)
+
+	require.NoError(t, c.AddBalancer("A or B", "", a, b))
+	balancer := c.All()[3]
+	require.True(t, balancer.IsBalancer())
+	require.True(t, balancer.IsComposite())
+	require.False(t, a.IsComposite())
+	require.Equal(t, string(composite.StrategyRandom), balancer.Strategy())
+	require.Equal(t, []string{a.ID(), b.ID()}, balancer.MemberIDs())
+	require.Equal(t, []string{"A", "B"}, balancer.Members())
+	require.Equal(t, ProtocolBalancer, balancer.XRayConfig()["Protocol"])
+	require.Equal(t, "A, B", balancer.XRayConfig()["Members"])
+	require.ElementsMatch(t, []*Item{chain, balancer}, c.Dependents(a))
+
+	// Not connected balancer has no traffic.
+	read, written := balancer.MemberTraffic(0)
+	require.Zero(t, read+written)
+
+	// Balancers can not be chained.
+	require.ErrorIs(t, c.AddChain("balanced chain", a, balancer), ErrBalancerHop)
+}
+
+type fakeClient struct {
+	connectErr error
+}
+
+func (f *fakeClient) Connect(string) error             { return f.connectErr }
+func (f *fakeClient) Disconnect(context.Context) error { return nil }
+func (f *fakeClient) BytesRead() int                   { return 0 }
+func (f *fakeClient) BytesWritten() int                { return 0 }
+
+func TestList_ConnectEvents(t *testing.T) {
+	l := New()
+	var connects int
+	var connectErr error
+	l.OnConnect(func(_ *Item, _ time.Duration, err error) {
+		connects++
+		connectErr = err
+	})
+	var reasons []DisconnectReason
+	l.OnDisconnect(func(_ *Item, reason DisconnectReason, err error) {
+		reasons = append(reasons, reason)
+		require.NoError(t, err)
+	})
+
+	c, err := newItem(newID(), "test", sampleVlessLink, l)
+	require.NoError(t, err)
+	client := &fakeClient{connectErr: errors.New("failed")}
+	c.client = client
+
+	require.Error(t, c.Connect())
+	require.Equal(t, 1, connects)
+	require.Error(t, connectErr)
+	require.True(t, c.ConnectedAt().IsZero())
+
+	client.connectErr = nil
+	require.NoError(t, c.Connect())
+	require.Equal(t, 2, connects)
+	require.NoError(t, connectErr)
+	require.WithinDuration(t, time.Now(), c.ConnectedAt(), time.Second)
+
+	require.NoError(t, c.Reconnect())
+	require.Equal(t, []DisconnectReason{DisconnectReconnect}, reasons)
+	require.Equal(t, 3, connects)
+
+	require.NoError(t, c.Disconnect())
+	require.Equal(t, []DisconnectReason{DisconnectReconnect, DisconnectUser}, reasons)
+	require.True(t, c.ConnectedAt().IsZero())
+}
+
+func TestItem_History(t *testing.T) {
+	c := New()
+	require.NoError(t, c.AddItem("A", sampleVlessLink))
+	item := c.All()[0]
+	require.Empty(t, item.History())
+
+	const otherLink = "vless://h1px412i-9138-s9m5-9b86-d47d74dd8541@127.0.0.2:8080?type=tcp#Other"
+	require.NoError(t, item.Update(otherLink, "B"))
+	require.NoError(t, item.Update(otherLink, "B")) // Unchanged, not recorded.
+	require.Error(t, item.Update("invalid", "C"))   // Failed, not recorded.
+	history := item.History()
+	require.Len(t, history, 1)
+	require.Equal(t, "A", history[0].Label)
+	require.Equal(t, sampleVlessLink, history[0].Link)
+
+	require.NoError(t, item.Rollback(0))
+	require.Equal(t, "A", item.Label())
+	require.Equal(t, sampleVlessLink, item.Link())
+	require.Equal(t, "B", item.History()[0].Label)
+	require.ErrorIs(t, item.Rollback(2), ErrNoVersion)
+
+	for i := range HistoryLimit + 5 {
+		require.NoError(t, item.Update(sampleVlessLink, string(rune('a'+i))))
+	}
+	require.Len(t, item.History(), HistoryLimit)
+
+	item.SetHistory(make([]Version, HistoryLimit+1))
+	require.Len(t, item.History(), HistoryLimit)
 }
@@ -13,6 +13,7 @@ import (
 	reflect "reflect"
 	time "time"
 
+	netchart "github.com/goxray/desktop/internal/netchart"
 	gomock "go.uber.org/mock/gomock"
 )
 
@@ -116,6 +117,46 @@ func (c *MockNetworkRecorderBytesWrittenCall) DoAndReturn(f func() int) *MockNet
 	return c
 }
 
+// Range mocks base method.
+func (m *MockNetworkRecorder) Range(span time.Duration) ([]float64, []float64, time.Duration) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Range", span)
+	ret0, _ := ret[0].([]float64)
+	ret1, _ := ret[1].([]float64)
+	ret2, _ := ret[2].(time.Duration)
+	return ret0, ret1, ret2
+}
+
+// Range indicates an expected call of Range.
+func (mr *MockNetworkRecorderMockRecorder) Range(span any) *MockNetworkRecorderRangeCall {
+	mr.mock.ctrl.T.Helper()
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Range", reflect.TypeOf((*MockNetworkRecorder)(nil).Range), span)
+	return &MockNetworkRecorderRangeCall{Call: call}
+}
+
+// MockNetworkRecorderRangeCall wrap *gomock.Call
+type MockNetworkRecorderRangeCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *MockNetworkRecorderRangeCall) Return(read, written []float64, interval time.Duration) *MockNetworkRecorderRangeCall {
+	c.Call = c.Call.Return(read, written, interval)
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *MockNetworkRecorderRangeCall) Do(f func(time.Duration) ([]float64, []float64, time.Duration)) *MockNetworkRecorderRangeCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *MockNetworkRecorderRangeCall) DoAndReturn(f func(time.Duration) ([]float64, []float64, time.Duration)) *MockNetworkRecorderRangeCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
 // Read mocks base method.
 func (m *MockNetworkRecorder) Read() []float64 {
 	m.ctrl.T.Helper()
@@ -192,38 +233,79 @@ func (c *MockNetworkRecorderRecordIntervalCall) DoAndReturn(f func() time.Durati
 	return c
 }
 
-// Start mocks base method.
-func (m *MockNetworkRecorder) Start() {
+// Sample mocks base method.
+func (m *MockNetworkRecorder) Sample(now time.Time) (int, int) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Sample", now)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(int)
+	return ret0, ret1
+}
+
+// Sample indicates an expected call of Sample.
+func (mr *MockNetworkRecorderMockRecorder) Sample(now any) *MockNetworkRecorderSampleCall {
+	mr.mock.ctrl.T.Helper()
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Sample", reflect.TypeOf((*MockNetworkRecorder)(nil).Sample), now)
+	return &MockNetworkRecorderSampleCall{Call: call}
+}
+
+// MockNetworkRecorderSampleCall wrap *gomock.Call
+type MockNetworkRecorderSampleCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *MockNetworkRecorderSampleCall) Return(read, written int) *MockNetworkRecorderSampleCall {
+	c.Call = c.Call.Return(read, written)
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *MockNetworkRecorderSampleCall) Do(f func(time.Time) (int, int)) *MockNetworkRecorderSampleCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *MockNetworkRecorderSampleCall) DoAndReturn(f func(time.Time) (int, int)) *MockNetworkRecorderSampleCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
+// Unit mocks base method.
+func (m *MockNetworkRecorder) Unit() netchart.Unit {
 	m.ctrl.T.Helper()
-	m.ctrl.Call(m, "Start")
+	ret := m.ctrl.Call(m, "Unit")
+	ret0, _ := ret[0].(netchart.Unit)
+	return ret0
 }
 
-// Start indicates an expected call of Start.
-func (mr *MockNetworkRecorderMockRecorder) Start() *MockNetworkRecorderStartCall {
+// Unit indicates an expected call of Unit.
+func (mr *MockNetworkRecorderMockRecorder) Unit() *MockNetworkRecorderUnitCall {
 	mr.mock.ctrl.T.Helper()
-	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Start", reflect.TypeOf((*MockNetworkRecorder)(nil).Start))
-	return &MockNetworkRecorderStartCall{Call: call}
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Unit", reflect.TypeOf((*MockNetworkRecorder)(nil).Unit))
+	return &MockNetworkRecorderUnitCall{Call: call}
 }
 
-// MockNetworkRecorderStartCall wrap *gomock.Call
-type MockNetworkRecorderStartCall struct {
+// MockNetworkRecorderUnitCall wrap *gomock.Call
+type MockNetworkRecorderUnitCall struct {
 	*gomock.Call
 }
 
 // Return rewrite *gomock.Call.Return
-func (c *MockNetworkRecorderStartCall) Return() *MockNetworkRecorderStartCall {
-	c.Call = c.Call.Return()
+func (c *MockNetworkRecorderUnitCall) Return(arg0 netchart.Unit) *MockNetworkRecorderUnitCall {
+	c.Call = c.Call.Return(arg0)
 	return c
 }
 
 // Do rewrite *gomock.Call.Do
-func (c *MockNetworkRecorderStartCall) Do(f func()) *MockNetworkRecorderStartCall {
+func (c *MockNetworkRecorderUnitCall) Do(f func() netchart.Unit) *MockNetworkRecorderUnitCall {
 	c.Call = c.Call.Do(f)
 	return c
 }
 
 // DoAndReturn rewrite *gomock.Call.DoAndReturn
-func (c *MockNetworkRecorderStartCall) DoAndReturn(f func()) *MockNetworkRecorderStartCall {
+func (c *MockNetworkRecorderUnitCall) DoAndReturn(f func() netchart.Unit) *MockNetworkRecorderUnitCall {
 	c.Call = c.Call.DoAndReturn(f)
 	return c
 }
@@ -0,0 +1,62 @@
+/*
+Package events keeps a bounded log of recent connection events.
+*/
+package events
+
+import (
+	"sync"
+	"time"
+)
+
+// Kind of connection event.
+type Kind string
+
+const (
+	KindConnected    Kind = "connected"
+	KindDisconnected Kind = "disconnected"
+	KindFailed       Kind = "failed"
+)
+
+type Event struct {
+	Time   time.Time
+	ItemID string
+	Label  string
+	Kind   Kind
+	Err    string // Error of failed events.
+}
+
+// Log keeps up to limit latest events, it is safe for concurrent use.
+type Log struct {
+	limit int
+
+	mu     sync.Mutex
+	events []Event // From the oldest.
+}
+
+func NewLog(limit int) *Log {
+	return &Log{limit: max(limit, 1)}
+}
+
+// Add appends e to the log, dropping the oldest event if the log is full.
+func (l *Log) Add(e Event) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if len(l.events) == l.limit {
+		l.events = append(l.events[:0], l.events[1:]...)
+	}
+	l.events = append(l.events, e)
+}
+
+// Recent returns up to n latest events, the newest first.
+func (l *Log) Recent(n int) []Event {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	res := make([]Event, 0, min(n, len(l.events)))
+	for i := len(l.events) - 1; i >= 0 && len(res) < n; i-- {
+		res = append(res, l.events[i])
+	}
+
+	return res
+}
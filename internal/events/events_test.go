@@ -0,0 +1,28 @@
+package events
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLog(t *testing.T) {
+	l := NewLog(3)
+	require.Empty(t, l.Recent(10))
+
+	for i := range 5 {
+		l.Add(Event{ItemID: strconv.Itoa(i), Kind: KindConnected})
+	}
+
+	ids := func(events []Event) []string {
+		res := make([]string, 0, len(events))
+		for _, e := range events {
+			res = append(res, e.ItemID)
+		}
+		return res
+	}
+	require.Equal(t, []string{"4", "3", "2"}, ids(l.Recent(10)))
+	require.Equal(t, []string{"4", "3"}, ids(l.Recent(2)))
+	require.Empty(t, l.Recent(0))
+}
@@ -0,0 +1,192 @@
+/*
+Package metrics exposes connection statistics in the Prometheus text format.
+*/
+package metrics
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Path the metrics are served at.
+const Path = "/metrics"
+
+// ConnectBuckets are upper bounds of connect duration histogram buckets in seconds.
+var ConnectBuckets = []float64{0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30}
+
+type Item interface {
+	ID() string
+	Label() string
+	Active() bool
+	// BytesRead should return the number of bytes for uplink of the current connection.
+	BytesRead() int
+	// BytesWritten should return the number of bytes for downlink of the current connection.
+	BytesWritten() int
+	Reconnects() int
+	// LastHealthCheck should return the last health check result, observed is false if not checked yet.
+	LastHealthCheck() (observed, alive bool, delay time.Duration)
+}
+
+// Histogram counts observations in cumulative buckets.
+type Histogram struct {
+	buckets []float64
+	counts  []uint64 // Per bucket, not cumulative.
+	sum     float64
+	count   uint64
+}
+
+func NewHistogram(buckets []float64) *Histogram {
+	return &Histogram{buckets: buckets, counts: make([]uint64, len(buckets))}
+}
+
+func (h *Histogram) Observe(v float64) {
+	for i, b := range h.buckets {
+		if v <= b {
+			h.counts[i]++
+			break
+		}
+	}
+	h.sum += v
+	h.count++
+}
+
+// connectStats are connection attempts of an item.
+type connectStats struct {
+	latency  *Histogram // Of successful attempts.
+	failures uint64
+}
+
+// Collector collects metrics of items on every scrape and keeps connection attempts between scrapes.
+type Collector struct {
+	items func() []Item
+
+	mu       sync.Mutex
+	connects map[string]*connectStats
+}
+
+// New creates a Collector of items returned by items, it is called on every scrape.
+func New(items func() []Item) *Collector {
+	return &Collector{items: items, connects: map[string]*connectStats{}}
+}
+
+// ObserveConnect records a connection attempt of the item.
+func (c *Collector) ObserveConnect(itemID string, took time.Duration, err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	stats, ok := c.connects[itemID]
+	if !ok {
+		stats = &connectStats{latency: NewHistogram(ConnectBuckets)}
+		c.connects[itemID] = stats
+	}
+	if err != nil {
+		stats.failures++
+		return
+	}
+	stats.latency.Observe(took.Seconds())
+}
+
+func (c *Collector) ServeHTTP(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	if err := c.Write(w); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+// Write writes metrics of all items in the Prometheus text format.
+func (c *Collector) Write(w io.Writer) error {
+	items := c.items()
+	bw := bufio.NewWriter(w)
+
+	family := func(name, typ, help string, write func(it Item, labels string)) {
+		fmt.Fprintf(bw, "# HELP %s %s\n# TYPE %s %s\n", name, help, name, typ)
+		for _, it := range items {
+			write(it, itemLabels(it))
+		}
+	}
+
+	family("goxray_item_bytes_total", "counter", "Bytes transferred by the connection since it was connected, reset on reconnect.",
+		func(it Item, labels string) {
+			fmt.Fprintf(bw, "goxray_item_bytes_total{%s,direction=\"up\"} %d\n", labels, it.BytesRead())
+			fmt.Fprintf(bw, "goxray_item_bytes_total{%s,direction=\"down\"} %d\n", labels, it.BytesWritten())
+		})
+	family("goxray_item_connected", "gauge", "Whether the connection is active.",
+		func(it Item, labels string) {
+			fmt.Fprintf(bw, "goxray_item_connected{%s} %d\n", labels, boolToInt(it.Active()))
+		})
+	family("goxray_item_reconnects_total", "counter", "Automatic reconnects of the connection.",
+		func(it Item, labels string) {
+			fmt.Fprintf(bw, "goxray_item_reconnects_total{%s} %d\n", labels, it.Reconnects())
+		})
+	family("goxray_item_health_check_up", "gauge", "Result of the last health check, absent if not checked yet.",
+		func(it Item, labels string) {
+			if observed, alive, _ := it.LastHealthCheck(); observed {
+				fmt.Fprintf(bw, "goxray_item_health_check_up{%s} %d\n", labels, boolToInt(alive))
+			}
+		})
+	family("goxray_item_health_check_duration_seconds", "gauge", "Duration of the last health check.",
+		func(it Item, labels string) {
+			if observed, _, delay := it.LastHealthCheck(); observed {
+				fmt.Fprintf(bw, "goxray_item_health_check_duration_seconds{%s} %s\n", labels, formatFloat(delay.Seconds()))
+			}
+		})
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	family("goxray_item_connect_failures_total", "counter", "Failed connection attempts.",
+		func(it Item, labels string) {
+			if stats, ok := c.connects[it.ID()]; ok {
+				fmt.Fprintf(bw, "goxray_item_connect_failures_total{%s} %d\n", labels, stats.failures)
+			}
+		})
+	family("goxray_item_connect_duration_seconds", "histogram", "Duration of successful connection attempts.",
+		func(it Item, labels string) {
+			stats, ok := c.connects[it.ID()]
+			if !ok {
+				return
+			}
+			h := stats.latency
+			var cumulative uint64
+			for i, b := range h.buckets {
+				cumulative += h.counts[i]
+				fmt.Fprintf(bw, "goxray_item_connect_duration_seconds_bucket{%s,le=\"%s\"} %d\n", labels, formatFloat(b), cumulative)
+			}
+			fmt.Fprintf(bw, "goxray_item_connect_duration_seconds_bucket{%s,le=\"+Inf\"} %d\n", labels, h.count)
+			fmt.Fprintf(bw, "goxray_item_connect_duration_seconds_sum{%s} %s\n", labels, formatFloat(h.sum))
+			fmt.Fprintf(bw, "goxray_item_connect_duration_seconds_count{%s} %d\n", labels, h.count)
+		})
+
+	if err := bw.Flush(); err != nil {
+		return fmt.Errorf("write metrics: %w", err)
+	}
+
+	return nil
+}
+
+func itemLabels(it Item) string {
+	return fmt.Sprintf("id=\"%s\",item=\"%s\"", escapeLabel(it.ID()), escapeLabel(it.Label()))
+}
+
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+func escapeLabel(v string) string {
+	return labelEscaper.Replace(v)
+}
+
+func formatFloat(v float64) string {
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
+
+func boolToInt(b bool) int {
+	if b {
+		return 1
+	}
+
+	return 0
+}
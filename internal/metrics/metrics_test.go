@@ -0,0 +1,107 @@
+package metrics
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type fakeItem struct {
+	id, label   string
+	active      bool
+	read, write int
+	reconnects  int
+	observed    bool
+	alive       bool
+	delay       time.Duration
+}
+
+func (f *fakeItem) ID() string        { return f.id }
+func (f *fakeItem) Label() string     { return f.label }
+func (f *fakeItem) Active() bool      { return f.active }
+func (f *fakeItem) BytesRead() int    { return f.read }
+func (f *fakeItem) BytesWritten() int { return f.write }
+func (f *fakeItem) Reconnects() int   { return f.reconnects }
+func (f *fakeItem) LastHealthCheck() (bool, bool, time.Duration) {
+	return f.observed, f.alive, f.delay
+}
+
+func TestCollector(t *testing.T) {
+	items := []Item{
+		&fakeItem{id: "a", label: `my "vpn"`, active: true, read: 10, write: 20, reconnects: 2,
+			observed: true, alive: true, delay: 150 * time.Millisecond},
+		&fakeItem{id: "b", label: "other"},
+	}
+	c := New(func() []Item { return items })
+	c.ObserveConnect("a", 300*time.Millisecond, nil)
+	c.ObserveConnect("a", 2*time.Second, nil)
+	c.ObserveConnect("a", time.Second, errors.New("failed"))
+
+	buf := &strings.Builder{}
+	require.NoError(t, c.Write(buf))
+	out := buf.String()
+
+	for _, line := range []string{
+		"# TYPE goxray_item_bytes_total counter",
+		`goxray_item_bytes_total{id="a",item="my \"vpn\"",direction="up"} 10`,
+		`goxray_item_bytes_total{id="a",item="my \"vpn\"",direction="down"} 20`,
+		`goxray_item_bytes_total{id="b",item="other",direction="up"} 0`,
+		`goxray_item_connected{id="a",item="my \"vpn\""} 1`,
+		`goxray_item_connected{id="b",item="other"} 0`,
+		`goxray_item_reconnects_total{id="a",item="my \"vpn\""} 2`,
+		`goxray_item_health_check_up{id="a",item="my \"vpn\""} 1`,
+		`goxray_item_health_check_duration_seconds{id="a",item="my \"vpn\""} 0.15`,
+		`goxray_item_connect_failures_total{id="a",item="my \"vpn\""} 1`,
+		`goxray_item_connect_duration_seconds_bucket{id="a",item="my \"vpn\"",le="0.25"} 0`,
+		`goxray_item_connect_duration_seconds_bucket{id="a",item="my \"vpn\"",le="0.5"} 1`,
+		`goxray_item_connect_duration_seconds_bucket{id="a",item="my \"vpn\"",le="2.5"} 2`,
+		`goxray_item_connect_duration_seconds_bucket{id="a",item="my \"vpn\"",le="+Inf"} 2`,
+		`goxray_item_connect_duration_seconds_sum{id="a",item="my \"vpn\""} 2.3`,
+		`goxray_item_connect_duration_seconds_count{id="a",item="my \"vpn\""} 2`,
+	} {
+		require.Contains(t, out, line+"\n")
+	}
+	require.NotContains(t, out, `goxray_item_health_check_up{id="b"`, "not checked yet")
+	require.NotContains(t, out, `goxray_item_connect_duration_seconds_count{id="b"`, "never connected")
+}
+
+func TestServer(t *testing.T) {
+	s := NewServer(New(func() []Item { return []Item{&fakeItem{id: "a", label: "a"}} }))
+	require.ErrorIs(t, s.Start("0.0.0.0:0"), ErrNotLoopback)
+	require.ErrorIs(t, s.Start("192.168.1.1:9477"), ErrNotLoopback)
+
+	// Find a free port.
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	addr := ln.Addr().String()
+	require.NoError(t, ln.Close())
+
+	require.NoError(t, s.Start(addr))
+	require.True(t, s.Running())
+
+	resp, err := http.Get(fmt.Sprintf("http://%s%s", addr, Path))
+	require.NoError(t, err)
+	body, _ := io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	require.Contains(t, resp.Header.Get("Content-Type"), "text/plain")
+	require.Contains(t, string(body), `goxray_item_connected{id="a",item="a"} 0`)
+
+	resp, err = http.Get(fmt.Sprintf("http://%s/other", addr))
+	require.NoError(t, err)
+	_ = resp.Body.Close()
+	require.Equal(t, http.StatusNotFound, resp.StatusCode)
+
+	require.NoError(t, s.Stop())
+	require.False(t, s.Running())
+	require.NoError(t, s.Stop())
+	_, err = http.Get(fmt.Sprintf("http://%s%s", addr, Path))
+	require.Error(t, err)
+}
@@ -0,0 +1,87 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+)
+
+const shutdownTimeout = 3 * time.Second
+
+var ErrNotLoopback = errors.New("metrics must listen on a loopback address")
+
+// Server serves metrics at Path, it can be started and stopped any number of times.
+type Server struct {
+	handler http.Handler
+
+	mu  sync.Mutex
+	srv *http.Server
+}
+
+func NewServer(handler http.Handler) *Server {
+	return &Server{handler: handler}
+}
+
+// Start starts serving on addr in background, addr must be a loopback address, e.g. 127.0.0.1:9477.
+func (s *Server) Start(addr string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.srv != nil {
+		return nil
+	}
+
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return fmt.Errorf("parse metrics address: %w", err)
+	}
+	if ip := net.ParseIP(host); host != "localhost" && (ip == nil || !ip.IsLoopback()) {
+		return fmt.Errorf("%w: %s", ErrNotLoopback, addr)
+	}
+
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		return fmt.Errorf("listen metrics: %w", err)
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle("GET "+Path, s.handler)
+	s.srv = &http.Server{Handler: mux, ReadHeaderTimeout: 5 * time.Second}
+	go func(srv *http.Server) {
+		if err := srv.Serve(ln); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			slog.Error("serve metrics", "err", err)
+		}
+	}(s.srv)
+
+	return nil
+}
+
+// Stop stops serving, it does nothing if not started.
+func (s *Server) Stop() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.srv == nil {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+	err := s.srv.Shutdown(ctx)
+	s.srv = nil
+	if err != nil {
+		return fmt.Errorf("stop metrics: %w", err)
+	}
+
+	return nil
+}
+
+func (s *Server) Running() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.srv != nil
+}
@@ -0,0 +1,113 @@
+package netchart
+
+import (
+	"bufio"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"image/color"
+	"image/png"
+	"io"
+	"strconv"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/driver/software"
+)
+
+// ImageFormat of exported charts.
+type ImageFormat string
+
+const (
+	ImagePNG ImageFormat = "png"
+	ImageSVG ImageFormat = "svg"
+)
+
+var ErrUnknownImageFormat = errors.New("unknown image format")
+
+// Export renders the chart of the given size off-screen and writes it in format, the chart on screen is not affected.
+func (c *Chart) Export(w io.Writer, size fyne.Size, format ImageFormat) error {
+	switch format {
+	case ImagePNG:
+		return c.WritePNG(w, size)
+	case ImageSVG:
+		return c.WriteSVG(w, size)
+	default:
+		return fmt.Errorf("%w: %q", ErrUnknownImageFormat, format)
+	}
+}
+
+// WritePNG renders the chart of the given size off-screen and writes it as PNG.
+func (c *Chart) WritePNG(w io.Writer, size fyne.Size) error {
+	cnv := software.NewCanvas()
+	cnv.SetPadded(false)
+	cnv.SetContent(c.offscreen())
+	cnv.Resize(size)
+
+	if err := png.Encode(w, cnv.Capture()); err != nil {
+		return fmt.Errorf("encode chart png: %w", err)
+	}
+
+	return nil
+}
+
+// WriteSVG writes the chart of the given size as SVG.
+func (c *Chart) WriteSVG(w io.Writer, size fyne.Size) error {
+	bw := bufio.NewWriter(w)
+	fmt.Fprintf(bw, `<svg xmlns="http://www.w3.org/2000/svg" width="%s" height="%s" viewBox="0 0 %[1]s %[2]s">`+"\n",
+		svgNumber(size.Width), svgNumber(size.Height))
+
+	for _, o := range c.offscreen().draw(size) {
+		switch o := o.(type) {
+		case *canvas.Rectangle:
+			fmt.Fprintf(bw, `<rect x="%s" y="%s" width="%s" height="%s" %s/>`+"\n",
+				svgNumber(o.Position().X), svgNumber(o.Position().Y), svgNumber(o.Size().Width), svgNumber(o.Size().Height),
+				svgPaint("fill", o.FillColor))
+		case *canvas.Line:
+			fmt.Fprintf(bw, `<line x1="%s" y1="%s" x2="%s" y2="%s" stroke-width="%s" %s/>`+"\n",
+				svgNumber(o.Position1.X), svgNumber(o.Position1.Y), svgNumber(o.Position2.X), svgNumber(o.Position2.Y),
+				svgNumber(o.StrokeWidth), svgPaint("stroke", o.StrokeColor))
+		case *canvas.Text:
+			fmt.Fprintf(bw, `<text x="%s" y="%s" font-family="sans-serif" font-size="%s" dominant-baseline="hanging" %s>`,
+				svgNumber(o.Position().X), svgNumber(o.Position().Y), svgNumber(o.TextSize), svgPaint("fill", o.Color))
+			_ = xml.EscapeText(bw, []byte(o.Text))
+			fmt.Fprint(bw, "</text>\n")
+		}
+	}
+
+	fmt.Fprint(bw, "</svg>\n")
+	if err := bw.Flush(); err != nil {
+		return fmt.Errorf("write chart svg: %w", err)
+	}
+
+	return nil
+}
+
+// offscreen returns a copy of the chart data without hover, so rendering it does not affect the chart on screen.
+func (c *Chart) offscreen() *Chart {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	o := New(c.size, c.unit)
+	o.series, o.end, o.interval = c.series, c.end, c.interval
+
+	return o
+}
+
+func svgNumber(v float32) string {
+	return strconv.FormatFloat(float64(v), 'f', -1, 32)
+}
+
+// svgPaint returns attributes painting a fill or a stroke with clr.
+func svgPaint(attr string, clr color.Color) string {
+	if clr == nil {
+		return attr + `="none"`
+	}
+	c := color.NRGBAModel.Convert(clr).(color.NRGBA)
+	paint := fmt.Sprintf(`%s="#%02x%02x%02x"`, attr, c.R, c.G, c.B)
+	if c.A != 0xff {
+		paint += fmt.Sprintf(` %s-opacity="%s"`, attr, strconv.FormatFloat(float64(c.A)/0xff, 'f', 3, 64))
+	}
+
+	return paint
+}
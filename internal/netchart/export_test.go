@@ -0,0 +1,70 @@
+package netchart
+
+import (
+	"bytes"
+	"encoding/xml"
+	"image/color"
+	"image/png"
+	"io"
+	"testing"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/test"
+	"github.com/stretchr/testify/require"
+)
+
+func exportedChart(t *testing.T) *Chart {
+	t.Helper()
+	test.NewTempApp(t)
+
+	c := New(fyne.NewSize(250, 100), MegabitsPerSecond)
+	require.NoError(t, c.Update([]Series{
+		{Name: "up & down", Color: color.RGBA{G: 255, A: 255}, Values: []float64{0, 1, 2, 3}},
+		{Name: "download", Color: color.NRGBA{B: 255, A: 128}, Values: []float64{4, 5, 6, 7.5}},
+	}, time.Date(2024, 5, 1, 12, 0, 59, 0, time.UTC), time.Second))
+	c.setHover(1)
+
+	return c
+}
+
+func TestChart_WritePNG(t *testing.T) {
+	c := exportedChart(t)
+
+	buf := &bytes.Buffer{}
+	require.NoError(t, c.Export(buf, fyne.NewSize(640, 320), ImagePNG))
+	img, err := png.Decode(buf)
+	require.NoError(t, err)
+	require.Equal(t, 640, img.Bounds().Dx())
+	require.Equal(t, 320, img.Bounds().Dy())
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	require.Equal(t, 1, c.hover, "chart on screen is not affected")
+}
+
+func TestChart_WriteSVG(t *testing.T) {
+	c := exportedChart(t)
+
+	buf := &bytes.Buffer{}
+	require.NoError(t, c.Export(buf, fyne.NewSize(640, 320), ImageSVG))
+	out := buf.String()
+	require.Contains(t, out, `width="640" height="320" viewBox="0 0 640 320"`)
+	require.Contains(t, out, ">up &amp; down</text>")
+	require.Contains(t, out, ">8 Mb/s</text>")
+	require.Contains(t, out, ">12:00:59</text>")
+	require.Contains(t, out, `stroke="#00ff00"`)
+	require.Contains(t, out, `stroke="#0000ff" stroke-opacity="0.502"`)
+	require.NotContains(t, out, "download: ", "no tooltip")
+
+	dec := xml.NewDecoder(buf)
+	for {
+		_, err := dec.Token()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+	}
+
+	require.ErrorIs(t, c.Export(io.Discard, fyne.NewSize(1, 1), "gif"), ErrUnknownImageFormat)
+}
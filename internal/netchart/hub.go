@@ -0,0 +1,112 @@
+package netchart
+
+import (
+	"sync"
+	"time"
+)
+
+type Sampler interface {
+	// Sample should record traffic since the previous sample and return number of bytes transferred.
+	Sample(now time.Time) (read, written int)
+}
+
+// hubEntry is an active sampler, mu serializes its samples.
+type hubEntry struct {
+	mu      sync.Mutex
+	removed bool // Deactivated, it is not sampled anymore.
+	notify  func(read, written int)
+}
+
+// Hub samples all active samplers with a single ticker. The ticker only runs while any sampler is active,
+// so idle samplers cost nothing.
+//
+// Callbacks are called without any locks held, so they may activate and deactivate samplers.
+type Hub struct {
+	interval time.Duration
+
+	mu     sync.Mutex // Guards active and stop.
+	active map[Sampler]*hubEntry
+	stop   chan struct{}
+}
+
+// NewHub creates a Hub sampling every interval.
+func NewHub(interval time.Duration) *Hub {
+	return &Hub{interval: interval, active: map[Sampler]*hubEntry{}}
+}
+
+// Activate starts sampling s, notify is called with transferred bytes after each sample.
+func (h *Hub) Activate(s Sampler, notify func(read, written int)) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.active[s] = &hubEntry{notify: notify}
+	if h.stop == nil {
+		h.stop = make(chan struct{})
+		go h.run(h.stop)
+	}
+}
+
+// Deactivate samples s for the last time and stops sampling it, it waits for a sample in progress.
+func (h *Hub) Deactivate(s Sampler) {
+	h.mu.Lock()
+	e, ok := h.active[s]
+	if ok {
+		delete(h.active, s)
+		if len(h.active) == 0 {
+			close(h.stop)
+			h.stop = nil
+		}
+	}
+	h.mu.Unlock()
+	if !ok {
+		return
+	}
+
+	e.mu.Lock()
+	e.removed = true
+	read, written := s.Sample(time.Now())
+	e.mu.Unlock()
+	e.notify(read, written)
+}
+
+// Active returns number of active samplers.
+func (h *Hub) Active() int {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	return len(h.active)
+}
+
+func (h *Hub) run(stop chan struct{}) {
+	ticker := time.NewTicker(h.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-stop:
+			return
+		case now := <-ticker.C:
+			h.tick(now)
+		}
+	}
+}
+
+func (h *Hub) tick(now time.Time) {
+	h.mu.Lock()
+	active := make(map[Sampler]*hubEntry, len(h.active))
+	for s, e := range h.active {
+		active[s] = e
+	}
+	h.mu.Unlock()
+
+	for s, e := range active {
+		e.mu.Lock()
+		if e.removed { // Deactivated after the copy, it had the last sample.
+			e.mu.Unlock()
+			continue
+		}
+		read, written := s.Sample(now)
+		e.mu.Unlock()
+		e.notify(read, written)
+	}
+}
@@ -0,0 +1,87 @@
+package netchart
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type countingSampler struct {
+	mu sync.Mutex
+	n  int
+}
+
+func (c *countingSampler) Sample(time.Time) (int, int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.n++
+
+	return c.n, 0
+}
+
+func (c *countingSampler) count() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.n
+}
+
+func TestHub(t *testing.T) {
+	hub := NewHub(time.Millisecond)
+	a, b := &countingSampler{}, &countingSampler{}
+	notified := make(chan struct{}, 100)
+
+	hub.Activate(a, func(int, int) {
+		select {
+		case notified <- struct{}{}:
+		default:
+		}
+	})
+	hub.Activate(b, func(int, int) {})
+	require.Equal(t, 2, hub.Active())
+	<-notified
+	<-notified
+
+	hub.Deactivate(b)
+	hub.Deactivate(b) // Already inactive.
+	sampled := b.count()
+	require.Positive(t, sampled)
+	require.Equal(t, 1, hub.Active())
+
+	<-notified
+	<-notified
+	require.Equal(t, sampled, b.count(), "inactive samplers are not sampled")
+
+	hub.Deactivate(a)
+	require.Zero(t, hub.Active())
+	sampled = a.count()
+	<-time.After(5 * time.Millisecond)
+	require.Equal(t, sampled, a.count(), "ticker is stopped when nothing is active")
+
+	// Restarts after being idle.
+	hub.Activate(a, func(int, int) {})
+	require.Eventually(t, func() bool { return a.count() > sampled }, time.Second, time.Millisecond)
+	hub.Deactivate(a)
+}
+
+func TestHub_DeactivateFromCallback(t *testing.T) {
+	hub := NewHub(time.Millisecond)
+	a := &countingSampler{}
+	done := make(chan int, 1)
+	hub.Activate(a, func(read, _ int) {
+		if read == 1 {
+			hub.Deactivate(a) // E.g. disconnect when a quota is exceeded.
+		} else {
+			done <- read
+		}
+	})
+
+	select {
+	case last := <-done:
+		require.Equal(t, 2, last, "deactivated sampler is sampled for the last time")
+	case <-time.After(time.Second):
+		t.Fatal("deadlock")
+	}
+	require.Zero(t, hub.Active())
+}
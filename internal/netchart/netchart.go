@@ -1,131 +1,443 @@
 /*
-Package netchart implements basic chart to generate small minimalistic chart of network usage.
+Package netchart records network usage and draws it as an interactive line chart.
 */
 package netchart
 
 import (
+	"errors"
 	"fmt"
 	"image/color"
+	"math"
+	"strconv"
+	"sync"
+	"time"
 
 	"fyne.io/fyne/v2"
-	canvas2 "fyne.io/fyne/v2/canvas"
-	"fyne.io/fyne/v2/container"
-	"github.com/ajstarks/fc"
-	"github.com/ajstarks/fc/chart"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/driver/desktop"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
 )
 
+const (
+	yTicks       = 4 // Number of intervals between Y axis labels.
+	xTicks       = 3 // Number of intervals between X axis labels.
+	chartPadding = 4
+	lineWidth    = 1.5
+	legendMarker = 8
+)
+
+var ErrSeriesLength = errors.New("all series must have the same number of values")
+
+// Series is a named line of the chart.
+type Series struct {
+	Name   string
+	Color  color.Color
+	Values []float64
+}
+
+// bitScales are Y axis units from the smallest, a scale is used when the axis top reaches it.
+var bitScales = []struct {
+	bits float64
+	name string
+}{
+	{1, "bit/s"},
+	{1e3, "Kb/s"},
+	{1e6, "Mb/s"},
+	{1e9, "Gb/s"},
+}
+
+// Chart is a line chart of transfer rates with time labeled X axis, auto-scaled Y axis, a legend and
+// a tooltip with exact values of the hovered point.
 type Chart struct {
-	canvas   fc.Canvas
-	lineSize float64
+	widget.BaseWidget
+	size fyne.Size
+	unit Unit
+
+	mu       sync.Mutex
+	series   []Series
+	end      time.Time
+	interval time.Duration
+	hover    int // Index of the hovered value, -1 if none.
+}
+
+var _ desktop.Hoverable = (*Chart)(nil)
+
+// New creates an empty chart of the given minimal size for values in unit, use Update to draw values.
+func New(size fyne.Size, unit Unit) *Chart {
+	c := &Chart{size: size, unit: unit, hover: -1}
+	c.ExtendBaseWidget(c)
+
+	return c
+}
+
+// Update replaces the drawn series, values are sampled every interval and the last one is at end.
+func (c *Chart) Update(series []Series, end time.Time, interval time.Duration) error {
+	for _, s := range series {
+		if len(s.Values) != len(series[0].Values) {
+			return fmt.Errorf("%w: %s", ErrSeriesLength, s.Name)
+		}
+	}
+
+	c.mu.Lock()
+	c.series, c.end, c.interval = series, end, interval
+	if c.hover >= c.length() {
+		c.hover = -1
+	}
+	c.mu.Unlock()
+	c.Refresh()
+
+	return nil
+}
+
+func (c *Chart) CreateRenderer() fyne.WidgetRenderer {
+	return &chartRenderer{chart: c}
+}
+
+func (c *Chart) MouseIn(e *desktop.MouseEvent) {
+	c.MouseMoved(e)
+}
+
+func (c *Chart) MouseMoved(e *desktop.MouseEvent) {
+	c.setHover(c.indexAt(e.Position))
+}
+
+func (c *Chart) MouseOut() {
+	c.setHover(-1)
+}
+
+func (c *Chart) setHover(i int) {
+	c.mu.Lock()
+	changed := c.hover != i
+	c.hover = i
+	c.mu.Unlock()
+
+	if changed {
+		c.Refresh()
+	}
+}
+
+// indexAt returns index of the value closest to pos, -1 if pos is outside the plot.
+func (c *Chart) indexAt(pos fyne.Position) int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	n := c.length()
+	plot := c.plotArea(c.Size())
+	if n == 0 || pos.X < plot.Position.X || pos.X > plot.Position.X+plot.Size.Width {
+		return -1
+	}
+	if n == 1 {
+		return 0
+	}
+
+	return int(math.Round(float64((pos.X - plot.Position.X) / plot.Size.Width * float32(n-1))))
+}
+
+// length returns number of values in each series, must be called with mu held.
+func (c *Chart) length() int {
+	if len(c.series) == 0 {
+		return 0
+	}
+
+	return len(c.series[0].Values)
+}
+
+// timeAt returns time of the i-th value, must be called with mu held.
+func (c *Chart) timeAt(i int) time.Time {
+	return c.end.Add(-time.Duration(c.length()-1-i) * c.interval)
+}
+
+// area is a rectangle on the chart.
+type area struct {
+	Position fyne.Position
+	Size     fyne.Size
+}
+
+// plotArea returns area of lines inside the axes labels and the legend.
+func (c *Chart) plotArea(size fyne.Size) area {
+	textHeight := fyne.MeasureText("0", theme.CaptionTextSize(), fyne.TextStyle{}).Height
+	left := fyne.MeasureText("000 Kb/s", theme.CaptionTextSize(), fyne.TextStyle{}).Width + chartPadding
+	top := textHeight + chartPadding // Legend.
+	bottom := textHeight + chartPadding
+
+	return area{
+		Position: fyne.NewPos(left, top),
+		Size:     fyne.NewSize(max(size.Width-left-chartPadding, 1), max(size.Height-top-bottom, 1)),
+	}
+}
+
+// yScale returns top of the Y axis in the chart unit and the step between labels for values up to maxVal.
+func (c *Chart) yScale(maxVal float64) (top, step float64) {
+	if maxVal <= 0 {
+		maxVal = bitScales[2].bits * c.unit.PerByte / 8 // 1 Mb/s for empty charts.
+	}
+	step = niceStep(maxVal / yTicks)
+
+	return step * math.Ceil(maxVal/step), step
 }
 
-func New(w, h, lineSize float64) *Chart {
-	return &Chart{
-		canvas: fc.Canvas{
-			Container: container.NewWithoutLayout(canvas2.NewRectangle(color.RGBA{255, 255, 255, 0})),
-			Width:     w,
-			Height:    h,
-		},
-		lineSize: lineSize,
+// FormatRate formats a rate in unit as bits per second with a unit suffix (Kb/s, Mb/s, Gb/s).
+func FormatRate(v float64, unit Unit) string {
+	bits := v * 8 / unit.PerByte
+	scale := bitScales[0]
+	for _, s := range bitScales {
+		if math.Abs(bits) >= s.bits {
+			scale = s
+		}
 	}
+
+	return strconv.FormatFloat(roundTo(bits/scale.bits, 3), 'f', -1, 64) + " " + scale.name
 }
 
-func (c *Chart) Container() *fyne.Container {
-	return c.canvas.Container
+// niceStep rounds v up to 1, 2, 2.5 or 5 multiplied by a power of ten.
+func niceStep(v float64) float64 {
+	exp := math.Pow(10, math.Floor(math.Log10(v)))
+	for _, f := range []float64{1, 2, 2.5, 5} {
+		if v <= f*exp {
+			return f * exp
+		}
+	}
+
+	return 10 * exp
 }
 
-func (c *Chart) Canvas() fc.Canvas {
-	return c.canvas
+// roundTo rounds v to the given number of significant digits.
+func roundTo(v float64, digits int) float64 {
+	if v == 0 {
+		return 0
+	}
+	pow := math.Pow(10, float64(digits-1)-math.Floor(math.Log10(math.Abs(v))))
+
+	return math.Round(v*pow) / pow
 }
 
-func (c *Chart) UpdateNamed(stats map[string][]float64, clrs map[string]color.RGBA, orders []string) error {
-	if len(stats) == 0 {
-		return fmt.Errorf("stats is empty")
+// timeLayout returns time layout precise enough to tell apart times d apart.
+func timeLayout(d time.Duration) string {
+	switch {
+	case d < 10*time.Minute:
+		return time.TimeOnly
+	case d < 24*time.Hour:
+		return "15:04"
+	default:
+		return "Jan 2"
 	}
+}
+
+type chartRenderer struct {
+	chart   *Chart
+	objects []fyne.CanvasObject
+}
+
+func (r *chartRenderer) Destroy() {}
+
+func (r *chartRenderer) Layout(size fyne.Size) {
+	r.objects = r.chart.draw(size)
+}
 
-	if len(clrs) != len(stats) {
-		return fmt.Errorf("required %d colors, got %d", len(stats), len(clrs))
+func (r *chartRenderer) MinSize() fyne.Size {
+	return r.chart.size
+}
+
+func (r *chartRenderer) Objects() []fyne.CanvasObject {
+	return r.objects
+}
+
+func (r *chartRenderer) Refresh() {
+	r.objects = r.chart.draw(r.chart.Size())
+	canvas.Refresh(r.chart)
+}
+
+// draw creates chart objects for the given size.
+func (c *Chart) draw(size fyne.Size) []fyne.CanvasObject {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	textSize := theme.CaptionTextSize()
+	muted := theme.Color(theme.ColorNamePlaceHolder)
+	plot := c.plotArea(size)
+	n := c.length()
+
+	bg := canvas.NewRectangle(theme.Color(theme.ColorNameInputBackground))
+	bg.Resize(size)
+	objects := []fyne.CanvasObject{bg}
+
+	text := func(s string, clr color.Color, pos fyne.Position, align fyne.TextAlign) *canvas.Text {
+		t := canvas.NewText(s, clr)
+		t.TextSize = textSize
+		width := fyne.MeasureText(s, textSize, t.TextStyle).Width
+		switch align {
+		case fyne.TextAlignCenter:
+			pos.X -= width / 2
+		case fyne.TextAlignTrailing:
+			pos.X -= width
+		}
+		t.Move(pos)
+		t.Resize(fyne.NewSize(width, fyne.MeasureText(s, textSize, t.TextStyle).Height))
+		objects = append(objects, t)
+
+		return t
+	}
+	line := func(from, to fyne.Position, clr color.Color, width float32) {
+		l := canvas.NewLine(clr)
+		l.StrokeWidth = width
+		l.Position1, l.Position2 = from, to
+		objects = append(objects, l)
 	}
 
-	prevLen := 0
-	for k, v := range stats {
-		if prevLen == 0 {
-			prevLen = len(v)
-			continue
+	// Y axis with a grid.
+	maxVal := 0.
+	for _, s := range c.series {
+		for _, v := range s.Values {
+			maxVal = max(maxVal, v)
 		}
-		if len(v) != prevLen {
-			return fmt.Errorf("expected all items to be the same len, got diff on stats[%s]", k)
+	}
+	top, step := c.yScale(maxVal)
+	textHeight := fyne.MeasureText("0", textSize, fyne.TextStyle{}).Height
+	for v := 0.; v <= top+step/2; v += step {
+		y := plot.Position.Y + plot.Size.Height*float32(1-v/top)
+		line(fyne.NewPos(plot.Position.X, y), fyne.NewPos(plot.Position.X+plot.Size.Width, y),
+			theme.Color(theme.ColorNameSeparator), 1)
+		text(FormatRate(v, c.unit), muted, fyne.NewPos(plot.Position.X-chartPadding, y-textHeight/2), fyne.TextAlignTrailing)
+	}
+
+	x := func(i int) float32 {
+		if n <= 1 {
+			return plot.Position.X + plot.Size.Width
+		}
+
+		return plot.Position.X + plot.Size.Width*float32(i)/float32(n-1)
+	}
+	y := func(v float64) float32 {
+		return plot.Position.Y + plot.Size.Height*float32(1-min(v/top, 1))
+	}
+
+	// X axis labels.
+	if n > 0 {
+		layout := timeLayout(time.Duration(n-1) * c.interval)
+		labelY := plot.Position.Y + plot.Size.Height + chartPadding/2
+		for k := 0; k <= xTicks; k++ {
+			i := k * (n - 1) / xTicks
+			align := fyne.TextAlignCenter
+			switch k {
+			case 0:
+				align = fyne.TextAlignLeading
+			case xTicks:
+				align = fyne.TextAlignTrailing
+			}
+			text(c.timeAt(i).Format(layout), muted, fyne.NewPos(x(i), labelY), align)
 		}
 	}
 
-	charts := make([]chart.ChartBox, 0, len(stats))
-	// Preserve order
-	for _, name := range orders {
-		charts = append(charts, chart.ChartBox{
-			Title:     name,
-			Data:      make([]chart.NameValue, 0, len(stats[name])),
-			Color:     clrs[name],
-			Top:       85,
-			Bottom:    20,
-			Left:      10,
-			Right:     95,
-			Minvalue:  0,
-			Maxvalue:  0, // Will be dynamically updated from stats
-			Zerobased: true,
-		})
-	}
-
-	maxValue := 0.
-	for i := range charts {
-		// Add data to chart
-		data := make([]chart.NameValue, 0, len(stats[charts[i].Title]))
-		for _, sv := range stats[charts[i].Title] {
-			maxValue = max(maxValue, sv)
-			data = append(data, chart.NameValue{
-				Value: sv,
-				Label: fmt.Sprintf("%.2f", sv),
-			})
+	// Lines.
+	for _, s := range c.series {
+		for i := 1; i < len(s.Values); i++ {
+			line(fyne.NewPos(x(i-1), y(s.Values[i-1])), fyne.NewPos(x(i), y(s.Values[i])), s.Color, lineWidth)
 		}
+	}
+
+	// Legend.
+	legendX := plot.Position.X
+	for _, s := range c.series {
+		marker := canvas.NewRectangle(s.Color)
+		marker.Resize(fyne.NewSquareSize(legendMarker))
+		marker.Move(fyne.NewPos(legendX, (textHeight-legendMarker)/2+chartPadding/2))
+		objects = append(objects, marker)
+		t := text(s.Name, theme.Color(theme.ColorNameForeground), fyne.NewPos(legendX+legendMarker+chartPadding, chartPadding/2),
+			fyne.TextAlignLeading)
+		legendX += legendMarker + t.Size().Width + chartPadding*4
+	}
+
+	if c.hover >= 0 && c.hover < n {
+		objects = append(objects, c.drawTooltip(plot, x(c.hover), textSize)...)
+	}
+
+	return objects
+}
+
+// drawTooltip draws hover line and tooltip with time and values of the hovered point at x,
+// must be called with mu held.
+func (c *Chart) drawTooltip(plot area, x, textSize float32) []fyne.CanvasObject {
+	marker := canvas.NewLine(theme.Color(theme.ColorNameForeground))
+	marker.Position1 = fyne.NewPos(x, plot.Position.Y)
+	marker.Position2 = fyne.NewPos(x, plot.Position.Y+plot.Size.Height)
 
-		charts[i].Data = data
+	bg := canvas.NewRectangle(theme.Color(theme.ColorNameOverlayBackground))
+	bg.StrokeColor = theme.Color(theme.ColorNameSeparator)
+	bg.StrokeWidth = 1
+	objects := []fyne.CanvasObject{marker, bg}
+
+	lines := c.tooltip(c.hover)
+	var width, height float32
+	texts := make([]*canvas.Text, 0, len(lines))
+	for i, s := range lines {
+		clr := theme.Color(theme.ColorNameForeground)
+		if i > 0 {
+			clr = c.series[i-1].Color
+		}
+		t := canvas.NewText(s, clr)
+		t.TextSize = textSize
+		t.Resize(fyne.MeasureText(s, textSize, t.TextStyle))
+		t.Move(fyne.NewPos(chartPadding, chartPadding+height))
+		width, height = max(width, t.Size().Width), height+t.Size().Height
+		texts = append(texts, t)
 	}
 
-	if maxValue == 0 {
-		maxValue = 100
+	size := fyne.NewSize(width+chartPadding*2, height+chartPadding*2)
+	pos := fyne.NewPos(x+chartPadding, plot.Position.Y)
+	if pos.X+size.Width > plot.Position.X+plot.Size.Width { // Keep inside the chart.
+		pos.X = x - chartPadding - size.Width
 	}
-	for i := range charts {
-		charts[i].Maxvalue = maxValue
+	bg.Resize(size)
+	bg.Move(pos)
+	for _, t := range texts {
+		t.Move(t.Position().Add(pos))
+		objects = append(objects, t)
 	}
 
-	rect := canvas2.NewRectangle(color.RGBA{R: 200, G: 255, B: 255, A: 0})
-	rect.SetMinSize(fyne.NewSize(float32(c.canvas.Width), float32(c.canvas.Height)))
-	c.canvas.Container.RemoveAll()
-	c.canvas.Container.Add(rect)
-	defer c.canvas.Container.Refresh()
+	return objects
+}
 
-	size := 5.
-	gridColor := color.RGBA{R: 128, G: 128, B: 128, A: 255}
-	for i := range charts {
-		chartColor := charts[i].Color
-		charts[i].Color = gridColor
-		charts[i].YAxis(c.canvas, size-1, 0, maxValue, maxValue/4, "%0.f", true)
-		charts[i].Color = chartColor
-		charts[i].Scatter(c.canvas, c.lineSize)
-		charts[i].Line(c.canvas, c.lineSize)
+// tooltip returns lines describing the i-th point: its time and a value of each series,
+// must be called with mu held.
+func (c *Chart) tooltip(i int) []string {
+	layout := time.DateTime
+	if c.interval >= 24*time.Hour {
+		layout = time.DateOnly
+	}
+	lines := []string{c.timeAt(i).Format(layout)}
+	for _, s := range c.series {
+		lines = append(lines, fmt.Sprintf("%s: %s", s.Name, FormatRate(s.Values[i], c.unit)))
+	}
 
+	return lines
+}
+
+// Downsample averages consecutive values so that no more than n values are returned.
+func Downsample(vals []float64, n int) []float64 {
+	if n <= 0 || len(vals) <= n {
+		return vals
 	}
 
-	if len(charts) != 2 {
-		return nil
+	group := DownsampleGroup(len(vals), n)
+	res := make([]float64, 0, n)
+	for i := 0; i < len(vals); i += group {
+		end := min(i+group, len(vals))
+		sum := 0.
+		for _, v := range vals[i:end] {
+			sum += v
+		}
+		res = append(res, sum/float64(end-i))
 	}
 
-	// Hardcoded, ok for current goal.
-	offset := 17.
-	midx := charts[0].Left + ((charts[0].Right - charts[0].Left) / 2) + 14
-	c.canvas.CText(midx, charts[0].Bottom-offset, size, charts[0].Title, charts[0].Color)
+	return res
+}
 
-	midx = charts[1].Left + ((charts[1].Right - charts[1].Left) / 2) - 14
-	c.canvas.CText(midx, charts[1].Bottom-offset, size, charts[1].Title, charts[1].Color)
+// DownsampleGroup returns how many of length values Downsample averages into one value.
+func DownsampleGroup(length, n int) int {
+	if n <= 0 || length <= n {
+		return 1
+	}
 
-	return nil
+	return (length + n - 1) / n
 }
@@ -0,0 +1,100 @@
+package netchart
+
+import (
+	"image/color"
+	"testing"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/test"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDownsample(t *testing.T) {
+	require.Equal(t, []float64{1, 2, 3}, Downsample([]float64{1, 2, 3}, 3))
+	require.Equal(t, []float64{1, 2, 3}, Downsample([]float64{1, 2, 3}, 0))
+	require.Equal(t, []float64{1.5, 3.5, 5.5}, Downsample([]float64{1, 2, 3, 4, 5, 6}, 3))
+	require.Equal(t, []float64{2, 5, 7}, Downsample([]float64{1, 2, 3, 4, 5, 6, 7}, 3))
+	require.Len(t, Downsample(make([]float64, 1440), 120), 120)
+	require.Len(t, Downsample(make([]float64, 720), 120), 120)
+}
+
+func TestFormatRate(t *testing.T) {
+	require.Equal(t, "0 bit/s", FormatRate(0, MegabitsPerSecond))
+	require.Equal(t, "500 Kb/s", FormatRate(0.5, MegabitsPerSecond))
+	require.Equal(t, "12.3 Mb/s", FormatRate(12.345, MegabitsPerSecond))
+	require.Equal(t, "1.5 Gb/s", FormatRate(1500, MegabitsPerSecond))
+	require.Equal(t, "800 bit/s", FormatRate(100, BytesPerSecond))
+	require.Equal(t, "8 Kb/s", FormatRate(1000, BytesPerSecond))
+}
+
+func TestChart_YScale(t *testing.T) {
+	c := New(fyne.NewSize(100, 100), MegabitsPerSecond)
+	for _, tc := range []struct {
+		max, top, step float64
+	}{
+		{max: 0, top: 1, step: 0.25},
+		{max: 3.7, top: 4, step: 1},
+		{max: 9, top: 10, step: 2.5},
+		{max: 42, top: 60, step: 20},
+		{max: 100, top: 100, step: 25},
+	} {
+		top, step := c.yScale(tc.max)
+		require.InDelta(t, tc.top, top, 1e-9, tc.max)
+		require.InDelta(t, tc.step, step, 1e-9, tc.max)
+	}
+}
+
+func TestChart_Update(t *testing.T) {
+	c := New(fyne.NewSize(100, 100), MegabitsPerSecond)
+	require.ErrorIs(t, c.Update([]Series{{Name: "a", Values: []float64{1}}, {Name: "b", Values: []float64{1, 2}}},
+		time.Now(), time.Second), ErrSeriesLength)
+	require.NoError(t, c.Update(nil, time.Now(), time.Second))
+}
+
+func TestChart_Render(t *testing.T) {
+	test.NewTempApp(t)
+
+	c := New(fyne.NewSize(300, 120), MegabitsPerSecond)
+	end := time.Date(2024, 5, 1, 12, 0, 59, 0, time.UTC)
+	require.NoError(t, c.Update([]Series{
+		{Name: "upload", Color: color.RGBA{G: 255, A: 255}, Values: []float64{0, 1, 2, 3}},
+		{Name: "download", Color: color.RGBA{B: 255, A: 255}, Values: []float64{4, 5, 6, 7.5}},
+		{Name: "other", Color: color.RGBA{R: 255, A: 255}, Values: []float64{0, 0, 0, 0}},
+	}, end, 20*time.Second))
+
+	w := test.NewTempWindow(t, c)
+	w.SetPadded(false)
+	w.Resize(fyne.NewSize(300, 120))
+	texts := chartTexts(c)
+	for _, s := range []string{"upload", "download", "other", "0 bit/s", "2 Mb/s", "8 Mb/s", "11:59:59", "12:00:59"} {
+		require.Contains(t, texts, s)
+	}
+	require.Equal(t, 300, w.Canvas().Capture().Bounds().Dx())
+
+	plot := c.plotArea(c.Size())
+	test.MoveMouse(w.Canvas(), fyne.NewPos(plot.Position.X+plot.Size.Width, plot.Position.Y+10))
+	texts = chartTexts(c)
+	for _, s := range []string{"2024-05-01 12:00:59", "upload: 3 Mb/s", "download: 7.5 Mb/s", "other: 0 bit/s"} {
+		require.Contains(t, texts, s)
+	}
+
+	test.MoveMouse(w.Canvas(), fyne.NewPos(plot.Position.X+1, plot.Position.Y+10))
+	require.Contains(t, chartTexts(c), "2024-05-01 11:59:59")
+	require.Contains(t, chartTexts(c), "download: 4 Mb/s")
+
+	test.MoveMouse(w.Canvas(), fyne.NewPos(1, 1))
+	require.NotContains(t, chartTexts(c), "upload: 0 bit/s")
+}
+
+func chartTexts(c *Chart) []string {
+	var texts []string
+	for _, o := range test.WidgetRenderer(c).Objects() {
+		if t, ok := o.(*canvas.Text); ok {
+			texts = append(texts, t.Text)
+		}
+	}
+
+	return texts
+}
@@ -3,104 +3,289 @@
 package netchart
 
 import (
-	"context"
 	"slices"
-	"sync"
+	"sync/atomic"
 	"time"
 )
 
-const bytesToMb = 125000
-
 type Source interface {
 	BytesRead() int
 	BytesWritten() int
 }
 
+// Unit is a unit of recorded transfer rates.
+type Unit struct {
+	Name    string
+	PerByte float64 // Number of units in a byte.
+}
+
+var (
+	BytesPerSecond    = Unit{Name: "B/s", PerByte: 1}
+	BitsPerSecond     = Unit{Name: "bit/s", PerByte: 8}
+	MegabitsPerSecond = Unit{Name: "Mbit/s", PerByte: 8.0 / 1e6}
+)
+
+// Resolution is a detail level of recorded data: one sample per Interval is kept for the last Span.
+type Resolution struct {
+	Interval time.Duration
+	Span     time.Duration
+}
+
+// DefaultResolutions keep detailed data only for the recent past, so memory stays bounded
+// (about 2.6k samples per direction) while allowing charts for up to a month.
+var DefaultResolutions = []Resolution{
+	{Interval: time.Second, Span: 2 * time.Minute},
+	{Interval: 10 * time.Second, Span: time.Hour},
+	{Interval: time.Minute, Span: 24 * time.Hour},
+	{Interval: time.Hour, Span: 30 * 24 * time.Hour},
+}
+
+// ring is a fixed-size circular buffer.
+type ring struct {
+	buf  []float64
+	next int
+	full bool
+}
+
+func newRing(size int) ring {
+	return ring{buf: make([]float64, size)}
+}
+
+func (r *ring) push(v float64) {
+	r.buf[r.next] = v
+	r.next++
+	if r.next == len(r.buf) {
+		r.next = 0
+		r.full = true
+	}
+}
+
+// values returns a copy of values from the oldest to the newest.
+func (r *ring) values() []float64 {
+	if !r.full {
+		return slices.Clone(r.buf[:r.next])
+	}
+
+	return append(slices.Clone(r.buf[r.next:]), r.buf[:r.next]...)
+}
+
+// series is an immutable snapshot of tier values.
+type series struct {
+	read    []float64
+	written []float64
+	at      time.Time // Time of the last value.
+}
+
+// tier keeps samples of a single Resolution, every sample is an average of ratio samples of the finer tier.
+// Rings and accumulators are only accessed by the recording goroutine, readers use the published snapshot.
+type tier struct {
+	Resolution
+	ratio int
+	limit int
+
+	read    ring
+	written ring
+	// Accumulated finer samples that are not yet averaged into a sample.
+	accRead, accWritten float64
+	accN                int
+
+	snapshot atomic.Pointer[series]
+}
+
+func newTiers(resolutions []Resolution) []*tier {
+	tiers := make([]*tier, 0, len(resolutions))
+	for i, res := range resolutions {
+		t := &tier{Resolution: res, ratio: 1, limit: max(int(res.Span/res.Interval), 1)}
+		if i > 0 {
+			t.ratio = max(int(res.Interval/resolutions[i-1].Interval), 1)
+		}
+		t.read, t.written = newRing(t.limit), newRing(t.limit)
+		t.snapshot.Store(&series{})
+		tiers = append(tiers, t)
+	}
+
+	return tiers
+}
+
+func (t *tier) push(read, written float64, now time.Time) {
+	t.read.push(read)
+	t.written.push(written)
+	t.publish(now)
+}
+
+func (t *tier) publish(now time.Time) {
+	t.snapshot.Store(&series{read: t.read.values(), written: t.written.values(), at: now})
+}
+
+// values returns up to limit latest values as of now, intervals passed since the snapshot are zeroes.
+func (t *tier) values(now time.Time) (read, written []float64) {
+	s := t.snapshot.Load()
+	if s.at.IsZero() {
+		return s.read, s.written
+	}
+
+	idle := min(max(int(now.Sub(s.at)/t.Interval), 0), t.limit)
+	if idle == 0 {
+		return s.read, s.written
+	}
+	n := min(len(s.read)+idle, t.limit)
+
+	return lastPadded(append(slices.Clone(s.read), make([]float64, idle)...), n),
+		lastPadded(append(slices.Clone(s.written), make([]float64, idle)...), n)
+}
+
+// Option configures Recorder.
+type Option func(r *Recorder)
+
+// WithUnit sets the unit of recorded values, MegabitsPerSecond by default.
+func WithUnit(u Unit) Option {
+	return func(r *Recorder) { r.unit = u }
+}
+
+// WithResolutions sets resolutions from the finest to the coarsest, DefaultResolutions by default.
+// The finest interval is the recording interval.
+func WithResolutions(resolutions []Resolution) Option {
+	return func(r *Recorder) {
+		r.interval = resolutions[0].Interval
+		r.tiers = newTiers(resolutions)
+	}
+}
+
+// Recorder keeps transfer rates of Source sampled every RecordInterval, usually by Hub. Samples are recorded
+// by a single goroutine and published as immutable snapshots, so reading is safe at any time and never blocks
+// recording.
 type Recorder struct {
 	base     Source
 	interval time.Duration
-	mu       sync.RWMutex
+	unit     Unit
 
-	stopRecording   func()
-	done            chan struct{}
-	recordedRead    []float64
-	recordedWritten []float64
-	recordLimit     int
-	totalRead       int
-	totalWrite      int
+	lastSample time.Time
+	tiers      []*tier // From the finest to the coarsest.
+	totalRead  atomic.Int64
+	totalWrite atomic.Int64
 }
 
-// NewRecorder creates a default Recorder
-// TODO: Decrease detalization for old data as the time goes on to allow for longer ranges charts.
-func NewRecorder(s Source) *Recorder {
-	return &Recorder{
-		base:        s,
-		interval:    time.Second, // store data value per interval
-		recordLimit: 60 * 2,      // store and record only last 2 minutes of data
-		done:        make(chan struct{}),
+// NewRecorder creates a Recorder with DefaultResolutions in MegabitsPerSecond unless configured otherwise.
+func NewRecorder(s Source, opts ...Option) *Recorder {
+	r := &Recorder{
+		base:     s,
+		interval: DefaultResolutions[0].Interval, // store data value per interval
+		unit:     MegabitsPerSecond,
+		tiers:    newTiers(DefaultResolutions),
+	}
+	for _, opt := range opts {
+		opt(r)
 	}
+
+	return r
 }
 
+// Read returns uplink samples of the finest resolution.
 func (r *Recorder) Read() []float64 {
-	r.mu.Lock()
-	defer r.mu.Unlock()
-	return r.recordedRead
+	read, _ := r.tiers[0].values(time.Now())
+	return slices.Clone(read)
 }
 
+// Written returns downlink samples of the finest resolution.
 func (r *Recorder) Written() []float64 {
-	r.mu.RLock()
-	defer r.mu.RUnlock()
-	return r.recordedWritten
+	_, written := r.tiers[0].values(time.Now())
+	return slices.Clone(written)
 }
 
 func (r *Recorder) RecordInterval() time.Duration {
 	return r.interval
 }
 
-func (r *Recorder) Start() {
-	var ctx context.Context
-	ctx, r.stopRecording = context.WithCancel(context.Background())
-	go func() {
-		for {
-			select {
-			case <-ctx.Done():
-				r.done <- struct{}{}
-				return
-			case <-time.After(r.interval):
-				func() {
-					r.mu.RLock()
-					defer r.mu.RUnlock()
-
-					rawRead := float64(r.ReadSinceLast() / bytesToMb)
-					rawWritten := float64(r.WrittenSinceLast() / bytesToMb)
-
-					if len(r.recordedRead) > r.recordLimit {
-						r.recordedRead = slices.Delete(r.recordedRead, 0, 1)
-					}
-					r.recordedRead = append(r.recordedRead, rawRead)
-
-					if len(r.recordedWritten) > r.recordLimit {
-						r.recordedWritten = slices.Delete(r.recordedWritten, 0, 1)
-					}
-					r.recordedWritten = append(r.recordedWritten, rawWritten)
-				}()
-			}
+// Unit returns the unit of recorded values.
+func (r *Recorder) Unit() Unit {
+	return r.unit
+}
+
+// Range returns samples covering the last span from the finest resolution that keeps the whole span,
+// and the interval of the returned samples. Missing old samples are filled with zeroes.
+func (r *Recorder) Range(span time.Duration) (read, written []float64, interval time.Duration) {
+	t := r.tiers[len(r.tiers)-1]
+	for _, candidate := range r.tiers {
+		if candidate.Span >= span {
+			t = candidate
+			break
 		}
+	}
+
+	n := min(max(int(span/t.Interval), 1), t.limit)
+	read, written = t.values(time.Now())
 
-	}()
+	return lastPadded(read, n), lastPadded(written, n), t.Interval
 }
 
-func (r *Recorder) Stop() {
-	r.stopRecording()
-	<-r.done
+// lastPadded returns last n values, prepending zeroes if there are not enough values.
+func lastPadded(vals []float64, n int) []float64 {
+	res := make([]float64, n)
+	if len(vals) > n {
+		vals = vals[len(vals)-n:]
+	}
+	copy(res[n-len(vals):], vals)
+
+	return res
+}
+
+// Sample records bytes transferred since the previous sample and returns them, intervals missed since then are
+// recorded as idle. It must not be called concurrently.
+func (r *Recorder) Sample(now time.Time) (read, written int) {
+	if !r.lastSample.IsZero() {
+		if missed := int(now.Sub(r.lastSample)/r.interval) - 1; missed > 0 {
+			r.skip(missed, now)
+		}
+	}
+	r.lastSample = now
+
+	read, written = r.ReadSinceLast(), r.WrittenSinceLast()
+	perSecond := r.unit.PerByte / r.interval.Seconds()
+	r.record(float64(read)*perSecond, float64(written)*perSecond, now)
+
+	return read, written
+}
+
+// skip records zeroes for missed intervals of the finest tier, partially accumulated samples are dropped.
+func (r *Recorder) skip(missed int, now time.Time) {
+	for _, t := range r.tiers {
+		n := missed * int(r.interval) / int(t.Interval)
+		if n == 0 {
+			continue
+		}
+		t.accRead, t.accWritten, t.accN = 0, 0, 0
+		for range min(n, t.limit) {
+			t.read.push(0)
+			t.written.push(0)
+		}
+		t.publish(now)
+	}
+}
+
+// record adds sample to the finest tier and averages it into coarser tiers.
+func (r *Recorder) record(read, written float64, now time.Time) {
+	for i, t := range r.tiers {
+		if i > 0 {
+			t.accRead += read
+			t.accWritten += written
+			t.accN++
+			if t.accN < t.ratio {
+				return
+			}
+
+			read, written = t.accRead/float64(t.accN), t.accWritten/float64(t.accN)
+			t.accRead, t.accWritten, t.accN = 0, 0, 0
+		}
+		t.push(read, written, now)
+	}
 }
 
 func (r *Recorder) BytesRead() int {
-	return r.totalRead
+	return int(r.totalRead.Load())
 }
 
 func (r *Recorder) BytesWritten() int {
-	return r.totalWrite
+	return int(r.totalWrite.Load())
 }
 
 // ReadSinceLast returns bytes read from last call (upload).
@@ -108,10 +293,8 @@ func (r *Recorder) ReadSinceLast() int {
 	if r.base == nil {
 		return 0
 	}
-	readSinceLast := r.base.BytesRead() - r.totalRead
-	r.totalRead = r.base.BytesRead()
 
-	return max(readSinceLast, 0)
+	return sinceLast(&r.totalRead, int64(r.base.BytesRead()))
 }
 
 // WrittenSinceLast returns bytes written from last call (download).
@@ -119,8 +302,16 @@ func (r *Recorder) WrittenSinceLast() int {
 	if r.base == nil {
 		return 0
 	}
-	writtenSinceLast := r.base.BytesWritten() - r.totalWrite
-	r.totalWrite = r.base.BytesWritten()
 
-	return max(writtenSinceLast, 0)
+	return sinceLast(&r.totalWrite, int64(r.base.BytesWritten()))
+}
+
+// sinceLast stores current counter value into last and returns bytes counted since the previous one.
+// Counters start over on each connect, so a value below the previous one is counted from zero.
+func sinceLast(last *atomic.Int64, current int64) int {
+	if prev := last.Swap(current); current >= prev {
+		return int(current - prev)
+	}
+
+	return int(current)
 }
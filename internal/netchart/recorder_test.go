@@ -1,6 +1,7 @@
 package netchart
 
 import (
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -18,33 +19,225 @@ func TestRecorder_NilSource(t *testing.T) {
 }
 
 func TestRecorder(t *testing.T) {
-	incR, incW := 0, 0
-	i := 0
+	var read, written int
 	sourceMock := mocks.NewMockSource(gomock.NewController(t))
-	sourceMock.EXPECT().BytesRead().DoAndReturn(func() int {
-		i++
-		incR += 1 * i * bytesToMb
-		return incR
-	}).AnyTimes()
-	sourceMock.EXPECT().BytesWritten().DoAndReturn(func() int {
-		i++
-		incW += 1 * i * bytesToMb
-		return incW
-	}).AnyTimes()
+	sourceMock.EXPECT().BytesRead().DoAndReturn(func() int { return read }).AnyTimes()
+	sourceMock.EXPECT().BytesWritten().DoAndReturn(func() int { return written }).AnyTimes()
 
+	rec := NewRecorder(sourceMock, WithUnit(BytesPerSecond), WithResolutions([]Resolution{
+		{Interval: 500 * time.Millisecond, Span: 2 * time.Second},
+	}))
+	require.Equal(t, 500*time.Millisecond, rec.RecordInterval())
+	require.Equal(t, BytesPerSecond, rec.Unit())
+
+	now := time.Now()
+	for i := 1; i <= 6; i++ {
+		read += i * 100
+		written += i * 10
+		now = now.Add(rec.RecordInterval())
+		rec.Sample(now)
+	}
+
+	// Rates per second, only the last samples are kept.
+	require.Equal(t, []float64{600, 800, 1000, 1200}, rec.Read())
+	require.Equal(t, []float64{60, 80, 100, 120}, rec.Written())
+	require.Equal(t, 2100, rec.BytesRead())
+	require.Equal(t, 210, rec.BytesWritten())
+
+	// Counter reset of the source is not recorded as negative traffic.
+	read, written = 0, 0
+	rec.Sample(now.Add(rec.RecordInterval()))
+	require.Equal(t, []float64{800, 1000, 1200, 0}, rec.Read())
+}
+
+func TestRecorder_SinceLast(t *testing.T) {
+	var read, written int
+	sourceMock := mocks.NewMockSource(gomock.NewController(t))
+	sourceMock.EXPECT().BytesRead().DoAndReturn(func() int { return read }).AnyTimes()
+	sourceMock.EXPECT().BytesWritten().DoAndReturn(func() int { return written }).AnyTimes()
 	rec := NewRecorder(sourceMock)
-	rec.recordLimit = 10
-	rec.interval = time.Millisecond
 
-	require.Equal(t, rec.interval, rec.RecordInterval())
+	read, written = 100, 10
+	require.Equal(t, 100, rec.ReadSinceLast())
+	require.Equal(t, 10, rec.WrittenSinceLast())
+	read, written = 150, 30
+	require.Equal(t, 50, rec.ReadSinceLast())
+	require.Equal(t, 20, rec.WrittenSinceLast())
+
+	// Counters start over after reconnect, traffic of the new connection is still counted.
+	read, written = 40, 5
+	require.Equal(t, 40, rec.ReadSinceLast())
+	require.Equal(t, 5, rec.WrittenSinceLast())
+	require.Equal(t, 0, rec.ReadSinceLast())
+}
+
+func TestRecorder_Idle(t *testing.T) {
+	source := &staticSource{}
+	rec := NewRecorder(source, WithUnit(BytesPerSecond), WithResolutions([]Resolution{
+		{Interval: time.Second, Span: 4 * time.Second},
+		{Interval: 2 * time.Second, Span: 8 * time.Second},
+	}))
+
+	// Not sampled for a while, e.g. disconnected: the time passed is shown as idle.
+	now := time.Now().Add(-time.Hour)
+	source.read = 10
+	rec.Sample(now)
+	source.read = 30
+	rec.Sample(now.Add(time.Second))
+	require.Equal(t, []float64{10, 20}, rec.tiers[0].snapshot.Load().read)
+	require.Equal(t, []float64{0, 0, 0, 0}, rec.Read())
+	read, _, _ := rec.Range(8 * time.Second)
+	require.Equal(t, []float64{0, 0, 0, 0}, read)
+
+	// Sampling again records missed intervals as zeroes.
+	now = now.Add(4 * time.Second)
+	source.read = 40
+	rec.Sample(now)
+	require.Equal(t, []float64{20, 0, 0, 10}, rec.tiers[0].snapshot.Load().read)
+	require.Equal(t, []float64{15, 0}, rec.tiers[1].snapshot.Load().read)
+}
+
+func TestRecorder_Units(t *testing.T) {
+	for _, tc := range []struct {
+		unit Unit
+		want float64
+	}{
+		{BytesPerSecond, 1000},
+		{BitsPerSecond, 8000},
+		{MegabitsPerSecond, 0.008}, // Sub-megabit traffic is not rounded to zero.
+	} {
+		rec := NewRecorder(&staticSource{read: 1000}, WithUnit(tc.unit))
+		rec.Sample(time.Now())
+		require.InDelta(t, tc.want, rec.Read()[0], 1e-12, tc.unit.Name)
+	}
+}
+
+type staticSource struct{ read, written int }
+
+func (s *staticSource) BytesRead() int    { return s.read }
+func (s *staticSource) BytesWritten() int { return s.written }
+
+func TestRing(t *testing.T) {
+	r := newRing(3)
+	require.Empty(t, r.values())
+	r.push(1)
+	r.push(2)
+	require.Equal(t, []float64{1, 2}, r.values())
+	r.push(3)
+	r.push(4)
+	require.Equal(t, []float64{2, 3, 4}, r.values())
+
+	vals := r.values()
+	vals[0] = 100
+	require.Equal(t, []float64{2, 3, 4}, r.values(), "values are copied")
+}
+
+// TestRecorder_Concurrent must be run with -race.
+func TestRecorder_Concurrent(t *testing.T) {
+	source := &atomicSource{}
+	rec := NewRecorder(source, WithResolutions([]Resolution{
+		{Interval: time.Millisecond, Span: 10 * time.Millisecond},
+		{Interval: 2 * time.Millisecond, Span: 20 * time.Millisecond},
+	}))
+	hub := NewHub(time.Millisecond)
+	hub.Activate(rec, func(int, int) {})
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for range 1000 {
+			source.n.Add(1000)
+		}
+	}()
+	for {
+		select {
+		case <-done:
+			hub.Deactivate(rec)
+			require.LessOrEqual(t, len(rec.Read()), 10)
+			return
+		default:
+		}
+		read, written := rec.Read(), rec.Written()
+		require.Len(t, written, len(read))
+		r, w, _ := rec.Range(20 * time.Millisecond)
+		require.Len(t, r, 10)
+		require.Len(t, w, 10)
+		_, _ = rec.BytesRead(), rec.BytesWritten()
+	}
+}
+
+type atomicSource struct{ n atomic.Int64 }
+
+func (s *atomicSource) BytesRead() int    { return int(s.n.Load()) }
+func (s *atomicSource) BytesWritten() int { return int(s.n.Load()) }
+
+func TestRecorder_Resolutions(t *testing.T) {
+	rec := NewRecorder(nil, WithResolutions([]Resolution{
+		{Interval: time.Second, Span: 4 * time.Second},
+		{Interval: 2 * time.Second, Span: 6 * time.Second},
+		{Interval: 6 * time.Second, Span: 12 * time.Second},
+	}))
+	require.Equal(t, []int{1, 2, 3}, []int{rec.tiers[0].ratio, rec.tiers[1].ratio, rec.tiers[2].ratio})
+
+	for i := 1; i <= 12; i++ {
+		rec.record(float64(i), float64(i*10), time.Now())
+	}
+
+	// Finest tier keeps only the last samples.
+	require.Equal(t, []float64{9, 10, 11, 12}, rec.Read())
+	require.Equal(t, []float64{90, 100, 110, 120}, rec.Written())
+
+	// Coarser tiers keep averages of the finer ones.
+	read, written, interval := rec.Range(6 * time.Second)
+	require.Equal(t, 2*time.Second, interval)
+	require.Equal(t, []float64{7.5, 9.5, 11.5}, read)
+	require.Equal(t, []float64{75, 95, 115}, written)
 
-	rec.Start()
-	<-time.After(time.Millisecond * 20)
-	rec.Stop()
+	read, _, interval = rec.Range(12 * time.Second)
+	require.Equal(t, 6*time.Second, interval)
+	require.Equal(t, []float64{3.5, 9.5}, read)
+
+	// Short range is taken from the finest tier.
+	read, _, interval = rec.Range(2 * time.Second)
+	require.Equal(t, time.Second, interval)
+	require.Equal(t, []float64{11, 12}, read)
+
+	// Range longer than recorded data is padded with zeroes, longer than any tier is cut to the coarsest.
+	rec.tiers = newTiers([]Resolution{rec.tiers[0].Resolution})
+	rec.record(1, 1, time.Now())
+	read, _, _ = rec.Range(3 * time.Second)
+	require.Equal(t, []float64{0, 0, 1}, read)
+	read, _, _ = rec.Range(time.Hour)
+	require.Len(t, read, 4)
+}
+
+func TestDefaultResolutions_Bounded(t *testing.T) {
+	total := 0
+	for _, tr := range newTiers(DefaultResolutions) {
+		total += tr.limit
+	}
+	require.Equal(t, 120+360+1440+720, total)
+}
+
+func TestRecorder_SampleBytes(t *testing.T) {
+	total := 0
+	sourceMock := mocks.NewMockSource(gomock.NewController(t))
+	sourceMock.EXPECT().BytesRead().DoAndReturn(func() int { return total }).AnyTimes()
+	sourceMock.EXPECT().BytesWritten().DoAndReturn(func() int { return 2 * total }).AnyTimes()
+
+	var samples [][2]int
+	rec := NewRecorder(sourceMock)
+	sample := func(now time.Time) {
+		read, written := rec.Sample(now)
+		samples = append(samples, [2]int{read, written})
+	}
 
-	require.Equal(t, []float64{25, 29, 33, 37, 41, 45, 49, 53, 57, 61, 65}, rec.Read())
-	require.Equal(t, []float64{27, 31, 35, 39, 43, 47, 51, 55, 59, 63, 67}, rec.Written())
+	now := time.Now()
+	total = 100
+	sample(now)
+	sample(now.Add(time.Second)) // The counter does not change.
+	total = 150
+	sample(now.Add(2 * time.Second))
 
-	require.Equal(t, 150875000, rec.BytesWritten())
-	require.Equal(t, 142375000, rec.BytesRead())
+	require.Equal(t, [][2]int{{100, 200}, {0, 0}, {50, 100}}, samples)
 }
@@ -0,0 +1,205 @@
+/*
+Package netrules keeps rules that connect or disconnect depending on the network the system is connected to.
+*/
+package netrules
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/goxray/desktop/internal/osspecific/netid"
+)
+
+// Field is a network identity field the rule is matched against.
+type Field string
+
+const (
+	FieldGatewayIP  Field = "gateway_ip"
+	FieldGatewayMAC Field = "gateway_mac"
+	FieldInterface  Field = "interface"
+	FieldDNSSuffix  Field = "dns_suffix"
+)
+
+// Action is performed when the rule matches.
+type Action string
+
+const (
+	ActionConnect    Action = "connect"
+	ActionDisconnect Action = "disconnect"
+)
+
+var (
+	ErrUnknownField  = errors.New("unknown network field")
+	ErrUnknownAction = errors.New("unknown rule action")
+	ErrEmptyValue    = errors.New("rule value is empty")
+	ErrNoItem        = errors.New("connection for the rule is not selected")
+	ErrRuleNotFound  = errors.New("rule not found")
+)
+
+// Fields lists all supported fields.
+func Fields() []Field {
+	return []Field{FieldGatewayIP, FieldGatewayMAC, FieldInterface, FieldDNSSuffix}
+}
+
+// Value returns the field of id.
+func (f Field) Value(id netid.Identity) string {
+	switch f {
+	case FieldGatewayIP:
+		return id.GatewayIP
+	case FieldGatewayMAC:
+		return id.GatewayMAC
+	case FieldInterface:
+		return id.Interface
+	case FieldDNSSuffix:
+		return id.DNSSuffix
+	}
+
+	return ""
+}
+
+// Rule connects ItemID or disconnects when the current network Field has Value.
+type Rule struct {
+	Field  Field  `json:"field"`
+	Value  string `json:"value"`
+	Action Action `json:"action"`
+	ItemID string `json:"item_id,omitempty"` // Connection to use for ActionConnect.
+}
+
+func (r Rule) Validate() error {
+	if !slices.Contains(Fields(), r.Field) {
+		return fmt.Errorf("%w: %s", ErrUnknownField, r.Field)
+	}
+	if strings.TrimSpace(r.Value) == "" {
+		return ErrEmptyValue
+	}
+
+	switch r.Action {
+	case ActionConnect:
+		if r.ItemID == "" {
+			return ErrNoItem
+		}
+	case ActionDisconnect:
+	default:
+		return fmt.Errorf("%w: %s", ErrUnknownAction, r.Action)
+	}
+
+	return nil
+}
+
+// Matches reports whether the network with id is matched by the rule.
+// Addresses are compared by value, names are case-insensitive, DNS suffix also matches subdomains.
+func (r Rule) Matches(id netid.Identity) bool {
+	actual := r.Field.Value(id)
+	if actual == "" {
+		return false
+	}
+	want := strings.TrimSpace(r.Value)
+
+	switch r.Field {
+	case FieldGatewayIP:
+		if ip := net.ParseIP(want); ip != nil {
+			return ip.Equal(net.ParseIP(actual))
+		}
+	case FieldGatewayMAC:
+		wantMAC, err1 := net.ParseMAC(want)
+		actualMAC, err2 := net.ParseMAC(actual)
+		if err1 == nil && err2 == nil {
+			return wantMAC.String() == actualMAC.String()
+		}
+	case FieldDNSSuffix:
+		want = strings.ToLower(strings.Trim(want, "."))
+		actual = strings.ToLower(actual)
+
+		return actual == want || strings.HasSuffix(actual, "."+want)
+	}
+
+	return strings.EqualFold(actual, want)
+}
+
+// Rules is an ordered collection of rules, the first matching rule wins.
+type Rules struct {
+	mu       sync.Mutex
+	rules    []Rule
+	onChange func()
+}
+
+func New() *Rules {
+	return &Rules{onChange: func() {}}
+}
+
+// OnChange sets handler that is called after rules are added or removed, but not loaded.
+func (r *Rules) OnChange(fn func()) {
+	r.onChange = fn
+}
+
+func (r *Rules) All() []Rule {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return slices.Clone(r.rules)
+}
+
+func (r *Rules) Add(rule Rule) error {
+	if err := rule.Validate(); err != nil {
+		return err
+	}
+	if rule.Action == ActionDisconnect {
+		rule.ItemID = ""
+	}
+
+	r.mu.Lock()
+	r.rules = append(r.rules, rule)
+	r.mu.Unlock()
+	r.onChange()
+
+	return nil
+}
+
+func (r *Rules) Remove(i int) error {
+	r.mu.Lock()
+	if i < 0 || i >= len(r.rules) {
+		r.mu.Unlock()
+
+		return ErrRuleNotFound
+	}
+	r.rules = slices.Delete(r.rules, i, i+1)
+	r.mu.Unlock()
+	r.onChange()
+
+	return nil
+}
+
+// Load replaces rules with saved ones, invalid rules are skipped.
+func (r *Rules) Load(rules []Rule) {
+	valid := make([]Rule, 0, len(rules))
+	for _, rule := range rules {
+		if err := rule.Validate(); err != nil {
+			slog.Error("failed to load network rule", "error", err)
+			continue
+		}
+		valid = append(valid, rule)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.rules = valid
+}
+
+// Match returns the first rule matching the network with id.
+func (r *Rules) Match(id netid.Identity) (Rule, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, rule := range r.rules {
+		if rule.Matches(id) {
+			return rule, true
+		}
+	}
+
+	return Rule{}, false
+}
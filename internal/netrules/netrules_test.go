@@ -0,0 +1,90 @@
+package netrules
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/goxray/desktop/internal/osspecific/netid"
+)
+
+func TestRule_Validate(t *testing.T) {
+	require.NoError(t, Rule{Field: FieldGatewayIP, Value: "192.168.1.1", Action: ActionDisconnect}.Validate())
+	require.NoError(t, Rule{Field: FieldInterface, Value: "wlan0", Action: ActionConnect, ItemID: "id"}.Validate())
+
+	require.ErrorIs(t, Rule{Field: "ssid", Value: "x", Action: ActionDisconnect}.Validate(), ErrUnknownField)
+	require.ErrorIs(t, Rule{Field: FieldInterface, Value: " ", Action: ActionDisconnect}.Validate(), ErrEmptyValue)
+	require.ErrorIs(t, Rule{Field: FieldInterface, Value: "wlan0", Action: "toggle"}.Validate(), ErrUnknownAction)
+	require.ErrorIs(t, Rule{Field: FieldInterface, Value: "wlan0", Action: ActionConnect}.Validate(), ErrNoItem)
+}
+
+func TestRule_Matches(t *testing.T) {
+	office := netid.Identity{
+		GatewayIP:  "10.0.0.1",
+		GatewayMAC: "AA:BB:CC:DD:EE:FF",
+		Interface:  "eth0",
+		DNSSuffix:  "corp.example.com",
+	}
+
+	tests := []struct {
+		field Field
+		value string
+		want  bool
+	}{
+		{FieldGatewayIP, "10.0.0.1", true},
+		{FieldGatewayIP, " 10.0.0.1 ", true},
+		{FieldGatewayIP, "10.0.0.2", false},
+		{FieldGatewayMAC, "aa:bb:cc:dd:ee:ff", true},
+		{FieldGatewayMAC, "aa-bb-cc-dd-ee-ff", true},
+		{FieldGatewayMAC, "aa:bb:cc:dd:ee:00", false},
+		{FieldInterface, "eth0", true},
+		{FieldInterface, "wlan0", false},
+		{FieldDNSSuffix, "corp.example.com", true},
+		{FieldDNSSuffix, "example.com.", true},
+		{FieldDNSSuffix, "ample.com", false},
+	}
+	for _, tt := range tests {
+		rule := Rule{Field: tt.field, Value: tt.value, Action: ActionDisconnect}
+		require.Equal(t, tt.want, rule.Matches(office), "%s=%q", tt.field, tt.value)
+	}
+
+	// Undetected fields never match.
+	require.False(t, Rule{Field: FieldGatewayMAC, Value: "aa:bb:cc:dd:ee:ff"}.Matches(netid.Identity{}))
+}
+
+func TestRules(t *testing.T) {
+	rules := New()
+	changes := 0
+	rules.OnChange(func() { changes++ })
+
+	home := netid.Identity{GatewayIP: "192.168.1.1", Interface: "wlan0"}
+	_, ok := rules.Match(home)
+	require.False(t, ok)
+
+	require.ErrorIs(t, rules.Add(Rule{Field: FieldInterface, Action: ActionDisconnect}), ErrEmptyValue)
+	require.NoError(t, rules.Add(Rule{Field: FieldInterface, Value: "wlan0", Action: ActionConnect, ItemID: "vpn"}))
+	require.NoError(t, rules.Add(Rule{Field: FieldGatewayIP, Value: "192.168.1.1", Action: ActionDisconnect, ItemID: "dropped"}))
+	require.Equal(t, 2, changes)
+	require.Empty(t, rules.All()[1].ItemID, "item is not used by disconnect rules")
+
+	// The first matching rule wins.
+	rule, ok := rules.Match(home)
+	require.True(t, ok)
+	require.Equal(t, ActionConnect, rule.Action)
+	require.Equal(t, "vpn", rule.ItemID)
+
+	require.ErrorIs(t, rules.Remove(2), ErrRuleNotFound)
+	require.NoError(t, rules.Remove(0))
+	require.Equal(t, 3, changes)
+	rule, ok = rules.Match(home)
+	require.True(t, ok)
+	require.Equal(t, ActionDisconnect, rule.Action)
+
+	// Load skips invalid rules and does not trigger changes.
+	rules.Load([]Rule{
+		{Field: FieldDNSSuffix, Value: "example.com", Action: ActionDisconnect},
+		{Field: "unknown", Value: "x", Action: ActionDisconnect},
+	})
+	require.Len(t, rules.All(), 1)
+	require.Equal(t, 3, changes)
+}
@@ -0,0 +1,89 @@
+/*
+Package netid identifies the network the system is connected to, e.g. to tell home network from office one.
+*/
+package netid
+
+import (
+	"bufio"
+	"io"
+	"log/slog"
+	"net"
+	"strings"
+
+	"github.com/jackpal/gateway"
+)
+
+// Identity describes the current network, fields that can not be detected are left empty.
+type Identity struct {
+	GatewayIP  string
+	GatewayMAC string
+	Interface  string
+	DNSSuffix  string
+}
+
+// Current detects identity of the network behind the default route, it never fails and returns
+// whatever could be detected.
+func Current() Identity {
+	id := Identity{}
+
+	gw, err := gateway.DiscoverGateway()
+	if err != nil {
+		slog.Debug("discover gateway", "err", err)
+	} else {
+		id.GatewayIP = gw.String()
+		id.GatewayMAC = gatewayMAC(gw)
+	}
+
+	local, err := gateway.DiscoverInterface()
+	if err != nil {
+		slog.Debug("discover interface", "err", err)
+	} else {
+		id.Interface = interfaceByIP(local)
+	}
+
+	id.DNSSuffix = dnsSuffix()
+
+	return id
+}
+
+func interfaceByIP(ip net.IP) string {
+	ifaces, err := net.Interfaces()
+	if err != nil {
+		return ""
+	}
+
+	for _, iface := range ifaces {
+		addrs, err := iface.Addrs()
+		if err != nil {
+			continue
+		}
+		for _, addr := range addrs {
+			if ipNet, ok := addr.(*net.IPNet); ok && ipNet.IP.Equal(ip) {
+				return iface.Name
+			}
+		}
+	}
+
+	return ""
+}
+
+// parseResolvConf returns the first search domain (or domain) of resolv.conf.
+func parseResolvConf(r io.Reader) string {
+	domain := ""
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 2 {
+			continue
+		}
+
+		switch fields[0] {
+		case "search":
+			return strings.TrimSuffix(fields[1], ".")
+		case "domain":
+			domain = strings.TrimSuffix(fields[1], ".")
+		}
+	}
+
+	return domain
+}
@@ -0,0 +1,37 @@
+package netid
+
+import (
+	"net"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+func gatewayMAC(ip net.IP) string {
+	// Output format: "? (192.168.1.1) at aa:bb:cc:dd:ee:ff on en0 ifscope [ethernet]".
+	out, err := exec.Command("arp", "-n", ip.String()).Output()
+	if err != nil {
+		return ""
+	}
+
+	fields := strings.Fields(string(out))
+	for i, f := range fields {
+		if f == "at" && i+1 < len(fields) {
+			if _, err := net.ParseMAC(fields[i+1]); err == nil {
+				return fields[i+1]
+			}
+		}
+	}
+
+	return ""
+}
+
+func dnsSuffix() string {
+	f, err := os.Open("/etc/resolv.conf")
+	if err != nil {
+		return ""
+	}
+	defer f.Close()
+
+	return parseResolvConf(f)
+}
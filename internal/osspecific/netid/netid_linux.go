@@ -0,0 +1,48 @@
+package netid
+
+import (
+	"bufio"
+	"io"
+	"net"
+	"os"
+	"strings"
+)
+
+func gatewayMAC(ip net.IP) string {
+	f, err := os.Open("/proc/net/arp")
+	if err != nil {
+		return ""
+	}
+	defer f.Close()
+
+	return parseProcARP(f, ip)
+}
+
+// parseProcARP looks up hardware address of ip in /proc/net/arp table.
+func parseProcARP(r io.Reader, ip net.IP) string {
+	scanner := bufio.NewScanner(r)
+	scanner.Scan() // Skip header.
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 4 || !ip.Equal(net.ParseIP(fields[0])) {
+			continue
+		}
+		if fields[3] == "00:00:00:00:00:00" { // Incomplete entry.
+			return ""
+		}
+
+		return fields[3]
+	}
+
+	return ""
+}
+
+func dnsSuffix() string {
+	f, err := os.Open("/etc/resolv.conf")
+	if err != nil {
+		return ""
+	}
+	defer f.Close()
+
+	return parseResolvConf(f)
+}
@@ -0,0 +1,20 @@
+package netid
+
+import (
+	"net"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseProcARP(t *testing.T) {
+	table := `IP address       HW type     Flags       HW address            Mask     Device
+192.168.1.10     0x1         0x2         aa:bb:cc:dd:ee:01     *        wlan0
+192.168.1.1      0x1         0x2         aa:bb:cc:dd:ee:ff     *        wlan0
+192.168.1.20     0x1         0x0         00:00:00:00:00:00     *        wlan0
+`
+	require.Equal(t, "aa:bb:cc:dd:ee:ff", parseProcARP(strings.NewReader(table), net.ParseIP("192.168.1.1")))
+	require.Equal(t, "", parseProcARP(strings.NewReader(table), net.ParseIP("192.168.1.20")))
+	require.Equal(t, "", parseProcARP(strings.NewReader(table), net.ParseIP("10.0.0.1")))
+}
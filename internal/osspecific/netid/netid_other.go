@@ -0,0 +1,13 @@
+//go:build !linux && !darwin
+
+package netid
+
+import "net"
+
+func gatewayMAC(net.IP) string {
+	return "" // Not implemented on this platform, rules can still match other fields.
+}
+
+func dnsSuffix() string {
+	return ""
+}
@@ -0,0 +1,20 @@
+package netid
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseResolvConf(t *testing.T) {
+	require.Equal(t, "", parseResolvConf(strings.NewReader("nameserver 1.1.1.1\n")))
+	require.Equal(t, "corp.example.com", parseResolvConf(strings.NewReader(
+		"# comment\ndomain example.com\nsearch corp.example.com. lab.example.com\nnameserver 10.0.0.1\n")))
+	require.Equal(t, "example.com", parseResolvConf(strings.NewReader("domain example.com\nnameserver 10.0.0.1\n")))
+}
+
+func TestCurrent(t *testing.T) {
+	// Detection depends on the host, but must never panic or block.
+	_ = Current()
+}
@@ -0,0 +1,53 @@
+package netmon
+
+import (
+	"context"
+	"time"
+)
+
+const (
+	clockCheckInterval = 5 * time.Second
+	// clockJumpThreshold is how much wall clock may run ahead of monotonic clock before it counts as resume.
+	clockJumpThreshold = 15 * time.Second
+)
+
+// clock provides both wall and monotonic time, so tests can simulate suspend.
+type clock interface {
+	Wall() time.Time
+	// Mono returns monotonic time since an arbitrary point, it does not advance while the system is suspended.
+	Mono() time.Duration
+}
+
+var processStart = time.Now()
+
+type realClock struct{}
+
+func (realClock) Wall() time.Time {
+	return time.Now().Round(0) // Strip monotonic reading.
+}
+
+func (realClock) Mono() time.Duration {
+	return time.Since(processStart)
+}
+
+// watchClock emits KindResume when wall clock jumps ahead of monotonic clock, which happens after suspend.
+// It blocks till ctx is done.
+func watchClock(ctx context.Context, c clock, interval, threshold time.Duration, emit func(Kind)) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	prevWall, prevMono := c.Wall(), c.Mono()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		wall, mono := c.Wall(), c.Mono()
+		if wall.Sub(prevWall)-(mono-prevMono) > threshold {
+			emit(KindResume)
+		}
+		prevWall, prevMono = wall, mono
+	}
+}
@@ -0,0 +1,90 @@
+/*
+Package netmon watches for network changes and system resume, so the active connection can be checked or restored.
+*/
+package netmon
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Kind is a type of network event, greater kinds are more disruptive.
+type Kind int
+
+const (
+	KindRoute Kind = iota + 1
+	KindLink
+	KindResume
+)
+
+func (k Kind) String() string {
+	switch k {
+	case KindRoute:
+		return "route"
+	case KindLink:
+		return "link"
+	case KindResume:
+		return "resume"
+	}
+
+	return "unknown"
+}
+
+// Event is a single network change notification.
+type Event struct {
+	Kind Kind
+	Time time.Time
+}
+
+// Monitor is a source of network events.
+type Monitor interface {
+	// Events returns channel that is closed when the monitor is closed.
+	Events() <-chan Event
+	Close() error
+}
+
+// New creates Monitor for the current OS, it never fails: if OS specific events are not available
+// only system resume is detected.
+func New() Monitor {
+	ctx, cancel := context.WithCancel(context.Background())
+	m := &monitor{events: make(chan Event, 16), cancel: cancel}
+
+	m.wg.Add(2)
+	go func() {
+		defer m.wg.Done()
+		watchClock(ctx, realClock{}, clockCheckInterval, clockJumpThreshold, m.emit)
+	}()
+	go func() {
+		defer m.wg.Done()
+		watchSystem(ctx, m.emit)
+	}()
+
+	return m
+}
+
+type monitor struct {
+	events chan Event
+	cancel func()
+	wg     sync.WaitGroup
+}
+
+func (m *monitor) Events() <-chan Event {
+	return m.events
+}
+
+func (m *monitor) Close() error {
+	m.cancel()
+	m.wg.Wait()
+	close(m.events)
+
+	return nil
+}
+
+// emit never blocks, events are only a hint so dropping them on a full buffer is fine.
+func (m *monitor) emit(kind Kind) {
+	select {
+	case m.events <- Event{Kind: kind, Time: time.Now()}:
+	default:
+	}
+}
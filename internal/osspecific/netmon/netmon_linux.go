@@ -0,0 +1,74 @@
+package netmon
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/vishvananda/netlink"
+)
+
+// tunLinkType is netlink link type of TUN/TAP devices, including the one created by the VPN client itself.
+const tunLinkType = "tuntap"
+
+// watchSystem emits link and route changes received from netlink. Changes of TUN devices and route exceptions
+// are ignored, as they are caused by connecting or disconnecting the VPN.
+func watchSystem(ctx context.Context, emit func(Kind)) {
+	links := make(chan netlink.LinkUpdate)
+	routes := make(chan netlink.RouteUpdate)
+	onErr := func(err error) { slog.Debug("netlink subscription error", "err", err) }
+
+	if err := netlink.LinkSubscribeWithOptions(links, ctx.Done(), netlink.LinkSubscribeOptions{ErrorCallback: onErr}); err != nil {
+		slog.Warn("subscribe to link updates failed, network changes are not monitored", "err", err)
+		links = nil
+	}
+	if err := netlink.RouteSubscribeWithOptions(routes, ctx.Done(), netlink.RouteSubscribeOptions{ErrorCallback: onErr}); err != nil {
+		slog.Warn("subscribe to route updates failed, network changes are not monitored", "err", err)
+		routes = nil
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case upd, ok := <-links:
+			if !ok {
+				links = nil
+				continue
+			}
+			if upd.Link != nil && upd.Link.Type() == tunLinkType {
+				continue
+			}
+			emit(KindLink)
+		case upd, ok := <-routes:
+			if !ok {
+				routes = nil
+				continue
+			}
+			if isExceptionRoute(upd.Route) || isTunRoute(upd.Route) {
+				continue
+			}
+			emit(KindRoute)
+		}
+	}
+}
+
+// isExceptionRoute reports whether r is a host route through a gateway. VPN clients add one for the server
+// on connect, so its traffic goes past the TUN device, and delete it on disconnect.
+func isExceptionRoute(r netlink.Route) bool {
+	if r.Dst == nil || r.Gw == nil {
+		return false
+	}
+	ones, bits := r.Dst.Mask.Size()
+
+	return ones == bits
+}
+
+func isTunRoute(r netlink.Route) bool {
+	link, err := netlink.LinkByIndex(r.LinkIndex)
+	if err != nil {
+		// Link is already gone, most likely the TUN device was removed on disconnect.
+		return true
+	}
+
+	return link.Type() == tunLinkType
+}
@@ -0,0 +1,25 @@
+package netmon
+
+import (
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/vishvananda/netlink"
+)
+
+func TestIsExceptionRoute(t *testing.T) {
+	gw := net.ParseIP("192.168.1.1")
+	cidr := func(s string) *net.IPNet {
+		_, n, err := net.ParseCIDR(s)
+		require.NoError(t, err)
+		return n
+	}
+
+	require.True(t, isExceptionRoute(netlink.Route{Dst: cidr("203.0.113.7/32"), Gw: gw}), "server route of the client")
+	require.True(t, isExceptionRoute(netlink.Route{Dst: cidr("2001:db8::7/128"), Gw: gw}))
+	require.False(t, isExceptionRoute(netlink.Route{Gw: gw}), "default route")
+	require.False(t, isExceptionRoute(netlink.Route{Dst: cidr("192.168.1.0/24")}), "subnet of the link")
+	require.False(t, isExceptionRoute(netlink.Route{Dst: cidr("10.0.0.0/8"), Gw: gw}))
+	require.False(t, isExceptionRoute(netlink.Route{Dst: cidr("192.168.1.5/32")}), "host route of the link")
+}
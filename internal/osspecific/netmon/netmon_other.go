@@ -0,0 +1,13 @@
+//go:build !linux
+
+package netmon
+
+import (
+	"context"
+	"log/slog"
+)
+
+func watchSystem(ctx context.Context, _ func(Kind)) {
+	slog.Debug("network change monitoring not implemented on this platform, only resume is detected")
+	<-ctx.Done()
+}
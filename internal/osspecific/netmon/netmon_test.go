@@ -0,0 +1,169 @@
+package netmon
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type fakeMonitor struct {
+	events chan Event
+}
+
+func newFakeMonitor() *fakeMonitor {
+	return &fakeMonitor{events: make(chan Event, 16)}
+}
+
+func (f *fakeMonitor) Events() <-chan Event {
+	return f.events
+}
+
+func (f *fakeMonitor) Close() error {
+	close(f.events)
+
+	return nil
+}
+
+func (f *fakeMonitor) send(kind Kind) {
+	f.events <- Event{Kind: kind, Time: time.Now()}
+}
+
+type fakeClock struct {
+	mu   sync.Mutex
+	wall time.Time
+	mono time.Duration
+}
+
+func (f *fakeClock) Wall() time.Time {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return f.wall
+}
+
+func (f *fakeClock) Mono() time.Duration {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return f.mono
+}
+
+func (f *fakeClock) advance(wall, mono time.Duration) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.wall = f.wall.Add(wall)
+	f.mono += mono
+}
+
+func TestWatcher_Debounce(t *testing.T) {
+	mon := newFakeMonitor()
+	got := make(chan Event, 10)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	go NewWatcher(mon, 20*time.Millisecond, 0).Run(ctx, func(ev Event) { got <- ev })
+
+	// Burst is reported once with the most disruptive kind.
+	mon.send(KindRoute)
+	mon.send(KindLink)
+	mon.send(KindRoute)
+	select {
+	case ev := <-got:
+		require.Equal(t, KindLink, ev.Kind)
+	case <-time.After(time.Second):
+		t.Fatal("event not reported")
+	}
+	select {
+	case ev := <-got:
+		t.Fatalf("unexpected event %s", ev.Kind)
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	// Next burst is reported separately.
+	mon.send(KindResume)
+	select {
+	case ev := <-got:
+		require.Equal(t, KindResume, ev.Kind)
+	case <-time.After(time.Second):
+		t.Fatal("event not reported")
+	}
+}
+
+func TestWatcher_Cooldown(t *testing.T) {
+	mon := newFakeMonitor()
+	got := make(chan Event, 10)
+	done := make(chan struct{})
+
+	go func() {
+		defer close(done)
+		NewWatcher(mon, 10*time.Millisecond, time.Hour).Run(context.Background(), func(ev Event) { got <- ev })
+	}()
+
+	mon.send(KindLink)
+	select {
+	case <-got:
+	case <-time.After(time.Second):
+		t.Fatal("event not reported")
+	}
+
+	// Changes caused by reacting to the event are ignored.
+	mon.send(KindRoute)
+	mon.send(KindLink)
+	select {
+	case ev := <-got:
+		t.Fatalf("unexpected event %s", ev.Kind)
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	// Watcher stops when the source is closed.
+	require.NoError(t, mon.Close())
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("watcher not stopped")
+	}
+}
+
+func TestWatchClock(t *testing.T) {
+	clk := &fakeClock{wall: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}
+	ctx, cancel := context.WithCancel(context.Background())
+	got := make(chan Kind, 10)
+	done := make(chan struct{})
+
+	go func() {
+		defer close(done)
+		watchClock(ctx, clk, time.Millisecond, time.Minute, func(k Kind) { got <- k })
+	}()
+
+	// Clocks running together or small adjustments are not a resume.
+	clk.advance(time.Second, time.Second)
+	clk.advance(30*time.Second, 0)
+	select {
+	case k := <-got:
+		t.Fatalf("unexpected event %s", k)
+	case <-time.After(20 * time.Millisecond):
+	}
+
+	// Monotonic clock stands still during suspend.
+	clk.advance(time.Hour, 0)
+	select {
+	case k := <-got:
+		require.Equal(t, KindResume, k)
+	case <-time.After(time.Second):
+		t.Fatal("resume not detected")
+	}
+
+	cancel()
+	<-done
+}
+
+func TestMonitor_Close(t *testing.T) {
+	mon := New()
+	require.NoError(t, mon.Close())
+
+	_, ok := <-mon.Events()
+	require.False(t, ok)
+}
@@ -0,0 +1,50 @@
+package netmon
+
+import (
+	"context"
+	"time"
+)
+
+// Watcher debounces events of the Monitor, bursts of changes (e.g. interface going down and up with all
+// its routes) result in a single callback.
+type Watcher struct {
+	source   Monitor
+	debounce time.Duration
+	cooldown time.Duration
+}
+
+// NewWatcher creates Watcher that waits for debounce of silence before reporting, and ignores events for
+// cooldown after each report, as reacting to an event (reconnecting) causes network changes itself.
+func NewWatcher(source Monitor, debounce, cooldown time.Duration) *Watcher {
+	return &Watcher{source: source, debounce: debounce, cooldown: cooldown}
+}
+
+// Run calls fn with the most disruptive event of each burst. It blocks till ctx is done or the source is closed.
+func (w *Watcher) Run(ctx context.Context, fn func(Event)) {
+	var pending *Event
+	var fire <-chan time.Time
+	quietUntil := time.Time{}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case ev, ok := <-w.source.Events():
+			if !ok {
+				return
+			}
+			if ev.Time.Before(quietUntil) {
+				continue
+			}
+
+			if pending == nil || ev.Kind > pending.Kind {
+				pending = &ev
+			}
+			fire = time.After(w.debounce)
+		case <-fire:
+			fn(*pending)
+			pending, fire = nil, nil
+			quietUntil = time.Now().Add(w.cooldown)
+		}
+	}
+}
@@ -0,0 +1,245 @@
+/*
+Package quota keeps traffic limits of connections per billing cycle and reports when warning thresholds are reached.
+*/
+package quota
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"maps"
+	"slices"
+	"sync"
+	"time"
+)
+
+// Exceeded is the level reported when the whole limit is used.
+const Exceeded = 100
+
+var (
+	ErrInvalidLimit     = errors.New("quota limit must be positive")
+	ErrInvalidResetDay  = errors.New("reset day must be between 1 and 31")
+	ErrInvalidThreshold = errors.New("thresholds must be between 1 and 99 percent")
+	ErrNoItem           = errors.New("connection for the quota is not selected")
+)
+
+// Quota limits traffic (uplink and downlink together) of a connection per billing cycle.
+type Quota struct {
+	Limit int64 `json:"limit"` // Bytes per billing cycle.
+	// ResetDay is the day of month the billing cycle starts, clamped to the last day of shorter months.
+	ResetDay int `json:"reset_day"`
+	// Thresholds are warning levels in percent of Limit, sorted ascending.
+	Thresholds     []int `json:"thresholds"`
+	AutoDisconnect bool  `json:"auto_disconnect"`
+}
+
+func (q Quota) Validate() error {
+	if q.Limit <= 0 {
+		return ErrInvalidLimit
+	}
+	if q.ResetDay < 1 || q.ResetDay > 31 {
+		return fmt.Errorf("%w: %d", ErrInvalidResetDay, q.ResetDay)
+	}
+	for _, t := range q.Thresholds {
+		if t < 1 || t >= Exceeded {
+			return fmt.Errorf("%w: %d", ErrInvalidThreshold, t)
+		}
+	}
+
+	return nil
+}
+
+// CycleStart returns the start of the billing cycle now belongs to.
+func (q Quota) CycleStart(now time.Time) time.Time {
+	start := resetDate(now.Year(), now.Month(), q.ResetDay, now.Location())
+	if start.After(now) {
+		start = resetDate(now.Year(), now.Month()-1, q.ResetDay, now.Location())
+	}
+
+	return start
+}
+
+// resetDate returns midnight of the day in the month, clamping the day to the month length.
+func resetDate(year int, month time.Month, day int, loc *time.Location) time.Time {
+	last := time.Date(year, month+1, 0, 0, 0, 0, 0, loc).Day()
+
+	return time.Date(year, month, min(day, last), 0, 0, 0, 0, loc)
+}
+
+// Percent returns used bytes in percent of the limit.
+func (q Quota) Percent(used int64) int {
+	if q.Limit <= 0 {
+		return 0
+	}
+
+	return int(used * 100 / q.Limit)
+}
+
+// Level returns the highest threshold reached by used bytes, Exceeded if the limit is reached and 0 if none.
+func (q Quota) Level(used int64) int {
+	percent := q.Percent(used)
+	if percent >= Exceeded {
+		return Exceeded
+	}
+
+	level := 0
+	for _, t := range q.Thresholds {
+		if percent >= t {
+			level = t
+		}
+	}
+
+	return level
+}
+
+// Alert is reported when a connection reaches a new level of its quota.
+type Alert struct {
+	ItemID string
+	Level  int // Reached threshold or Exceeded.
+	Used   int64
+	Quota  Quota
+}
+
+// Reported is the highest level reported in the billing cycle starting at Cycle.
+type Reported struct {
+	Cycle time.Time `json:"cycle"`
+	Level int       `json:"level"`
+}
+
+// Quotas keeps quotas by item ID.
+type Quotas struct {
+	mu       sync.Mutex
+	quotas   map[string]Quota
+	reported map[string]Reported
+	onChange func()
+}
+
+func New() *Quotas {
+	return &Quotas{quotas: map[string]Quota{}, reported: map[string]Reported{}, onChange: func() {}}
+}
+
+// OnChange sets handler that is called after quotas are set or removed and after a new level is reported,
+// but not loaded.
+func (q *Quotas) OnChange(fn func()) {
+	q.onChange = fn
+}
+
+func (q *Quotas) Get(itemID string) (Quota, bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	quota, ok := q.quotas[itemID]
+
+	return quota, ok
+}
+
+func (q *Quotas) All() map[string]Quota {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	return maps.Clone(q.quotas)
+}
+
+// Set replaces quota of the item, levels reached with the previous quota are reported again.
+func (q *Quotas) Set(itemID string, quota Quota) error {
+	if itemID == "" {
+		return ErrNoItem
+	}
+	if err := quota.Validate(); err != nil {
+		return err
+	}
+	quota.Thresholds = slices.Compact(slices.Sorted(slices.Values(quota.Thresholds)))
+
+	q.mu.Lock()
+	q.quotas[itemID] = quota
+	delete(q.reported, itemID)
+	q.mu.Unlock()
+	q.onChange()
+
+	return nil
+}
+
+func (q *Quotas) Remove(itemID string) {
+	q.mu.Lock()
+	delete(q.quotas, itemID)
+	delete(q.reported, itemID)
+	q.mu.Unlock()
+	q.onChange()
+}
+
+// Load replaces all quotas, invalid quotas are skipped.
+func (q *Quotas) Load(quotas map[string]Quota) {
+	valid := make(map[string]Quota, len(quotas))
+	for id, quota := range quotas {
+		if err := quota.Validate(); err != nil {
+			slog.Error("failed to load quota", "item", id, "error", err)
+			continue
+		}
+		valid[id] = quota
+	}
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.quotas = valid
+	q.reported = map[string]Reported{}
+}
+
+// AllReported returns the highest levels reported by item ID, they are kept so alerts are not repeated
+// after restart.
+func (q *Quotas) AllReported() map[string]Reported {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	return maps.Clone(q.reported)
+}
+
+// LoadReported replaces reported levels, it must be called after Load.
+func (q *Quotas) LoadReported(reported map[string]Reported) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	q.reported = maps.Clone(reported)
+	if q.reported == nil {
+		q.reported = map[string]Reported{}
+	}
+}
+
+// Check returns Alert if used bytes of the item reach a new level in the billing cycle of now.
+// Every level is reported once per cycle, see Blocks to enforce the limit.
+func (q *Quotas) Check(itemID string, used int64, now time.Time) (Alert, bool) {
+	q.mu.Lock()
+	quota, ok := q.quotas[itemID]
+	if !ok {
+		q.mu.Unlock()
+		return Alert{}, false
+	}
+
+	cycle := quota.CycleStart(now)
+	last := q.reported[itemID]
+	if !last.Cycle.Equal(cycle) {
+		last = Reported{Cycle: cycle}
+	}
+
+	level := quota.Level(used)
+	if level <= last.Level {
+		q.reported[itemID] = last
+		q.mu.Unlock()
+		return Alert{}, false
+	}
+	q.reported[itemID] = Reported{Cycle: cycle, Level: level}
+	q.mu.Unlock()
+	q.onChange()
+
+	return Alert{ItemID: itemID, Level: level, Used: used, Quota: quota}, true
+}
+
+// Blocks reports whether the item must not be connected: its quota disconnects automatically and used bytes
+// of the current billing cycle reach the limit. Unlike Check, it reports every time.
+func (q *Quotas) Blocks(itemID string, used int64) bool {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	quota, ok := q.quotas[itemID]
+
+	return ok && quota.AutoDisconnect && quota.Level(used) == Exceeded
+}
@@ -0,0 +1,110 @@
+package quota
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func date(month time.Month, day, hour int) time.Time {
+	return time.Date(2024, month, day, hour, 0, 0, 0, time.Local)
+}
+
+func TestQuota_Validate(t *testing.T) {
+	require.NoError(t, Quota{Limit: 1, ResetDay: 31, Thresholds: []int{80, 95}}.Validate())
+	require.ErrorIs(t, Quota{ResetDay: 1}.Validate(), ErrInvalidLimit)
+	require.ErrorIs(t, Quota{Limit: 1}.Validate(), ErrInvalidResetDay)
+	require.ErrorIs(t, Quota{Limit: 1, ResetDay: 32}.Validate(), ErrInvalidResetDay)
+	require.ErrorIs(t, Quota{Limit: 1, ResetDay: 1, Thresholds: []int{100}}.Validate(), ErrInvalidThreshold)
+	require.ErrorIs(t, Quota{Limit: 1, ResetDay: 1, Thresholds: []int{0}}.Validate(), ErrInvalidThreshold)
+}
+
+func TestQuota_CycleStart(t *testing.T) {
+	q := Quota{ResetDay: 15}
+	require.Equal(t, date(3, 15, 0), q.CycleStart(date(3, 15, 0)))
+	require.Equal(t, date(3, 15, 0), q.CycleStart(date(3, 20, 10)))
+	require.Equal(t, date(2, 15, 0), q.CycleStart(date(3, 14, 23)))
+	require.Equal(t, time.Date(2023, 12, 15, 0, 0, 0, 0, time.Local), q.CycleStart(date(1, 1, 0)))
+
+	// Clamped to the end of shorter months.
+	q = Quota{ResetDay: 31}
+	require.Equal(t, date(2, 29, 0), q.CycleStart(date(3, 1, 0)))
+	require.Equal(t, date(3, 31, 0), q.CycleStart(date(3, 31, 5)))
+	require.Equal(t, date(4, 30, 0), q.CycleStart(date(5, 30, 0)))
+}
+
+func TestQuota_Level(t *testing.T) {
+	q := Quota{Limit: 1000, Thresholds: []int{80, 95}}
+	require.Equal(t, 0, q.Level(799))
+	require.Equal(t, 80, q.Level(800))
+	require.Equal(t, 95, q.Level(999))
+	require.Equal(t, Exceeded, q.Level(1000))
+	require.Equal(t, 150, q.Percent(1500))
+	require.Equal(t, Exceeded, Quota{Limit: 10}.Level(20))
+}
+
+func TestQuotas(t *testing.T) {
+	changes := 0
+	quotas := New()
+	quotas.OnChange(func() { changes++ })
+
+	require.ErrorIs(t, quotas.Set("", Quota{Limit: 1, ResetDay: 1}), ErrNoItem)
+	require.ErrorIs(t, quotas.Set("a", Quota{ResetDay: 1}), ErrInvalidLimit)
+	require.NoError(t, quotas.Set("a", Quota{Limit: 1000, ResetDay: 1, Thresholds: []int{95, 80, 95}}))
+	require.Equal(t, 1, changes)
+
+	q, ok := quotas.Get("a")
+	require.True(t, ok)
+	require.Equal(t, []int{80, 95}, q.Thresholds)
+
+	_, ok = quotas.Check("b", 2000, date(1, 2, 0))
+	require.False(t, ok, "no quota")
+
+	_, ok = quotas.Check("a", 100, date(1, 2, 0))
+	require.False(t, ok)
+	alert, ok := quotas.Check("a", 900, date(1, 2, 0))
+	require.True(t, ok)
+	require.Equal(t, Alert{ItemID: "a", Level: 80, Used: 900, Quota: q}, alert)
+	_, ok = quotas.Check("a", 910, date(1, 3, 0))
+	require.False(t, ok, "already reported")
+	alert, ok = quotas.Check("a", 1000, date(1, 3, 0))
+	require.True(t, ok)
+	require.Equal(t, Exceeded, alert.Level)
+
+	// New billing cycle.
+	alert, ok = quotas.Check("a", 850, date(2, 1, 0))
+	require.True(t, ok)
+	require.Equal(t, 80, alert.Level)
+
+	require.Equal(t, 4, changes, "reported levels are saved")
+	require.Equal(t, map[string]Reported{"a": {Cycle: date(2, 1, 0), Level: 80}}, quotas.AllReported())
+
+	quotas.Remove("a")
+	require.Equal(t, 5, changes)
+	require.Empty(t, quotas.All())
+	require.Empty(t, quotas.AllReported())
+
+	quotas.Load(map[string]Quota{"a": {Limit: 1000, ResetDay: 1}, "b": {}})
+	require.Len(t, quotas.All(), 1)
+	quotas.LoadReported(map[string]Reported{"a": {Cycle: date(2, 1, 0), Level: Exceeded}})
+	require.Equal(t, 5, changes, "loading does not notify")
+	_, ok = quotas.Check("a", 1000, date(2, 3, 0))
+	require.False(t, ok, "reported before restart")
+}
+
+func TestQuotas_Blocks(t *testing.T) {
+	quotas := New()
+	require.NoError(t, quotas.Set("a", Quota{Limit: 1000, ResetDay: 1}))
+	require.NoError(t, quotas.Set("b", Quota{Limit: 1000, ResetDay: 1, AutoDisconnect: true}))
+
+	require.False(t, quotas.Blocks("a", 2000), "no auto-disconnect")
+	require.False(t, quotas.Blocks("b", 999))
+	require.False(t, quotas.Blocks("c", 2000), "no quota")
+
+	// Blocked every time, not only when the level is reported.
+	_, ok := quotas.Check("b", 1000, date(1, 2, 0))
+	require.True(t, ok)
+	require.True(t, quotas.Blocks("b", 1000))
+	require.True(t, quotas.Blocks("b", 1500))
+}
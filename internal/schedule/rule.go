@@ -0,0 +1,112 @@
+package schedule
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+)
+
+// Action is performed when the rule starts.
+type Action string
+
+const (
+	ActionConnect    Action = "connect"
+	ActionDisconnect Action = "disconnect"
+)
+
+var (
+	ErrInvalidTime   = errors.New("time must be in HH:MM format")
+	ErrUnknownAction = errors.New("unknown rule action")
+	ErrNoItem        = errors.New("connection for the rule is not selected")
+	ErrEmptyWindow   = errors.New("rule start and end must differ")
+	ErrRuleNotFound  = errors.New("rule not found")
+)
+
+// TimeOfDay is a number of minutes since midnight, it is encoded as "HH:MM".
+type TimeOfDay int
+
+func ParseTimeOfDay(s string) (TimeOfDay, error) {
+	t, err := time.Parse("15:04", s)
+	if err != nil {
+		return 0, fmt.Errorf("%w: %q", ErrInvalidTime, s)
+	}
+
+	return TimeOfDay(t.Hour()*60 + t.Minute()), nil
+}
+
+func (t TimeOfDay) String() string {
+	return fmt.Sprintf("%02d:%02d", int(t)/60, int(t)%60)
+}
+
+func (t TimeOfDay) MarshalText() ([]byte, error) {
+	return []byte(t.String()), nil
+}
+
+func (t *TimeOfDay) UnmarshalText(b []byte) error {
+	parsed, err := ParseTimeOfDay(string(b))
+	if err != nil {
+		return err
+	}
+	*t = parsed
+
+	return nil
+}
+
+// on returns the moment of t on the day of date.
+func (t TimeOfDay) on(date time.Time) time.Time {
+	y, m, d := date.Date()
+
+	return time.Date(y, m, d, int(t)/60, int(t)%60, 0, 0, date.Location())
+}
+
+// Rule performs Action at Start on Days. If End is set the rule is a window, at End connect rules
+// disconnect their connection, e.g. "connect on weekdays 09:00–18:00". End before Start spans midnight.
+type Rule struct {
+	Days   []time.Weekday `json:"days,omitempty"` // Empty means every day.
+	Start  TimeOfDay      `json:"start"`
+	End    *TimeOfDay     `json:"end,omitempty"`
+	Action Action         `json:"action"`
+	ItemID string         `json:"item_id,omitempty"` // Connection to use for ActionConnect.
+}
+
+func (r Rule) Validate() error {
+	if r.Start < 0 || r.Start >= 24*60 || (r.End != nil && (*r.End < 0 || *r.End >= 24*60)) {
+		return ErrInvalidTime
+	}
+	if r.End != nil && *r.End == r.Start {
+		return ErrEmptyWindow
+	}
+
+	switch r.Action {
+	case ActionConnect:
+		if r.ItemID == "" {
+			return ErrNoItem
+		}
+	case ActionDisconnect:
+	default:
+		return fmt.Errorf("%w: %s", ErrUnknownAction, r.Action)
+	}
+
+	return nil
+}
+
+// OnDay reports whether the rule starts on weekday.
+func (r Rule) OnDay(day time.Weekday) bool {
+	return len(r.Days) == 0 || slices.Contains(r.Days, day)
+}
+
+// window returns start and end of the rule window that starts on the day of date, end is zero if not set.
+func (r Rule) window(date time.Time) (start, end time.Time) {
+	start = r.Start.on(date)
+	if r.End == nil {
+		return start, time.Time{}
+	}
+
+	end = r.End.on(date)
+	if !end.After(start) {
+		end = r.End.on(date.AddDate(0, 0, 1))
+	}
+
+	return start, end
+}
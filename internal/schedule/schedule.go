@@ -0,0 +1,214 @@
+/*
+Package schedule connects and disconnects at the time of day set by rules.
+*/
+package schedule
+
+import (
+	"context"
+	"log/slog"
+	"slices"
+	"sync"
+	"time"
+)
+
+const (
+	checkInterval = 30 * time.Second
+	// maxCatchUp limits how far back missed triggers are looked up, e.g. after a long suspend.
+	maxCatchUp = 8 * 24 * time.Hour
+)
+
+// Trigger is an action that is due.
+type Trigger struct {
+	Action Action
+	// ItemID is the connection to connect, or to disconnect at the end of a window.
+	// Empty for disconnect rules which drop any connection.
+	ItemID string
+	At     time.Time
+}
+
+// Due returns the latest trigger of each rule that happened in (from, to], sorted by time.
+func Due(rules []Rule, from, to time.Time) []Trigger {
+	if to.Sub(from) > maxCatchUp {
+		from = to.Add(-maxCatchUp)
+	}
+
+	res := make([]Trigger, 0)
+	for _, r := range rules {
+		var latest *Trigger
+		// Windows that started the day before may end today.
+		for day := midnight(from).AddDate(0, 0, -1); !day.After(to); day = day.AddDate(0, 0, 1) {
+			if !r.OnDay(day.Weekday()) {
+				continue
+			}
+
+			start, end := r.window(day)
+			if start.After(from) && !start.After(to) && (latest == nil || start.After(latest.At)) {
+				latest = &Trigger{Action: r.Action, ItemID: r.ItemID, At: start}
+			}
+			// Only connect windows are reverted at the end, we can't know what to restore after disconnect.
+			if r.Action == ActionConnect && !end.IsZero() && end.After(from) && !end.After(to) &&
+				(latest == nil || end.After(latest.At)) {
+				latest = &Trigger{Action: ActionDisconnect, ItemID: r.ItemID, At: end}
+			}
+		}
+
+		if latest != nil {
+			res = append(res, *latest)
+		}
+	}
+	slices.SortStableFunc(res, func(a, b Trigger) int { return a.At.Compare(b.At) })
+
+	return res
+}
+
+func midnight(t time.Time) time.Time {
+	y, m, d := t.Date()
+
+	return time.Date(y, m, d, 0, 0, 0, 0, t.Location())
+}
+
+// Active returns start triggers of the windows that contain now, used to catch up on application start.
+func Active(rules []Rule, now time.Time) []Trigger {
+	res := make([]Trigger, 0)
+	for _, r := range rules {
+		if r.End == nil {
+			continue
+		}
+
+		for _, day := range []time.Time{now.AddDate(0, 0, -1), now} {
+			if !r.OnDay(day.Weekday()) {
+				continue
+			}
+			if start, end := r.window(day); !start.After(now) && end.After(now) {
+				res = append(res, Trigger{Action: r.Action, ItemID: r.ItemID, At: start})
+				break
+			}
+		}
+	}
+
+	return res
+}
+
+// Clock is the source of time for Scheduler.
+type Clock interface {
+	Now() time.Time
+	After(d time.Duration) <-chan time.Time
+}
+
+type realClock struct{}
+
+func (realClock) Now() time.Time {
+	return time.Now()
+}
+
+func (realClock) After(d time.Duration) <-chan time.Time {
+	return time.After(d)
+}
+
+// Scheduler checks rules periodically and fires triggers that became due since the last check.
+type Scheduler struct {
+	rules *Rules
+	clock Clock
+}
+
+// NewScheduler creates scheduler for rules, nil clock means system clock.
+func NewScheduler(rules *Rules, clock Clock) *Scheduler {
+	if clock == nil {
+		clock = realClock{}
+	}
+
+	return &Scheduler{rules: rules, clock: clock}
+}
+
+// Run fires active windows first and then every trigger when it is due. It blocks till ctx is done.
+func (s *Scheduler) Run(ctx context.Context, fire func(Trigger)) {
+	last := s.clock.Now()
+	for _, t := range Active(s.rules.All(), last) {
+		fire(t)
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-s.clock.After(checkInterval):
+		}
+
+		now := s.clock.Now()
+		for _, t := range Due(s.rules.All(), last, now) {
+			fire(t)
+		}
+		last = now
+	}
+}
+
+// Rules is a collection of schedule rules.
+type Rules struct {
+	mu       sync.Mutex
+	rules    []Rule
+	onChange func()
+}
+
+func NewRules() *Rules {
+	return &Rules{onChange: func() {}}
+}
+
+// OnChange sets handler that is called after rules are added or removed, but not loaded.
+func (r *Rules) OnChange(fn func()) {
+	r.onChange = fn
+}
+
+func (r *Rules) All() []Rule {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return slices.Clone(r.rules)
+}
+
+func (r *Rules) Add(rule Rule) error {
+	if err := rule.Validate(); err != nil {
+		return err
+	}
+	if rule.Action == ActionDisconnect {
+		rule.ItemID = ""
+	}
+	slices.Sort(rule.Days)
+	rule.Days = slices.Compact(rule.Days)
+
+	r.mu.Lock()
+	r.rules = append(r.rules, rule)
+	r.mu.Unlock()
+	r.onChange()
+
+	return nil
+}
+
+func (r *Rules) Remove(i int) error {
+	r.mu.Lock()
+	if i < 0 || i >= len(r.rules) {
+		r.mu.Unlock()
+
+		return ErrRuleNotFound
+	}
+	r.rules = slices.Delete(r.rules, i, i+1)
+	r.mu.Unlock()
+	r.onChange()
+
+	return nil
+}
+
+// Load replaces rules with saved ones, invalid rules are skipped.
+func (r *Rules) Load(rules []Rule) {
+	valid := make([]Rule, 0, len(rules))
+	for _, rule := range rules {
+		if err := rule.Validate(); err != nil {
+			slog.Error("failed to load schedule rule", "error", err)
+			continue
+		}
+		valid = append(valid, rule)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.rules = valid
+}
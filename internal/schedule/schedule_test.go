@@ -0,0 +1,176 @@
+package schedule
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+var weekdays = []time.Weekday{time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday}
+
+// 2024-01-01 is Monday.
+func at(day, hour, minute int) time.Time {
+	return time.Date(2024, 1, day, hour, minute, 0, 0, time.UTC)
+}
+
+func tod(s string) *TimeOfDay {
+	t, err := ParseTimeOfDay(s)
+	if err != nil {
+		panic(err)
+	}
+
+	return &t
+}
+
+type fakeClock struct {
+	mu   sync.Mutex
+	now  time.Time
+	tick chan time.Time
+}
+
+func (f *fakeClock) Now() time.Time {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return f.now
+}
+
+func (f *fakeClock) After(time.Duration) <-chan time.Time {
+	return f.tick
+}
+
+// advance moves the clock and wakes up the scheduler.
+func (f *fakeClock) advance(to time.Time) {
+	f.mu.Lock()
+	f.now = to
+	f.mu.Unlock()
+	f.tick <- to
+}
+
+func TestTimeOfDay(t *testing.T) {
+	v, err := ParseTimeOfDay("09:05")
+	require.NoError(t, err)
+	require.Equal(t, TimeOfDay(9*60+5), v)
+	require.Equal(t, "09:05", v.String())
+
+	_, err = ParseTimeOfDay("25:00")
+	require.ErrorIs(t, err, ErrInvalidTime)
+
+	b, err := json.Marshal(Rule{Start: v, End: tod("18:00"), Action: ActionConnect, ItemID: "x"})
+	require.NoError(t, err)
+	require.JSONEq(t, `{"start":"09:05","end":"18:00","action":"connect","item_id":"x"}`, string(b))
+
+	var r Rule
+	require.NoError(t, json.Unmarshal(b, &r))
+	require.Equal(t, v, r.Start)
+	require.Equal(t, *tod("18:00"), *r.End)
+}
+
+func TestRule_Validate(t *testing.T) {
+	require.NoError(t, Rule{Start: 60, Action: ActionDisconnect}.Validate())
+	require.ErrorIs(t, Rule{Start: 60, Action: ActionConnect}.Validate(), ErrNoItem)
+	require.ErrorIs(t, Rule{Start: 60, Action: "toggle"}.Validate(), ErrUnknownAction)
+	require.ErrorIs(t, Rule{Start: 60, End: tod("01:00"), Action: ActionDisconnect}.Validate(), ErrEmptyWindow)
+	require.ErrorIs(t, Rule{Start: 24 * 60, Action: ActionDisconnect}.Validate(), ErrInvalidTime)
+}
+
+func TestDue(t *testing.T) {
+	work := Rule{Days: weekdays, Start: *tod("09:00"), End: tod("18:00"), Action: ActionConnect, ItemID: "work"}
+	night := Rule{Start: *tod("23:30"), End: tod("07:00"), Action: ActionDisconnect}
+
+	// Nothing happens between the edges.
+	require.Empty(t, Due([]Rule{work, night}, at(1, 10, 0), at(1, 11, 0)))
+
+	// Start and end of the window.
+	require.Equal(t, []Trigger{{Action: ActionConnect, ItemID: "work", At: at(1, 9, 0)}},
+		Due([]Rule{work}, at(1, 8, 59), at(1, 9, 0)))
+	require.Equal(t, []Trigger{{Action: ActionDisconnect, ItemID: "work", At: at(1, 18, 0)}},
+		Due([]Rule{work}, at(1, 17, 59), at(1, 18, 30)))
+
+	// Window does not start on weekends.
+	require.Empty(t, Due([]Rule{work}, at(6, 0, 0), at(7, 23, 59)))
+
+	// Only the latest edge of each rule fires, e.g. after suspend.
+	require.Equal(t, []Trigger{{Action: ActionDisconnect, ItemID: "work", At: at(1, 18, 0)}},
+		Due([]Rule{work}, at(1, 8, 0), at(1, 19, 0)))
+
+	// Disconnect rule spans midnight and has nothing to revert at the end.
+	require.Equal(t, []Trigger{{Action: ActionDisconnect, At: at(1, 23, 30)}},
+		Due([]Rule{night}, at(1, 23, 0), at(2, 1, 0)))
+	require.Empty(t, Due([]Rule{night}, at(2, 6, 0), at(2, 8, 0)))
+
+	// Triggers are sorted by time.
+	due := Due([]Rule{night, work}, at(1, 8, 0), at(1, 23, 45))
+	require.Len(t, due, 2)
+	require.Equal(t, at(1, 18, 0), due[0].At)
+	require.Equal(t, at(1, 23, 30), due[1].At)
+}
+
+func TestActive(t *testing.T) {
+	work := Rule{Days: weekdays, Start: *tod("09:00"), End: tod("18:00"), Action: ActionConnect, ItemID: "work"}
+	night := Rule{Start: *tod("23:30"), End: tod("07:00"), Action: ActionDisconnect}
+	noEnd := Rule{Start: *tod("00:00"), Action: ActionDisconnect}
+
+	require.Equal(t, []Trigger{{Action: ActionConnect, ItemID: "work", At: at(1, 9, 0)}},
+		Active([]Rule{work, noEnd}, at(1, 12, 0)))
+	require.Empty(t, Active([]Rule{work}, at(1, 18, 0)))
+	require.Empty(t, Active([]Rule{work}, at(6, 12, 0)), "saturday")
+
+	// Window started yesterday.
+	require.Equal(t, []Trigger{{Action: ActionDisconnect, At: at(1, 23, 30)}},
+		Active([]Rule{night}, at(2, 3, 0)))
+}
+
+func TestScheduler(t *testing.T) {
+	rules := NewRules()
+	require.NoError(t, rules.Add(Rule{Days: weekdays, Start: *tod("09:00"), End: tod("18:00"), Action: ActionConnect, ItemID: "work"}))
+
+	clk := &fakeClock{now: at(1, 12, 0), tick: make(chan time.Time)}
+	fired := make(chan Trigger, 10)
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		NewScheduler(rules, clk).Run(ctx, func(t Trigger) { fired <- t })
+	}()
+
+	// Started inside the window.
+	require.Equal(t, ActionConnect, (<-fired).Action)
+
+	clk.advance(at(1, 17, 59))
+	clk.advance(at(1, 18, 0))
+	require.Equal(t, Trigger{Action: ActionDisconnect, ItemID: "work", At: at(1, 18, 0)}, <-fired)
+
+	// Rules added later are picked up on the next check.
+	require.NoError(t, rules.Add(Rule{Start: *tod("20:00"), Action: ActionDisconnect}))
+	clk.advance(at(1, 20, 0))
+	require.Equal(t, Trigger{Action: ActionDisconnect, At: at(1, 20, 0)}, <-fired)
+
+	cancel()
+	<-done
+	require.Empty(t, fired)
+}
+
+func TestRules(t *testing.T) {
+	rules := NewRules()
+	changes := 0
+	rules.OnChange(func() { changes++ })
+
+	require.ErrorIs(t, rules.Add(Rule{Action: ActionConnect}), ErrNoItem)
+	require.NoError(t, rules.Add(Rule{Days: []time.Weekday{time.Friday, time.Monday, time.Friday}, Action: ActionDisconnect, ItemID: "x"}))
+	require.Equal(t, 1, changes)
+	require.Equal(t, []time.Weekday{time.Monday, time.Friday}, rules.All()[0].Days)
+	require.Empty(t, rules.All()[0].ItemID)
+
+	require.ErrorIs(t, rules.Remove(1), ErrRuleNotFound)
+	require.NoError(t, rules.Remove(0))
+	require.Equal(t, 2, changes)
+
+	rules.Load([]Rule{{Action: ActionDisconnect}, {Action: "unknown"}})
+	require.Len(t, rules.All(), 1)
+	require.Equal(t, 2, changes)
+}
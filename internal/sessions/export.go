@@ -0,0 +1,75 @@
+package sessions
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+)
+
+// Format of exported sessions.
+type Format string
+
+const (
+	FormatCSV  Format = "csv"
+	FormatJSON Format = "json"
+)
+
+var ErrUnknownFormat = errors.New("unknown format")
+
+// Write writes sessions in format, timestamps are in RFC 3339.
+func Write(w io.Writer, format Format, sessions []Session) error {
+	switch format {
+	case FormatCSV:
+		return writeCSV(w, sessions)
+	case FormatJSON:
+		return writeJSON(w, sessions)
+	}
+
+	return fmt.Errorf("%w: %q", ErrUnknownFormat, format)
+}
+
+func writeCSV(w io.Writer, sessions []Session) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write([]string{"item_id", "item", "start", "end", "upload", "download", "reason", "error"}); err != nil {
+		return fmt.Errorf("write csv: %w", err)
+	}
+	for _, s := range sessions {
+		record := []string{
+			s.ItemID,
+			s.Label,
+			s.Start.Format(time.RFC3339),
+			s.End.Format(time.RFC3339),
+			strconv.FormatInt(s.Read, 10),
+			strconv.FormatInt(s.Written, 10),
+			string(s.Reason),
+			s.Err,
+		}
+		if err := cw.Write(record); err != nil {
+			return fmt.Errorf("write csv: %w", err)
+		}
+	}
+	cw.Flush()
+	if err := cw.Error(); err != nil {
+		return fmt.Errorf("write csv: %w", err)
+	}
+
+	return nil
+}
+
+func writeJSON(w io.Writer, sessions []Session) error {
+	if sessions == nil {
+		sessions = []Session{}
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(sessions); err != nil {
+		return fmt.Errorf("write json: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,35 @@
+package sessions
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestWrite(t *testing.T) {
+	all := []Session{
+		{ItemID: "a", Label: "my, vpn", Start: at(1), End: at(2), Read: 15, Written: 150, Reason: ReasonUser},
+		{ItemID: "b", Label: "B", Start: at(3), End: at(3), Reason: ReasonError, Err: "refused"},
+	}
+
+	buf := &bytes.Buffer{}
+	require.NoError(t, Write(buf, FormatCSV, all))
+	require.Equal(t, "item_id,item,start,end,upload,download,reason,error\n"+
+		"a,\"my, vpn\",2024-01-01T10:01:00Z,2024-01-01T10:02:00Z,15,150,user,\n"+
+		"b,B,2024-01-01T10:03:00Z,2024-01-01T10:03:00Z,0,0,error,refused\n", buf.String())
+
+	buf.Reset()
+	require.NoError(t, Write(buf, FormatJSON, all))
+	var decoded []Session
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &decoded))
+	require.Equal(t, all, decoded)
+
+	buf.Reset()
+	require.NoError(t, Write(buf, FormatJSON, nil))
+	require.Equal(t, "[]\n", buf.String())
+
+	require.ErrorIs(t, Write(io.Discard, "xml", all), ErrUnknownFormat)
+}
@@ -0,0 +1,174 @@
+/*
+Package sessions keeps a persisted history of connection sessions.
+*/
+package sessions
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+	"time"
+)
+
+// Reason a session ended.
+type Reason string
+
+const (
+	ReasonUser      Reason = "user"      // Disconnected by the user or by a rule.
+	ReasonError     Reason = "error"     // Connection failed.
+	ReasonReconnect Reason = "reconnect" // Reconnected, e.g. after a network change.
+	ReasonShutdown  Reason = "shutdown"  // The app was closed.
+)
+
+// Session is a single connection of an item, failed connection attempts are sessions with the same Start and End.
+type Session struct {
+	ItemID  string    `json:"item_id"`
+	Label   string    `json:"label"`
+	Start   time.Time `json:"start"`
+	End     time.Time `json:"end"`
+	Read    int64     `json:"read"`    // Uplink bytes.
+	Written int64     `json:"written"` // Downlink bytes.
+	Reason  Reason    `json:"reason"`
+	Err     string    `json:"error,omitempty"`
+}
+
+// History is a file backed history of up to limit latest sessions, changes are kept in memory till Flush.
+type History struct {
+	path  string
+	limit int
+
+	mu       sync.Mutex
+	sessions []Session           // Ended sessions from the oldest.
+	open     map[string]*Session // Sessions in progress by item ID.
+	dirty    bool
+}
+
+// New creates an empty History, the file at path is overwritten on the first Flush.
+func New(path string, limit int) *History {
+	return &History{path: path, limit: max(limit, 1), open: map[string]*Session{}}
+}
+
+// Open loads history from the file at path, the file is created on the first Flush.
+func Open(path string, limit int) (*History, error) {
+	h := New(path, limit)
+
+	b, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return h, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read sessions: %w", err)
+	}
+	if err := json.Unmarshal(b, &h.sessions); err != nil {
+		return nil, fmt.Errorf("unmarshal sessions: %w", err)
+	}
+	h.trim()
+
+	return h, nil
+}
+
+// Start opens a session of the item, a session already open for the item is replaced.
+func (h *History) Start(itemID, label string, at time.Time) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.open[itemID] = &Session{ItemID: itemID, Label: label, Start: at}
+}
+
+// AddTraffic counts bytes transferred in the open session of the item, it does nothing if there is none.
+func (h *History) AddTraffic(itemID string, read, written int) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if s, ok := h.open[itemID]; ok {
+		s.Read += int64(read)
+		s.Written += int64(written)
+	}
+}
+
+// End ends the open session of the item, it does nothing if there is none.
+func (h *History) End(itemID string, at time.Time, reason Reason, err error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	s, ok := h.open[itemID]
+	if !ok {
+		return
+	}
+	delete(h.open, itemID)
+	s.End, s.Reason = at, reason
+	if err != nil {
+		s.Err = err.Error()
+	}
+	h.add(*s)
+}
+
+// Fail records a failed connection attempt of the item.
+func (h *History) Fail(itemID, label string, at time.Time, err error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	s := Session{ItemID: itemID, Label: label, Start: at, End: at, Reason: ReasonError}
+	if err != nil {
+		s.Err = err.Error()
+	}
+	h.add(s)
+}
+
+// add appends an ended session, must be called with mu held.
+func (h *History) add(s Session) {
+	h.sessions = append(h.sessions, s)
+	h.trim()
+	h.dirty = true
+}
+
+// trim drops the oldest sessions over the limit, must be called with mu held.
+func (h *History) trim() {
+	if over := len(h.sessions) - h.limit; over > 0 {
+		h.sessions = slices.Delete(h.sessions, 0, over)
+	}
+}
+
+// All returns ended sessions, the newest first.
+func (h *History) All() []Session {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	res := slices.Clone(h.sessions)
+	slices.Reverse(res)
+
+	return res
+}
+
+// Flush writes the history to the file if anything changed since the last Flush.
+func (h *History) Flush() error {
+	h.mu.Lock()
+	if !h.dirty {
+		h.mu.Unlock()
+		return nil
+	}
+	b, err := json.Marshal(h.sessions)
+	h.dirty = false
+	h.mu.Unlock()
+	if err != nil {
+		return fmt.Errorf("marshal sessions: %w", err)
+	}
+
+	// Write to a temporary file first, so the previous state survives a crash in the middle of writing.
+	if err := os.MkdirAll(filepath.Dir(h.path), 0o755); err != nil {
+		return fmt.Errorf("create sessions dir: %w", err)
+	}
+	tmp := h.path + ".tmp"
+	if err := os.WriteFile(tmp, b, 0o644); err != nil {
+		return fmt.Errorf("write sessions: %w", err)
+	}
+	if err := os.Rename(tmp, h.path); err != nil {
+		return fmt.Errorf("replace sessions: %w", err)
+	}
+
+	return nil
+}
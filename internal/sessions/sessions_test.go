@@ -0,0 +1,58 @@
+package sessions
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func at(minute int) time.Time {
+	return time.Date(2024, 1, 1, 10, minute, 0, 0, time.UTC)
+}
+
+func TestHistory(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "sub", "sessions.json")
+	h, err := Open(path, 3)
+	require.NoError(t, err)
+	require.NoError(t, h.Flush(), "nothing to flush")
+	_, err = os.Stat(path)
+	require.ErrorIs(t, err, os.ErrNotExist)
+
+	h.AddTraffic("a", 1, 1) // No open session.
+	h.End("a", at(0), ReasonUser, nil)
+	require.Empty(t, h.All())
+
+	h.Start("a", "A", at(1))
+	h.AddTraffic("a", 10, 100)
+	h.AddTraffic("a", 5, 50)
+	h.End("a", at(2), ReasonReconnect, nil)
+	h.Start("a", "A", at(2))
+	h.Fail("b", "B", at(3), errors.New("refused"))
+	h.End("a", at(4), ReasonShutdown, errors.New("routes are gone"))
+
+	require.Equal(t, []Session{
+		{ItemID: "a", Label: "A", Start: at(2), End: at(4), Reason: ReasonShutdown, Err: "routes are gone"},
+		{ItemID: "b", Label: "B", Start: at(3), End: at(3), Reason: ReasonError, Err: "refused"},
+		{ItemID: "a", Label: "A", Start: at(1), End: at(2), Read: 15, Written: 150, Reason: ReasonReconnect},
+	}, h.All())
+
+	h.Start("c", "C", at(5))
+	h.End("c", at(6), ReasonUser, nil)
+	all := h.All()
+	require.Len(t, all, 3, "limited")
+	require.Equal(t, "c", all[0].ItemID)
+	require.Equal(t, at(3), all[2].Start, "the oldest is dropped")
+
+	require.NoError(t, h.Flush())
+	loaded, err := Open(path, 2)
+	require.NoError(t, err)
+	require.Equal(t, all[:2], loaded.All(), "trimmed to the new limit")
+
+	require.NoError(t, os.WriteFile(path, []byte("{"), 0o644))
+	_, err = Open(path, 3)
+	require.Error(t, err)
+}
@@ -0,0 +1,126 @@
+package sharelink
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"net"
+	"slices"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// Fields of problems, named as in editors.
+const (
+	FieldLink        = "Link"
+	FieldAddress     = "Address"
+	FieldPort        = "Port"
+	FieldUUID        = "UUID"
+	FieldPassword    = "Password"
+	FieldAlterID     = "Alter ID"
+	FieldSNI         = "SNI"
+	FieldFingerprint = "Fingerprint"
+	FieldPublicKey   = "Public key"
+	FieldShortID     = "Short ID"
+)
+
+const (
+	// maxShortID is the maximum number of hex digits of a reality short ID.
+	maxShortID = 16
+	// publicKeySize is the size of a reality X25519 public key in bytes.
+	publicKeySize = 32
+	// maxIDString is the maximum length of a vless or vmess ID that is not a UUID, xray maps such IDs to UUIDs.
+	maxIDString = 30
+)
+
+// Problem of a single field of a config, messages are constant so they can be translated.
+type Problem struct {
+	Field   string
+	Message string
+	Warning bool // Warnings do not prevent the config from working.
+}
+
+func (p Problem) Error() string {
+	return p.Field + ": " + p.Message
+}
+
+// Diagnose parses the link and returns problems of its fields, links of unknown protocols are not diagnosed.
+func Diagnose(link string) []Problem {
+	c, err := Parse(link)
+	switch {
+	case errors.Is(err, ErrUnknownProtocol):
+		return nil
+	case errors.Is(err, ErrInvalidPort):
+		return []Problem{{Field: FieldPort, Message: "must be between 1 and 65535"}}
+	case err != nil:
+		return []Problem{{Field: FieldLink, Message: "malformed link"}}
+	}
+
+	return c.Problems()
+}
+
+// Problems returns problems of the config fields used by its protocol.
+func (c Config) Problems() []Problem {
+	var problems []Problem
+	add := func(field, message string, warning bool) {
+		problems = append(problems, Problem{Field: field, Message: message, Warning: warning})
+	}
+
+	if c.Address == "" {
+		add(FieldAddress, "is required", false)
+	}
+	if c.Port < 1 || c.Port > 65535 {
+		add(FieldPort, "must be between 1 and 65535", false)
+	}
+
+	switch c.Protocol {
+	case VLESS, VMess:
+		if c.ID == "" {
+			add(FieldUUID, "is required", false)
+		} else if _, err := uuid.Parse(c.ID); err != nil && (len(c.ID) > maxIDString || strings.Contains(c.ID, "-")) {
+			add(FieldUUID, "malformed UUID", false)
+		}
+	default:
+		if c.ID == "" {
+			add(FieldPassword, "is required", false)
+		}
+	}
+	if c.Protocol == VMess && c.AlterID < 0 {
+		add(FieldAlterID, "must not be negative", false)
+	}
+	if c.Protocol == Shadowsocks {
+		return problems // No transport security.
+	}
+
+	if c.Security == "tls" || c.Security == "reality" {
+		if c.Fingerprint != "" && !knownFingerprint(c.Fingerprint) {
+			add(FieldFingerprint, "unknown fingerprint", false)
+		}
+	}
+	switch c.Security {
+	case "tls":
+		if c.SNI == "" && net.ParseIP(c.Address) != nil {
+			add(FieldSNI, "is empty with TLS, the certificate is checked against the IP address", true)
+		}
+	case "reality":
+		if c.SNI == "" {
+			add(FieldSNI, "is required with reality", false)
+		}
+		if b, err := base64.RawURLEncoding.DecodeString(c.PublicKey); err != nil || len(b) != publicKeySize {
+			add(FieldPublicKey, "must be 43 characters of base64url", false)
+		}
+		if _, err := hex.DecodeString(c.ShortID); err != nil || len(c.ShortID) > maxShortID {
+			add(FieldShortID, "must be up to 16 hex digits, an even number of them", false)
+		}
+	}
+
+	return problems
+}
+
+// knownFingerprint reports whether xray knows the TLS fingerprint, including specific versions like hellochrome_120.
+func knownFingerprint(fp string) bool {
+	fp = strings.ToLower(fp)
+
+	return slices.Contains(Fingerprints, fp) || fp == "unsafe" || strings.HasPrefix(fp, "hello")
+}
@@ -0,0 +1,55 @@
+package sharelink
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const testPublicKey = "Z84J2IelR9ch3k8VtlVhhs5ycBUlXA7wHBWcBrjqnAw"
+
+func TestDiagnose(t *testing.T) {
+	valid := "vless://" + testUUID + "@example.com:443?security=reality&sni=sni.com&fp=chrome&pbk=" + testPublicKey +
+		"&sid=ab12&type=tcp"
+	require.Empty(t, Diagnose(valid))
+	require.Empty(t, Diagnose("wireguard://key@example.com:51820"), "unknown protocols are not diagnosed")
+
+	for link, want := range map[string][]Problem{
+		"vless://id@example.com:70000":                    {{Field: FieldPort, Message: "must be between 1 and 65535"}},
+		"vmess://not-base64!":                             {{Field: FieldLink, Message: "malformed link"}},
+		"vless://b831381d-6324-4d53-ad4f@example.com:443": {{Field: FieldUUID, Message: "malformed UUID"}},
+		"vless://" + testUUID + "@example.com:443?security=reality&fp=chrom&pbk=short&sid=xyz": {
+			{Field: FieldFingerprint, Message: "unknown fingerprint"},
+			{Field: FieldSNI, Message: "is required with reality"},
+			{Field: FieldPublicKey, Message: "must be 43 characters of base64url"},
+			{Field: FieldShortID, Message: "must be up to 16 hex digits, an even number of them"},
+		},
+		"trojan://@1.2.3.4:443?security=tls": {
+			{Field: FieldPassword, Message: "is required"},
+			{Field: FieldSNI, Message: "is empty with TLS, the certificate is checked against the IP address", Warning: true},
+		},
+	} {
+		require.Equal(t, want, Diagnose(link), link)
+	}
+}
+
+func TestConfig_Problems(t *testing.T) {
+	c := New(VMess)
+	c.Address, c.ID = "example.com", "short-id"
+	require.Equal(t, []Problem{{Field: FieldUUID, Message: "malformed UUID"}}, c.Problems())
+
+	c.ID = "any string" // Xray maps short strings to UUIDs.
+	c.Fingerprint = "hellochrome_120"
+	require.Empty(t, c.Problems())
+
+	c.Security, c.ShortID, c.PublicKey, c.SNI = "reality", "0123456789abcdef0", testPublicKey, "sni.com"
+	require.Equal(t, []Problem{{Field: FieldShortID, Message: "must be up to 16 hex digits, an even number of them"}},
+		c.Problems())
+	c.ShortID = ""
+	require.Empty(t, c.Problems(), "short ID is optional")
+
+	ss := New(Shadowsocks)
+	ss.Address, ss.ID, ss.Fingerprint = "example.com", "secret", "unknown"
+	require.Empty(t, ss.Problems(), "no transport security")
+	require.Equal(t, "Password: is required", Config{Protocol: Trojan, Address: "a", Port: 1}.Problems()[0].Error())
+}
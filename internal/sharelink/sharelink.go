@@ -0,0 +1,443 @@
+/*
+Package sharelink converts share links of vless, vmess, trojan and shadowsocks configurations to typed fields
+and back to canonical links, so configurations can be edited without hand-editing URLs.
+*/
+package sharelink
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Protocols of share links.
+const (
+	VLESS       = "vless"
+	VMess       = "vmess"
+	Trojan      = "trojan"
+	Shadowsocks = "ss"
+)
+
+// Protocols are all supported protocols.
+var Protocols = []string{VLESS, VMess, Trojan, Shadowsocks}
+
+// Values offered by editors, other values are kept as is.
+var (
+	Securities    = []string{"none", "tls", "reality"}
+	Networks      = []string{"tcp", "ws", "grpc", "http", "httpupgrade", "xhttp", "kcp", "quic"}
+	Flows         = []string{"", "xtls-rprx-vision", "xtls-rprx-vision-udp443"}
+	VMessCiphers  = []string{"auto", "aes-128-gcm", "chacha20-poly1305", "none", "zero"}
+	ShadowCiphers = []string{
+		"2022-blake3-aes-128-gcm", "2022-blake3-aes-256-gcm", "2022-blake3-chacha20-poly1305",
+		"aes-128-gcm", "aes-256-gcm", "chacha20-ietf-poly1305", "xchacha20-ietf-poly1305",
+	}
+	Fingerprints = []string{
+		"chrome", "firefox", "safari", "ios", "android", "edge", "360", "qq", "random", "randomized", "randomizednoalpn",
+	}
+)
+
+var (
+	ErrUnknownProtocol = errors.New("unknown protocol")
+	ErrInvalidLink     = errors.New("invalid link")
+	ErrMissingAddress  = errors.New("address is required")
+	ErrInvalidPort     = errors.New("port must be between 1 and 65535")
+	ErrMissingID       = errors.New("ID or password is required")
+)
+
+// Config is a configuration of a share link, fields not used by the protocol are ignored.
+type Config struct {
+	Protocol string
+	Remark   string
+	Address  string
+	Port     int
+	ID       string // UUID of vless and vmess, password of trojan and shadowsocks.
+	Cipher   string // Encryption of vmess and shadowsocks.
+	AlterID  int    // Vmess only.
+	Flow     string // Vless only.
+
+	Security    string // none, tls or reality, shadowsocks has no transport security.
+	SNI         string
+	Fingerprint string
+	ALPN        string
+	PublicKey   string // Reality only.
+	ShortID     string // Reality only.
+
+	Network string
+	Host    string
+	Path    string // Path of the transport, service name of grpc.
+
+	// Extra are parameters without a field, kept so they are not lost when the link is regenerated.
+	Extra url.Values
+}
+
+// New returns a config of the protocol with common defaults, to build a link from scratch.
+func New(protocol string) Config {
+	c := Config{Protocol: protocol, Port: 443, Security: "tls", Fingerprint: "chrome", Network: "tcp"}
+	switch protocol {
+	case VMess:
+		c.Cipher = "auto"
+	case Shadowsocks:
+		c.Cipher, c.Security, c.Fingerprint, c.Network = ShadowCiphers[0], "", "", ""
+	}
+
+	return c
+}
+
+// Parse parses a share link.
+func Parse(link string) (Config, error) {
+	scheme, _, ok := strings.Cut(strings.TrimSpace(link), "://")
+	if !ok {
+		return Config{}, fmt.Errorf("%w: no protocol", ErrInvalidLink)
+	}
+
+	switch strings.ToLower(scheme) {
+	case VLESS, Trojan:
+		return parseURL(link)
+	case VMess:
+		return parseVMess(link)
+	case Shadowsocks:
+		return parseShadowsocks(link)
+	default:
+		return Config{}, fmt.Errorf("%w: %q", ErrUnknownProtocol, scheme)
+	}
+}
+
+// Link returns the canonical share link of the config, parameters are sorted so equal configs have equal links.
+func (c Config) Link() (string, error) {
+	if err := c.validate(); err != nil {
+		return "", err
+	}
+
+	switch c.Protocol {
+	case VLESS, Trojan:
+		return c.urlLink(), nil
+	case VMess:
+		return c.vmessLink()
+	case Shadowsocks:
+		return c.shadowsocksLink(), nil
+	default:
+		return "", fmt.Errorf("%w: %q", ErrUnknownProtocol, c.Protocol)
+	}
+}
+
+func (c Config) validate() error {
+	if !slices.Contains(Protocols, c.Protocol) {
+		return fmt.Errorf("%w: %q", ErrUnknownProtocol, c.Protocol)
+	}
+	if c.Address == "" {
+		return ErrMissingAddress
+	}
+	if c.Port < 1 || c.Port > 65535 {
+		return fmt.Errorf("%w: %d", ErrInvalidPort, c.Port)
+	}
+	if c.ID == "" {
+		return ErrMissingID
+	}
+
+	return nil
+}
+
+// Query parameters of vless and trojan links that have a field.
+const (
+	paramEncryption  = "encryption"
+	paramSecurity    = "security"
+	paramSNI         = "sni"
+	paramFingerprint = "fp"
+	paramALPN        = "alpn"
+	paramPublicKey   = "pbk"
+	paramShortID     = "sid"
+	paramFlow        = "flow"
+	paramNetwork     = "type"
+	paramHost        = "host"
+	paramPath        = "path"
+	paramServiceName = "serviceName"
+)
+
+func parseURL(link string) (Config, error) {
+	u, err := url.Parse(strings.TrimSpace(link))
+	if err != nil {
+		return Config{}, fmt.Errorf("%w: %w", ErrInvalidLink, err)
+	}
+	port, err := parsePort(u.Port())
+	if err != nil {
+		return Config{}, err
+	}
+
+	q := u.Query()
+	c := Config{
+		Protocol:    strings.ToLower(u.Scheme),
+		Remark:      u.Fragment,
+		Address:     u.Hostname(),
+		Port:        port,
+		ID:          u.User.Username(),
+		Flow:        pop(q, paramFlow),
+		Security:    pop(q, paramSecurity),
+		SNI:         pop(q, paramSNI),
+		Fingerprint: pop(q, paramFingerprint),
+		ALPN:        pop(q, paramALPN),
+		PublicKey:   pop(q, paramPublicKey),
+		ShortID:     pop(q, paramShortID),
+		Network:     pop(q, paramNetwork),
+		Host:        pop(q, paramHost),
+		Path:        pop(q, paramPath),
+	}
+	if c.Network == "grpc" {
+		c.Path = pop(q, paramServiceName)
+	}
+	if c.Security == "" {
+		c.Security = "none"
+	}
+	if c.Network == "" {
+		c.Network = "tcp"
+	}
+	if c.Protocol == VLESS && q.Get(paramEncryption) == "none" {
+		q.Del(paramEncryption) // Always set when the link is generated.
+	}
+	if len(q) > 0 {
+		c.Extra = q
+	}
+
+	return c, nil
+}
+
+func (c Config) urlLink() string {
+	q := url.Values{}
+	for k, v := range c.Extra {
+		q[k] = slices.Clone(v)
+	}
+	set := func(key, value string) {
+		if value != "" {
+			q.Set(key, value)
+		}
+	}
+
+	if c.Protocol == VLESS {
+		if !q.Has(paramEncryption) {
+			q.Set(paramEncryption, "none")
+		}
+		set(paramFlow, c.Flow)
+	}
+	set(paramSecurity, c.Security)
+	if c.Security == "tls" || c.Security == "reality" {
+		set(paramSNI, c.SNI)
+		set(paramFingerprint, c.Fingerprint)
+		set(paramALPN, c.ALPN)
+	}
+	if c.Security == "reality" {
+		set(paramPublicKey, c.PublicKey)
+		set(paramShortID, c.ShortID)
+	}
+	set(paramNetwork, c.Network)
+	set(paramHost, c.Host)
+	if c.Network == "grpc" {
+		set(paramServiceName, c.Path)
+	} else {
+		set(paramPath, c.Path)
+	}
+
+	u := url.URL{
+		Scheme:   c.Protocol,
+		User:     url.User(c.ID),
+		Host:     net.JoinHostPort(c.Address, strconv.Itoa(c.Port)),
+		RawQuery: q.Encode(),
+		Fragment: c.Remark,
+	}
+
+	return u.String()
+}
+
+// vmessJSON are keys of vmess links that have a field.
+var vmessJSON = []string{"v", "ps", "add", "port", "id", "aid", "scy", "net", "host", "path", "tls", "sni", "fp", "alpn",
+	"pbk", "sid"}
+
+func parseVMess(link string) (Config, error) {
+	_, encoded, _ := strings.Cut(strings.TrimSpace(link), "://")
+	b, err := decodeBase64(encoded)
+	if err != nil {
+		return Config{}, fmt.Errorf("%w: %w", ErrInvalidLink, err)
+	}
+
+	var raw map[string]any
+	if err := json.Unmarshal(b, &raw); err != nil {
+		return Config{}, fmt.Errorf("%w: %w", ErrInvalidLink, err)
+	}
+	fields := make(map[string]string, len(raw))
+	for k, v := range raw {
+		switch v := v.(type) {
+		case string:
+			fields[k] = v
+		case nil:
+		default:
+			fields[k] = fmt.Sprint(v) // Ports and alter IDs can be numbers.
+		}
+	}
+
+	port, err := parsePort(fields["port"])
+	if err != nil {
+		return Config{}, err
+	}
+	aid := 0
+	if fields["aid"] != "" {
+		if aid, err = strconv.Atoi(fields["aid"]); err != nil {
+			return Config{}, fmt.Errorf("%w: alter ID %q", ErrInvalidLink, fields["aid"])
+		}
+	}
+
+	c := Config{
+		Protocol:    VMess,
+		Remark:      fields["ps"],
+		Address:     fields["add"],
+		Port:        port,
+		ID:          fields["id"],
+		Cipher:      fields["scy"],
+		AlterID:     aid,
+		Security:    fields["tls"],
+		SNI:         fields["sni"],
+		Fingerprint: fields["fp"],
+		ALPN:        fields["alpn"],
+		PublicKey:   fields["pbk"],
+		ShortID:     fields["sid"],
+		Network:     fields["net"],
+		Host:        fields["host"],
+		Path:        fields["path"],
+	}
+	if c.Cipher == "" {
+		c.Cipher = "auto"
+	}
+	if c.Security == "" {
+		c.Security = "none"
+	}
+	if c.Network == "" {
+		c.Network = "tcp"
+	}
+	for k, v := range fields {
+		if !slices.Contains(vmessJSON, k) {
+			if c.Extra == nil {
+				c.Extra = url.Values{}
+			}
+			c.Extra.Set(k, v)
+		}
+	}
+
+	return c, nil
+}
+
+func (c Config) vmessLink() (string, error) {
+	fields := map[string]string{
+		"v": "2", "ps": c.Remark, "add": c.Address, "port": strconv.Itoa(c.Port), "id": c.ID,
+		"aid": strconv.Itoa(c.AlterID), "scy": c.Cipher, "net": c.Network, "host": c.Host, "path": c.Path, "tls": "",
+	}
+	for k := range c.Extra {
+		fields[k] = c.Extra.Get(k)
+	}
+	if c.Security == "tls" || c.Security == "reality" {
+		fields["tls"], fields["sni"], fields["fp"], fields["alpn"] = c.Security, c.SNI, c.Fingerprint, c.ALPN
+	}
+	if c.Security == "reality" {
+		fields["pbk"], fields["sid"] = c.PublicKey, c.ShortID
+	}
+
+	// Maps are marshaled with sorted keys.
+	b, err := json.Marshal(fields)
+	if err != nil {
+		return "", fmt.Errorf("marshal vmess link: %w", err)
+	}
+
+	return VMess + "://" + base64.StdEncoding.EncodeToString(b), nil
+}
+
+func parseShadowsocks(link string) (Config, error) {
+	u, err := url.Parse(strings.TrimSpace(link))
+	if err != nil {
+		return Config{}, fmt.Errorf("%w: %w", ErrInvalidLink, err)
+	}
+
+	// Legacy links encode everything but the remark: ss://base64(method:password@host:port)#remark.
+	var userInfo string
+	if u.User == nil {
+		b, err := decodeBase64(u.Host)
+		if err != nil {
+			return Config{}, fmt.Errorf("%w: %w", ErrInvalidLink, err)
+		}
+		i := strings.LastIndex(string(b), "@")
+		if i < 0 {
+			return Config{}, fmt.Errorf("%w: no address", ErrInvalidLink)
+		}
+		userInfo, u.Host = string(b[:i]), string(b[i+1:])
+	} else {
+		userInfo = u.User.Username()
+		if password, ok := u.User.Password(); ok {
+			userInfo += ":" + password
+		} else if b, err := decodeBase64(userInfo); err == nil {
+			userInfo = string(b)
+		}
+	}
+
+	method, password, ok := strings.Cut(userInfo, ":")
+	if !ok {
+		return Config{}, fmt.Errorf("%w: no method", ErrInvalidLink)
+	}
+	port, err := parsePort(u.Port())
+	if err != nil {
+		return Config{}, err
+	}
+
+	c := Config{
+		Protocol: Shadowsocks,
+		Remark:   u.Fragment,
+		Address:  u.Hostname(),
+		Port:     port,
+		ID:       password,
+		Cipher:   method,
+	}
+	if q := u.Query(); len(q) > 0 {
+		c.Extra = q
+	}
+
+	return c, nil
+}
+
+func (c Config) shadowsocksLink() string {
+	u := url.URL{
+		Scheme:   Shadowsocks,
+		User:     url.User(base64.RawURLEncoding.EncodeToString([]byte(c.Cipher + ":" + c.ID))),
+		Host:     net.JoinHostPort(c.Address, strconv.Itoa(c.Port)),
+		RawQuery: c.Extra.Encode(),
+		Fragment: c.Remark,
+	}
+
+	return u.String()
+}
+
+func parsePort(s string) (int, error) {
+	port, err := strconv.Atoi(s)
+	if err != nil || port < 1 || port > 65535 {
+		return 0, fmt.Errorf("%w: %q", ErrInvalidPort, s)
+	}
+
+	return port, nil
+}
+
+// decodeBase64 decodes standard or URL encoding, with or without padding.
+func decodeBase64(s string) ([]byte, error) {
+	s = strings.TrimRight(strings.TrimSpace(s), "=")
+	if strings.ContainsAny(s, "-_") {
+		return base64.RawURLEncoding.DecodeString(s)
+	}
+
+	return base64.RawStdEncoding.DecodeString(s)
+}
+
+// pop returns the value of key and removes it from q.
+func pop(q url.Values, key string) string {
+	v := q.Get(key)
+	q.Del(key)
+
+	return v
+}
@@ -0,0 +1,134 @@
+package sharelink
+
+import (
+	"encoding/base64"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const testUUID = "b831381d-6324-4d53-ad4f-8cda48b30811"
+
+func TestParse_VLESS(t *testing.T) {
+	c, err := Parse("vless://" + testUUID + "@example.com:443?type=ws&security=reality&sni=sni.com&fp=chrome" +
+		"&pbk=key&sid=ab12&flow=xtls-rprx-vision&path=%2Fws&host=cdn.com&encryption=none&spx=%2F#My%20server")
+	require.NoError(t, err)
+	require.Equal(t, Config{
+		Protocol: VLESS, Remark: "My server", Address: "example.com", Port: 443, ID: testUUID,
+		Flow: "xtls-rprx-vision", Security: "reality", SNI: "sni.com", Fingerprint: "chrome",
+		PublicKey: "key", ShortID: "ab12", Network: "ws", Host: "cdn.com", Path: "/ws",
+		Extra: url.Values{"spx": {"/"}},
+	}, c)
+
+	link, err := c.Link()
+	require.NoError(t, err)
+	require.Equal(t, "vless://"+testUUID+"@example.com:443?encryption=none&flow=xtls-rprx-vision&fp=chrome"+
+		"&host=cdn.com&path=%2Fws&pbk=key&security=reality&sid=ab12&sni=sni.com&spx=%2F&type=ws#My%20server", link)
+}
+
+func TestParse_Trojan(t *testing.T) {
+	c, err := Parse("trojan://secret@[2001:db8::1]:8443?security=tls&type=grpc&serviceName=svc#t")
+	require.NoError(t, err)
+	require.Equal(t, Config{
+		Protocol: Trojan, Remark: "t", Address: "2001:db8::1", Port: 8443, ID: "secret",
+		Security: "tls", Network: "grpc", Path: "svc",
+	}, c)
+
+	link, err := c.Link()
+	require.NoError(t, err)
+	require.Equal(t, "trojan://secret@[2001:db8::1]:8443?security=tls&serviceName=svc&type=grpc#t", link)
+}
+
+func TestParse_VMess(t *testing.T) {
+	raw := `{"v":"2","ps":"vm","add":"1.2.3.4","port":10086,"id":"` + testUUID + `","aid":0,"net":"ws",` +
+		`"type":"none","host":"h.com","path":"/p","tls":"tls","sni":"h.com"}`
+	c, err := Parse("vmess://" + base64.StdEncoding.EncodeToString([]byte(raw)))
+	require.NoError(t, err)
+	require.Equal(t, Config{
+		Protocol: VMess, Remark: "vm", Address: "1.2.3.4", Port: 10086, ID: testUUID, Cipher: "auto",
+		Security: "tls", SNI: "h.com", Network: "ws", Host: "h.com", Path: "/p",
+		Extra: url.Values{"type": {"none"}},
+	}, c)
+
+	link, err := c.Link()
+	require.NoError(t, err)
+	again, err := Parse(link)
+	require.NoError(t, err)
+	require.Equal(t, c, again)
+
+	b, err := base64.StdEncoding.DecodeString(link[len("vmess://"):])
+	require.NoError(t, err)
+	require.JSONEq(t, `{"v":"2","ps":"vm","add":"1.2.3.4","port":"10086","id":"`+testUUID+`","aid":"0","scy":"auto",`+
+		`"net":"ws","type":"none","host":"h.com","path":"/p","tls":"tls","sni":"h.com","fp":"","alpn":""}`, string(b))
+}
+
+func TestParse_Shadowsocks(t *testing.T) {
+	userInfo := base64.RawURLEncoding.EncodeToString([]byte("aes-256-gcm:pa:ss"))
+	c, err := Parse("ss://" + userInfo + "@example.com:8388#ss")
+	require.NoError(t, err)
+	want := Config{Protocol: Shadowsocks, Remark: "ss", Address: "example.com", Port: 8388, ID: "pa:ss", Cipher: "aes-256-gcm"}
+	require.Equal(t, want, c)
+
+	link, err := c.Link()
+	require.NoError(t, err)
+	require.Equal(t, "ss://"+userInfo+"@example.com:8388#ss", link)
+
+	legacy := base64.StdEncoding.EncodeToString([]byte("aes-256-gcm:pa:ss@example.com:8388"))
+	c, err = Parse("ss://" + legacy + "#ss")
+	require.NoError(t, err)
+	require.Equal(t, want, c)
+}
+
+func TestParse_Errors(t *testing.T) {
+	for link, want := range map[string]error{
+		"example.com":                   ErrInvalidLink,
+		"wireguard://key@example.com:1": ErrUnknownProtocol,
+		"vless://id@example.com:0":      ErrInvalidPort,
+		"vless://id@example.com":        ErrInvalidPort,
+		"vmess://not-base64!":           ErrInvalidLink,
+		"ss://" + base64.RawURLEncoding.EncodeToString([]byte("nomethod")) + "@example.com:1": ErrInvalidLink,
+	} {
+		_, err := Parse(link)
+		require.ErrorIs(t, err, want, link)
+	}
+}
+
+func TestConfig_Link(t *testing.T) {
+	c := New(VLESS)
+	_, err := c.Link()
+	require.ErrorIs(t, err, ErrMissingAddress)
+
+	c.Address = "example.com"
+	_, err = c.Link()
+	require.ErrorIs(t, err, ErrMissingID)
+
+	c.ID, c.Port = testUUID, 70000
+	_, err = c.Link()
+	require.ErrorIs(t, err, ErrInvalidPort)
+
+	c.Port = 443
+	c.PublicKey = "ignored without reality"
+	link, err := c.Link()
+	require.NoError(t, err)
+	require.Equal(t, "vless://"+testUUID+"@example.com:443?encryption=none&fp=chrome&security=tls&type=tcp", link)
+
+	_, err = Config{Protocol: "wireguard", Address: "a", Port: 1, ID: "id"}.Link()
+	require.ErrorIs(t, err, ErrUnknownProtocol)
+}
+
+func TestRoundTrip(t *testing.T) {
+	for _, protocol := range Protocols {
+		c := New(protocol)
+		c.Remark, c.Address, c.ID, c.SNI, c.Host, c.Path = "r", "example.com", testUUID, "sni.com", "h.com", "/p"
+		if protocol == Shadowsocks {
+			c.SNI, c.Host, c.Path = "", "", ""
+		}
+
+		link, err := c.Link()
+		require.NoError(t, err, protocol)
+		parsed, err := Parse(link)
+		require.NoError(t, err, protocol)
+		require.Equal(t, c, parsed, protocol)
+	}
+}
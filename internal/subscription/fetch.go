@@ -0,0 +1,80 @@
+package subscription
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// maxBodySize limits the size of downloaded subscriptions.
+const maxBodySize = 10 << 20
+
+var (
+	ErrUnexpectedStatus = errors.New("unexpected subscription response status")
+	ErrNoLinks          = errors.New("subscription has no links")
+)
+
+// Content is a downloaded subscription, HasInfo is false if the provider sent no UserInfoHeader.
+type Content struct {
+	Links   []string
+	Info    UserInfo
+	HasInfo bool
+}
+
+// Fetch downloads the subscription at url with client. Invalid user info is ignored, links are still imported.
+func Fetch(ctx context.Context, client *http.Client, url string) (Content, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return Content{}, fmt.Errorf("create subscription request: %w", err)
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return Content{}, fmt.Errorf("fetch subscription: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != http.StatusOK {
+		return Content{}, fmt.Errorf("%w: %s", ErrUnexpectedStatus, resp.Status)
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxBodySize))
+	if err != nil {
+		return Content{}, fmt.Errorf("read subscription: %w", err)
+	}
+	content := Content{Links: ParseLinks(body)}
+	if len(content.Links) == 0 {
+		return Content{}, ErrNoLinks
+	}
+	if header := resp.Header.Get(UserInfoHeader); header != "" {
+		content.Info, err = ParseUserInfo(header)
+		content.HasInfo = err == nil
+	}
+
+	return content, nil
+}
+
+// ParseLinks returns links of the subscription body, one per line, the body may be base64 encoded.
+// Lines that are not links, e.g. comments, are skipped.
+func ParseLinks(body []byte) []string {
+	body = bytes.TrimSpace(body)
+	for _, enc := range []*base64.Encoding{base64.StdEncoding, base64.RawStdEncoding, base64.URLEncoding, base64.RawURLEncoding} {
+		// Encoded bodies are often wrapped.
+		if decoded, err := enc.DecodeString(strings.Join(strings.Fields(string(body)), "")); err == nil {
+			body = decoded
+			break
+		}
+	}
+
+	var links []string
+	for _, line := range strings.Split(string(body), "\n") {
+		if line = strings.TrimSpace(line); strings.Contains(line, "://") {
+			links = append(links, line)
+		}
+	}
+
+	return links
+}
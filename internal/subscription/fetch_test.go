@@ -0,0 +1,64 @@
+package subscription
+
+import (
+	"context"
+	"encoding/base64"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	link1 = "vless://id@example.com:443?security=tls#First"
+	link2 = "trojan://password@example.org:443#Second"
+)
+
+func TestParseLinks(t *testing.T) {
+	plain := link1 + "\r\n# comment\n\n" + link2 + "\n"
+	require.Equal(t, []string{link1, link2}, ParseLinks([]byte(plain)))
+
+	encoded := base64.StdEncoding.EncodeToString([]byte(plain))
+	require.Equal(t, []string{link1, link2}, ParseLinks([]byte(encoded[:20]+"\n"+encoded[20:])), "wrapped base64")
+	require.Equal(t, []string{link1, link2}, ParseLinks([]byte(base64.RawURLEncoding.EncodeToString([]byte(plain)))))
+
+	require.Empty(t, ParseLinks([]byte("not a subscription")))
+}
+
+func TestFetch(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/info":
+			w.Header().Set(UserInfoHeader, "upload=1; download=2; total=10; expire=1700000000")
+			_, _ = w.Write([]byte(base64.StdEncoding.EncodeToString([]byte(link1 + "\n" + link2))))
+		case "/invalid-info":
+			w.Header().Set(UserInfoHeader, "upload=abc")
+			_, _ = w.Write([]byte(link1))
+		case "/empty":
+			_, _ = w.Write([]byte("\n"))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+	ctx := context.Background()
+
+	content, err := Fetch(ctx, srv.Client(), srv.URL+"/info")
+	require.NoError(t, err)
+	require.Equal(t, Content{
+		Links:   []string{link1, link2},
+		Info:    UserInfo{Upload: 1, Download: 2, Total: 10, Expire: time.Unix(1700000000, 0)},
+		HasInfo: true,
+	}, content)
+
+	content, err = Fetch(ctx, srv.Client(), srv.URL+"/invalid-info")
+	require.NoError(t, err)
+	require.Equal(t, Content{Links: []string{link1}}, content, "links are imported without info")
+
+	_, err = Fetch(ctx, srv.Client(), srv.URL+"/empty")
+	require.ErrorIs(t, err, ErrNoLinks)
+	_, err = Fetch(ctx, srv.Client(), srv.URL+"/missing")
+	require.ErrorIs(t, err, ErrUnexpectedStatus)
+}
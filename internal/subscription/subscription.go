@@ -0,0 +1,187 @@
+package subscription
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrNotFound     = errors.New("subscription not found")
+	ErrInvalidURL   = errors.New("subscription URL must be http or https")
+	ErrDuplicateURL = errors.New("subscription is already added")
+)
+
+// Subscription is a provider URL items are imported from.
+type Subscription struct {
+	ID        string    `json:"id"`
+	Name      string    `json:"name"`
+	URL       string    `json:"url"`
+	Info      UserInfo  `json:"info"`
+	HasInfo   bool      `json:"has_info"` // Whether the provider sent Info with the last update.
+	UpdatedAt time.Time `json:"updated_at"`
+	ItemIDs   []string  `json:"item_ids"` // Items imported with the last update.
+	Notified  State     `json:"notified"` // The last state notified about, see Subscriptions.Notify.
+}
+
+func (s Subscription) clone() Subscription {
+	s.ItemIDs = slices.Clone(s.ItemIDs)
+
+	return s
+}
+
+// Subscriptions keeps subscriptions in the order they were added.
+type Subscriptions struct {
+	mu       sync.Mutex
+	subs     []Subscription
+	onChange func()
+}
+
+func New() *Subscriptions {
+	return &Subscriptions{onChange: func() {}}
+}
+
+// OnChange sets handler that is called after subscriptions are changed, but not loaded.
+func (s *Subscriptions) OnChange(onChange func()) {
+	s.onChange = onChange
+}
+
+func (s *Subscriptions) All() []Subscription {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	res := make([]Subscription, 0, len(s.subs))
+	for _, sub := range s.subs {
+		res = append(res, sub.clone())
+	}
+
+	return res
+}
+
+func (s *Subscriptions) Get(id string) (Subscription, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if i := s.index(id); i >= 0 {
+		return s.subs[i].clone(), true
+	}
+
+	return Subscription{}, false
+}
+
+// ByItem returns the subscription the item was imported from.
+func (s *Subscriptions) ByItem(itemID string) (Subscription, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, sub := range s.subs {
+		if slices.Contains(sub.ItemIDs, itemID) {
+			return sub.clone(), true
+		}
+	}
+
+	return Subscription{}, false
+}
+
+// Add adds a subscription without items, the name defaults to the host of rawURL.
+func (s *Subscriptions) Add(name, rawURL string) (Subscription, error) {
+	rawURL = strings.TrimSpace(rawURL)
+	u, err := url.Parse(rawURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return Subscription{}, fmt.Errorf("%w: %q", ErrInvalidURL, rawURL)
+	}
+	if name = strings.TrimSpace(name); name == "" {
+		name = u.Hostname()
+	}
+
+	s.mu.Lock()
+	if slices.ContainsFunc(s.subs, func(sub Subscription) bool { return sub.URL == rawURL }) {
+		s.mu.Unlock()
+		return Subscription{}, fmt.Errorf("%w: %q", ErrDuplicateURL, rawURL)
+	}
+	sub := Subscription{ID: uuid.NewString(), Name: name, URL: rawURL}
+	s.subs = append(s.subs, sub)
+	s.mu.Unlock()
+	s.onChange()
+
+	return sub, nil
+}
+
+// Update sets the content of the last update of the subscription and its imported items.
+func (s *Subscriptions) Update(id string, content Content, itemIDs []string, now time.Time) error {
+	s.mu.Lock()
+	i := s.index(id)
+	if i < 0 {
+		s.mu.Unlock()
+		return fmt.Errorf("%w: %s", ErrNotFound, id)
+	}
+	sub := &s.subs[i]
+	sub.Info, sub.HasInfo = content.Info, content.HasInfo
+	sub.ItemIDs = slices.Clone(itemIDs)
+	sub.UpdatedAt = now
+	s.mu.Unlock()
+	s.onChange()
+
+	return nil
+}
+
+// Remove forgets the subscription, its items are left as they are.
+func (s *Subscriptions) Remove(id string) error {
+	s.mu.Lock()
+	i := s.index(id)
+	if i < 0 {
+		s.mu.Unlock()
+		return fmt.Errorf("%w: %s", ErrNotFound, id)
+	}
+	s.subs = slices.Delete(s.subs, i, i+1)
+	s.mu.Unlock()
+	s.onChange()
+
+	return nil
+}
+
+// Notify returns the state of the subscription at now if it was not notified about yet.
+// The state is notified again after the subscription becomes active, e.g. when it is renewed.
+func (s *Subscriptions) Notify(id string, now time.Time, warnBefore time.Duration) (State, bool) {
+	s.mu.Lock()
+	i := s.index(id)
+	if i < 0 || !s.subs[i].HasInfo {
+		s.mu.Unlock()
+		return StateActive, false
+	}
+	sub := &s.subs[i]
+	state := sub.Info.State(now, warnBefore)
+	if state == sub.Notified {
+		s.mu.Unlock()
+		return state, false
+	}
+	sub.Notified = state
+	s.mu.Unlock()
+	s.onChange()
+
+	return state, state != StateActive
+}
+
+// Load replaces subscriptions, subscriptions without ID or URL are skipped.
+func (s *Subscriptions) Load(subs []Subscription) {
+	valid := make([]Subscription, 0, len(subs))
+	for _, sub := range subs {
+		if sub.ID != "" && sub.URL != "" {
+			valid = append(valid, sub.clone())
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.subs = valid
+}
+
+func (s *Subscriptions) index(id string) int {
+	return slices.IndexFunc(s.subs, func(sub Subscription) bool { return sub.ID == id })
+}
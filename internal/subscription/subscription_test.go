@@ -0,0 +1,81 @@
+package subscription
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSubscriptions(t *testing.T) {
+	subs := New()
+	changes := 0
+	subs.OnChange(func() { changes++ })
+
+	_, err := subs.Add("", "ftp://example.com/sub")
+	require.ErrorIs(t, err, ErrInvalidURL)
+	_, err = subs.Add("", "example.com/sub")
+	require.ErrorIs(t, err, ErrInvalidURL)
+
+	sub, err := subs.Add(" ", "https://example.com/sub")
+	require.NoError(t, err)
+	require.Equal(t, "example.com", sub.Name, "host is the default name")
+	_, err = subs.Add("Other", "https://example.com/sub")
+	require.ErrorIs(t, err, ErrDuplicateURL)
+	require.Equal(t, 1, changes)
+
+	now := time.Unix(1000, 0)
+	content := Content{Info: UserInfo{Total: 10}, HasInfo: true}
+	require.NoError(t, subs.Update(sub.ID, content, []string{"a", "b"}, now))
+	require.ErrorIs(t, subs.Update("missing", content, nil, now), ErrNotFound)
+	require.Equal(t, 2, changes)
+
+	got, ok := subs.ByItem("b")
+	require.True(t, ok)
+	require.Equal(t, sub.ID, got.ID)
+	require.Equal(t, now, got.UpdatedAt)
+	got.ItemIDs[0] = "changed"
+	require.Equal(t, []string{"a", "b"}, subs.All()[0].ItemIDs, "copies are returned")
+	_, ok = subs.ByItem("c")
+	require.False(t, ok)
+
+	require.NoError(t, subs.Remove(sub.ID))
+	require.ErrorIs(t, subs.Remove(sub.ID), ErrNotFound)
+	require.Empty(t, subs.All())
+	require.Equal(t, 3, changes)
+
+	subs.Load([]Subscription{{ID: "a", URL: "https://example.com"}, {ID: "b"}})
+	require.Len(t, subs.All(), 1)
+	require.Equal(t, 3, changes, "loading does not notify")
+}
+
+func TestSubscriptions_Notify(t *testing.T) {
+	now := time.Unix(1000, 0)
+	subs := New()
+	sub, err := subs.Add("", "https://example.com/sub")
+	require.NoError(t, err)
+
+	_, ok := subs.Notify(sub.ID, now, time.Hour)
+	require.False(t, ok, "no info")
+
+	content := Content{Info: UserInfo{Total: 10, Expire: now.Add(time.Minute)}, HasInfo: true}
+	require.NoError(t, subs.Update(sub.ID, content, nil, now))
+	state, ok := subs.Notify(sub.ID, now, time.Hour)
+	require.True(t, ok)
+	require.Equal(t, StateExpiring, state)
+	_, ok = subs.Notify(sub.ID, now, time.Hour)
+	require.False(t, ok, "already notified")
+
+	state, ok = subs.Notify(sub.ID, now.Add(time.Minute), time.Hour)
+	require.True(t, ok)
+	require.Equal(t, StateExpired, state)
+
+	// Renewed, it is notified again when it expires.
+	content.Info.Expire = now.Add(48 * time.Hour)
+	require.NoError(t, subs.Update(sub.ID, content, nil, now))
+	_, ok = subs.Notify(sub.ID, now, time.Hour)
+	require.False(t, ok)
+	require.Equal(t, StateActive, subs.All()[0].Notified)
+	_, ok = subs.Notify(sub.ID, now.Add(48*time.Hour), time.Hour)
+	require.True(t, ok)
+}
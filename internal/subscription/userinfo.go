@@ -0,0 +1,117 @@
+/*
+Package subscription fetches and keeps subscriptions of providers, and parses their usage and expiry information.
+*/
+package subscription
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// UserInfoHeader is the response header providers use to report usage and expiry of a subscription.
+const UserInfoHeader = "Subscription-Userinfo"
+
+var ErrInvalidUserInfo = errors.New("invalid subscription user info")
+
+// UserInfo is usage and expiry of a subscription, zero Total and Expire mean unlimited.
+type UserInfo struct {
+	Upload   int64     `json:"upload"`
+	Download int64     `json:"download"`
+	Total    int64     `json:"total"`
+	Expire   time.Time `json:"expire"`
+}
+
+// ParseUserInfo parses header value like "upload=1; download=2; total=3; expire=1700000000", unknown keys are ignored.
+func ParseUserInfo(header string) (UserInfo, error) {
+	var info UserInfo
+	for _, part := range strings.Split(header, ";") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		key, value, ok := strings.Cut(part, "=")
+		if !ok {
+			return UserInfo{}, fmt.Errorf("%w: %q", ErrInvalidUserInfo, part)
+		}
+		key, value = strings.ToLower(strings.TrimSpace(key)), strings.TrimSpace(value)
+
+		var dst *int64
+		switch key {
+		case "upload":
+			dst = &info.Upload
+		case "download":
+			dst = &info.Download
+		case "total":
+			dst = &info.Total
+		case "expire":
+			if value == "" {
+				continue // Some providers send empty expire for unlimited subscriptions.
+			}
+			sec, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				return UserInfo{}, fmt.Errorf("%w: %s: %w", ErrInvalidUserInfo, key, err)
+			}
+			if sec > 0 {
+				info.Expire = time.Unix(int64(sec), 0)
+			}
+			continue
+		default:
+			continue
+		}
+
+		n, err := strconv.ParseFloat(value, 64) // Some providers send floats.
+		if err != nil {
+			return UserInfo{}, fmt.Errorf("%w: %s: %w", ErrInvalidUserInfo, key, err)
+		}
+		*dst = int64(n)
+	}
+
+	return info, nil
+}
+
+// Used returns bytes used in both directions.
+func (u UserInfo) Used() int64 {
+	return u.Upload + u.Download
+}
+
+// Exhausted reports whether the whole traffic limit is used.
+func (u UserInfo) Exhausted() bool {
+	return u.Total > 0 && u.Used() >= u.Total
+}
+
+// Expired reports whether the subscription is expired at now.
+func (u UserInfo) Expired(now time.Time) bool {
+	return !u.Expire.IsZero() && !now.Before(u.Expire)
+}
+
+// ExpiresWithin reports whether the subscription is not expired yet but expires within d from now.
+func (u UserInfo) ExpiresWithin(now time.Time, d time.Duration) bool {
+	return !u.Expire.IsZero() && !u.Expired(now) && u.Expire.Sub(now) <= d
+}
+
+// State is the state of a subscription, greater states are more severe.
+type State int
+
+const (
+	StateActive State = iota
+	StateExpiring
+	StateExhausted
+	StateExpired
+)
+
+// State returns the most severe state of the subscription at now, it is expiring if it expires within warnBefore.
+func (u UserInfo) State(now time.Time, warnBefore time.Duration) State {
+	switch {
+	case u.Expired(now):
+		return StateExpired
+	case u.Exhausted():
+		return StateExhausted
+	case u.ExpiresWithin(now, warnBefore):
+		return StateExpiring
+	}
+
+	return StateActive
+}
@@ -0,0 +1,54 @@
+package subscription
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseUserInfo(t *testing.T) {
+	info, err := ParseUserInfo("upload=455727941; download=6174315083; total=1073741824000; expire=1671815872")
+	require.NoError(t, err)
+	require.Equal(t, UserInfo{
+		Upload:   455727941,
+		Download: 6174315083,
+		Total:    1073741824000,
+		Expire:   time.Unix(1671815872, 0),
+	}, info)
+	require.Equal(t, int64(6630043024), info.Used())
+
+	info, err = ParseUserInfo("Upload = 1.5e3;download=0;total=0;expire=;foo=bar;")
+	require.NoError(t, err)
+	require.Equal(t, UserInfo{Upload: 1500}, info)
+
+	_, err = ParseUserInfo("upload=abc")
+	require.ErrorIs(t, err, ErrInvalidUserInfo)
+	_, err = ParseUserInfo("upload")
+	require.ErrorIs(t, err, ErrInvalidUserInfo)
+}
+
+func TestUserInfo_State(t *testing.T) {
+	now := time.Unix(1000, 0)
+
+	unlimited := UserInfo{Upload: 10, Download: 10}
+	require.False(t, unlimited.Exhausted())
+	require.False(t, unlimited.Expired(now))
+	require.False(t, unlimited.ExpiresWithin(now, time.Hour))
+
+	info := UserInfo{Upload: 10, Download: 10, Total: 20, Expire: now.Add(time.Minute)}
+	require.True(t, info.Exhausted())
+	require.False(t, info.Expired(now))
+	require.True(t, info.ExpiresWithin(now, time.Hour))
+	require.False(t, info.ExpiresWithin(now, time.Second))
+	require.True(t, info.Expired(now.Add(time.Minute)))
+	require.False(t, info.ExpiresWithin(now.Add(time.Minute), time.Hour))
+}
+
+func TestUserInfo_StateOrder(t *testing.T) {
+	now := time.Unix(1000, 0)
+	require.Equal(t, StateActive, UserInfo{}.State(now, time.Hour))
+	require.Equal(t, StateExpiring, UserInfo{Expire: now.Add(time.Minute)}.State(now, time.Hour))
+	require.Equal(t, StateExhausted, UserInfo{Download: 20, Total: 20, Expire: now.Add(time.Minute)}.State(now, time.Hour))
+	require.Equal(t, StateExpired, UserInfo{Download: 20, Total: 20, Expire: now}.State(now, time.Hour))
+}
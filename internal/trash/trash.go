@@ -0,0 +1,160 @@
+/*
+Package trash keeps deleted items for a while, so they can be restored before they expire.
+*/
+package trash
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+)
+
+// DefaultMaxAge is how long entries are kept by default.
+const DefaultMaxAge = 30 * 24 * time.Hour
+
+var ErrNotFound = errors.New("item not found in trash")
+
+// Entry is a deleted item.
+type Entry[T any] struct {
+	ID        string    `json:"id"`
+	Label     string    `json:"label"`
+	DeletedAt time.Time `json:"deleted_at"`
+	Item      T         `json:"item"`
+}
+
+// Bin keeps deleted items by ID, the most recently deleted first.
+type Bin[T any] struct {
+	mu       sync.Mutex
+	entries  []Entry[T]
+	maxAge   time.Duration // 0 keeps entries forever.
+	onChange func()
+}
+
+func New[T any]() *Bin[T] {
+	return &Bin[T]{maxAge: DefaultMaxAge, onChange: func() {}}
+}
+
+// OnChange sets handler that is called after entries are added or removed, but not loaded.
+func (b *Bin[T]) OnChange(fn func()) {
+	b.onChange = fn
+}
+
+// MaxAge returns how long entries are kept, 0 if forever.
+func (b *Bin[T]) MaxAge() time.Duration {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.maxAge
+}
+
+// SetMaxAge sets how long entries are kept, 0 keeps them forever. Entries are not expired until Expire.
+func (b *Bin[T]) SetMaxAge(d time.Duration) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.maxAge = max(d, 0)
+}
+
+// All returns entries, the most recently deleted first.
+func (b *Bin[T]) All() []Entry[T] {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return slices.Clone(b.entries)
+}
+
+// Add puts the item into the trash, replacing an entry with the same ID.
+func (b *Bin[T]) Add(id, label string, item T, now time.Time) {
+	b.mu.Lock()
+	b.entries = slices.DeleteFunc(b.entries, func(e Entry[T]) bool { return e.ID == id })
+	b.entries = slices.Insert(b.entries, 0, Entry[T]{ID: id, Label: label, DeletedAt: now, Item: item})
+	b.mu.Unlock()
+	b.onChange()
+}
+
+func (b *Bin[T]) Get(id string) (Entry[T], error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	i := slices.IndexFunc(b.entries, func(e Entry[T]) bool { return e.ID == id })
+	if i < 0 {
+		return Entry[T]{}, fmt.Errorf("%w: %s", ErrNotFound, id)
+	}
+
+	return b.entries[i], nil
+}
+
+// Remove deletes the entry permanently, it is used both to purge and after the item is restored.
+func (b *Bin[T]) Remove(id string) error {
+	b.mu.Lock()
+	n := len(b.entries)
+	b.entries = slices.DeleteFunc(b.entries, func(e Entry[T]) bool { return e.ID == id })
+	removed := len(b.entries) < n
+	b.mu.Unlock()
+	if !removed {
+		return fmt.Errorf("%w: %s", ErrNotFound, id)
+	}
+	b.onChange()
+
+	return nil
+}
+
+// Clear deletes all entries permanently.
+func (b *Bin[T]) Clear() {
+	b.mu.Lock()
+	b.entries = nil
+	b.mu.Unlock()
+	b.onChange()
+}
+
+// ExpiresAt returns when the entry expires, zero if never.
+func (b *Bin[T]) ExpiresAt(e Entry[T]) time.Time {
+	maxAge := b.MaxAge()
+	if maxAge == 0 {
+		return time.Time{}
+	}
+
+	return e.DeletedAt.Add(maxAge)
+}
+
+// Expire deletes entries older than MaxAge at now and returns their number.
+func (b *Bin[T]) Expire(now time.Time) int {
+	b.mu.Lock()
+	n := len(b.entries)
+	if b.maxAge > 0 {
+		b.entries = slices.DeleteFunc(b.entries, func(e Entry[T]) bool { return now.Sub(e.DeletedAt) >= b.maxAge })
+	}
+	expired := n - len(b.entries)
+	b.mu.Unlock()
+	if expired > 0 {
+		b.onChange()
+	}
+
+	return expired
+}
+
+// AutoExpire calls Expire every interval until ctx is done.
+func (b *Bin[T]) AutoExpire(ctx context.Context, interval time.Duration) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(interval):
+		}
+
+		b.Expire(time.Now())
+	}
+}
+
+// Load replaces all entries, they are sorted by deletion time.
+func (b *Bin[T]) Load(entries []Entry[T]) {
+	entries = slices.Clone(entries)
+	slices.SortStableFunc(entries, func(a, b Entry[T]) int { return b.DeletedAt.Compare(a.DeletedAt) })
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.entries = entries
+}
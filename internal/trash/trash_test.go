@@ -0,0 +1,58 @@
+package trash
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestBin(t *testing.T) {
+	b := New[string]()
+	changes := 0
+	b.OnChange(func() { changes++ })
+	now := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	b.Add("a", "A", "link a", now)
+	b.Add("b", "B", "link b", now.Add(time.Hour))
+	b.Add("a", "A2", "link a2", now.Add(2*time.Hour)) // Replaces the entry.
+	require.Equal(t, []Entry[string]{
+		{ID: "a", Label: "A2", DeletedAt: now.Add(2 * time.Hour), Item: "link a2"},
+		{ID: "b", Label: "B", DeletedAt: now.Add(time.Hour), Item: "link b"},
+	}, b.All())
+
+	e, err := b.Get("b")
+	require.NoError(t, err)
+	require.Equal(t, "link b", e.Item)
+	require.Equal(t, now.Add(time.Hour+DefaultMaxAge), b.ExpiresAt(e))
+
+	require.NoError(t, b.Remove("b"))
+	require.ErrorIs(t, b.Remove("b"), ErrNotFound)
+	_, err = b.Get("b")
+	require.ErrorIs(t, err, ErrNotFound)
+	require.Len(t, b.All(), 1)
+
+	b.Clear()
+	require.Empty(t, b.All())
+	require.Equal(t, 5, changes)
+}
+
+func TestBin_Expire(t *testing.T) {
+	b := New[int]()
+	now := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	b.Load([]Entry[int]{
+		{ID: "old", DeletedAt: now.Add(-48 * time.Hour)},
+		{ID: "new", DeletedAt: now.Add(-time.Hour)},
+		{ID: "day", DeletedAt: now.Add(-24 * time.Hour)},
+	})
+	require.Equal(t, "new", b.All()[0].ID)
+
+	b.SetMaxAge(0)
+	require.Zero(t, b.Expire(now))
+	require.True(t, b.ExpiresAt(b.All()[0]).IsZero())
+
+	b.SetMaxAge(24 * time.Hour)
+	require.Equal(t, 2, b.Expire(now))
+	require.Len(t, b.All(), 1)
+	require.Equal(t, "new", b.All()[0].ID)
+}
@@ -0,0 +1,182 @@
+/*
+Package trayicon renders a tray icon with a tiny graph of the recent throughput of the active connection.
+*/
+package trayicon
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"log/slog"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"fyne.io/fyne/v2"
+)
+
+const (
+	// Size of the rendered icon in pixels, it is scaled down by the system.
+	Size = 64
+	// Bars is the number of the most recent samples shown, one bar each.
+	Bars = 16
+	// Interval is how often the icon is rendered while enabled and connected.
+	Interval = time.Second
+
+	barWidth = Size / Bars
+	barGap   = 1
+	// baseline is the height of the line always drawn under the bars, so an idle connection is still visible.
+	baseline = 4
+)
+
+var (
+	// UpColor and DownColor match the upload and download series of the traffic charts.
+	UpColor   = color.NRGBA{R: 0x37, G: 0xb5, B: 0x5a, A: 0xff}
+	DownColor = color.NRGBA{R: 0x40, G: 0x7d, B: 0xff, A: 0xff}
+)
+
+// Render draws the most recent samples of up and down speed as stacked bars, the newest on the right.
+// Bars are scaled to the highest sample shown, missing samples are left blank.
+func Render(up, down []float64) *image.NRGBA {
+	img := image.NewNRGBA(image.Rect(0, 0, Size, Size))
+	draw.Draw(img, image.Rect(0, Size-baseline, Size, Size), image.NewUniform(DownColor), image.Point{}, draw.Src)
+
+	up, down = last(up, Bars), last(down, Bars)
+	n := min(len(up), len(down))
+	up, down = up[len(up)-n:], down[len(down)-n:]
+
+	var peak float64
+	for i := range n {
+		peak = max(peak, up[i]+down[i])
+	}
+	if peak <= 0 {
+		return img
+	}
+
+	height := float64(Size - baseline)
+	for i := range n {
+		x := Size - (n-i)*barWidth
+		downTop := Size - baseline - int(down[i]/peak*height+0.5)
+		upTop := downTop - int(up[i]/peak*height+0.5)
+		draw.Draw(img, image.Rect(x, downTop, x+barWidth-barGap, Size-baseline), image.NewUniform(DownColor),
+			image.Point{}, draw.Src)
+		draw.Draw(img, image.Rect(x, max(upTop, 0), x+barWidth-barGap, downTop), image.NewUniform(UpColor),
+			image.Point{}, draw.Src)
+	}
+
+	return img
+}
+
+// PNG renders the samples with Render and encodes the icon as PNG.
+func PNG(up, down []float64) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, Render(up, down)); err != nil {
+		return nil, fmt.Errorf("encode tray icon: %w", err)
+	}
+
+	return buf.Bytes(), nil
+}
+
+func last(s []float64, n int) []float64 {
+	return s[max(len(s)-n, 0):]
+}
+
+// Updater periodically sets the rendered icon while enabled and connected. It owns the tray icon: the tray menu
+// sets its icons with SetIcon, and only the static active icon is replaced, e.g. progress and warnings are kept.
+type Updater struct {
+	samples func() (up, down []float64, connected bool)
+	set     func(fyne.Resource)
+	static  fyne.Resource
+	enabled atomic.Bool
+	changed chan struct{}
+
+	mu    sync.Mutex
+	shown fyne.Resource // Icon set by the tray menu, nil if not set yet.
+	last  []byte        // Content of the rendered icon shown, nil if the tray menu icon is shown.
+}
+
+// New creates a disabled Updater rendering samples of the active connection with set, static is the active icon
+// shown back when disabled.
+func New(samples func() (up, down []float64, connected bool), set func(fyne.Resource), static fyne.Resource) *Updater {
+	return &Updater{samples: samples, set: set, static: static, changed: make(chan struct{}, 1)}
+}
+
+// Enabled reports whether the icon is rendered.
+func (u *Updater) Enabled() bool {
+	return u.enabled.Load()
+}
+
+// SetEnabled enables or disables rendering, the static icon is shown back at once when disabled.
+func (u *Updater) SetEnabled(enabled bool) {
+	u.enabled.Store(enabled)
+	u.wake()
+}
+
+// SetIcon shows the icon of the tray menu. The static active icon is replaced with the rendered one at once
+// while enabled, the rendered icon is not restored over other icons till the static one is set back.
+func (u *Updater) SetIcon(icon fyne.Resource) {
+	u.mu.Lock()
+	u.shown = icon
+	u.last = nil
+	u.set(icon)
+	u.mu.Unlock()
+
+	u.wake()
+}
+
+// Run updates the icon every Interval while enabled, till ctx is done.
+func (u *Updater) Run(ctx context.Context) {
+	for {
+		u.update()
+
+		var tick <-chan time.Time // Nothing to render while disabled, wait to be enabled.
+		if u.Enabled() {
+			tick = time.After(Interval)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-u.changed:
+		case <-tick:
+		}
+	}
+}
+
+func (u *Updater) wake() {
+	select {
+	case u.changed <- struct{}{}:
+	default:
+	}
+}
+
+// update shows the rendered icon if it changed, or the tray menu icon if rendering was disabled.
+func (u *Updater) update() {
+	up, down, connected := u.samples()
+
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	if !u.Enabled() || !connected || (u.shown != nil && u.shown != u.static) {
+		if u.last != nil {
+			u.set(cmp.Or(u.shown, u.static))
+		}
+		u.last = nil
+
+		return
+	}
+
+	b, err := PNG(up, down)
+	if err != nil {
+		slog.Error("failed to render tray icon", "error", err)
+		return
+	}
+	if bytes.Equal(b, u.last) {
+		return
+	}
+	u.set(fyne.NewStaticResource("tray_activity.png", b))
+	u.last = b
+}
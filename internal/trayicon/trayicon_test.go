@@ -0,0 +1,141 @@
+package trayicon
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/png"
+	"testing"
+
+	"fyne.io/fyne/v2"
+	"github.com/stretchr/testify/require"
+)
+
+var transparent = color.NRGBA{}
+
+func TestRender_Idle(t *testing.T) {
+	for _, samples := range [][]float64{nil, {0, 0, 0}} {
+		img := Render(samples, samples)
+		require.Equal(t, image.Rect(0, 0, Size, Size), img.Bounds())
+		require.Equal(t, DownColor, img.NRGBAAt(0, Size-1), "baseline")
+		require.Equal(t, DownColor, img.NRGBAAt(Size-1, Size-baseline))
+		require.Equal(t, transparent, img.NRGBAAt(Size-1, Size-baseline-1), "no bars")
+	}
+}
+
+func TestRender_Bars(t *testing.T) {
+	img := Render([]float64{0, 2, 1}, []float64{4, 2, 0})
+	height := Size - baseline
+
+	// The newest sample is on the right: upload only, a quarter of the peak.
+	x := Size - barWidth
+	require.Equal(t, UpColor, img.NRGBAAt(x, Size-baseline-1))
+	require.Equal(t, UpColor, img.NRGBAAt(x, Size-baseline-height/4))
+	require.Equal(t, transparent, img.NRGBAAt(x, Size-baseline-height/4-1))
+	require.Equal(t, transparent, img.NRGBAAt(x+barWidth-barGap, Size-baseline-1), "gap between bars")
+
+	// The sample before is the peak: download at the bottom, upload stacked on top.
+	x -= barWidth
+	require.Equal(t, DownColor, img.NRGBAAt(x, Size-baseline-1))
+	require.Equal(t, DownColor, img.NRGBAAt(x, Size-baseline-height/2))
+	require.Equal(t, UpColor, img.NRGBAAt(x, Size-baseline-height/2-1))
+	require.Equal(t, UpColor, img.NRGBAAt(x, 0))
+
+	// The oldest sample is download only, also the peak, and nothing is drawn before it.
+	x -= barWidth
+	require.Equal(t, DownColor, img.NRGBAAt(x, 0))
+	require.Equal(t, transparent, img.NRGBAAt(x-1, Size-baseline-1))
+}
+
+func TestRender_LastSamples(t *testing.T) {
+	up := make([]float64, Bars+5)
+	up[0] = 100 // Not shown, so it does not scale the rest down.
+	up[len(up)-1] = 1
+	img := Render(up, make([]float64, len(up)))
+
+	require.Equal(t, UpColor, img.NRGBAAt(Size-barWidth, 0))
+	require.Equal(t, transparent, img.NRGBAAt(0, Size-baseline-1))
+}
+
+func TestPNG(t *testing.T) {
+	b, err := PNG([]float64{1, 2}, []float64{3, 4})
+	require.NoError(t, err)
+
+	decoded, err := png.Decode(bytes.NewReader(b))
+	require.NoError(t, err)
+	want := Render([]float64{1, 2}, []float64{3, 4})
+	for y := range Size {
+		for x := range Size {
+			require.Equal(t, want.NRGBAAt(x, y), color.NRGBAModel.Convert(decoded.At(x, y)), "pixel %d,%d", x, y)
+		}
+	}
+}
+
+type fakeTray struct {
+	up, down  []float64
+	connected bool
+	shown     []fyne.Resource
+}
+
+func (f *fakeTray) updater(static fyne.Resource) *Updater {
+	return New(
+		func() ([]float64, []float64, bool) { return f.up, f.down, f.connected },
+		func(r fyne.Resource) { f.shown = append(f.shown, r) },
+		static,
+	)
+}
+
+func TestUpdater(t *testing.T) {
+	static := fyne.NewStaticResource("active.png", nil)
+	f := &fakeTray{up: []float64{1}, down: []float64{2}, connected: true}
+	u := f.updater(static)
+
+	u.update()
+	require.Empty(t, f.shown, "disabled")
+
+	u.enabled.Store(true)
+	u.update()
+	u.update()
+	require.Len(t, f.shown, 1, "shown once while samples are the same")
+	require.NotEqual(t, static, f.shown[0])
+
+	f.up = append(f.up, 3)
+	u.update()
+	require.Len(t, f.shown, 2, "samples changed")
+
+	u.enabled.Store(false)
+	u.update()
+	require.Len(t, f.shown, 3)
+	require.Equal(t, static, f.shown[2], "static icon shown back")
+	u.update()
+	require.Len(t, f.shown, 3)
+
+	u.enabled.Store(true)
+	f.connected = false
+	u.update()
+	require.Len(t, f.shown, 3, "the tray menu shows its own icon when not connected")
+}
+
+func TestUpdater_SetIcon(t *testing.T) {
+	static := fyne.NewStaticResource("active.png", nil)
+	progress := fyne.NewStaticResource("progress.png", nil)
+	f := &fakeTray{up: []float64{1}, down: []float64{2}, connected: true}
+	u := f.updater(static)
+	u.enabled.Store(true)
+
+	u.SetIcon(static)
+	u.update()
+	require.Len(t, f.shown, 2)
+	rendered := f.shown[1]
+	require.NotEqual(t, static, rendered, "static icon is replaced")
+
+	u.SetIcon(progress)
+	u.update()
+	require.Equal(t, []fyne.Resource{static, rendered, progress}, f.shown, "other icons are kept")
+
+	// Samples are the same, but the rendered icon is shown back over the static icon.
+	u.SetIcon(static)
+	u.update()
+	require.Len(t, f.shown, 5)
+	require.Equal(t, rendered, f.shown[4])
+}
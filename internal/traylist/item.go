@@ -50,12 +50,6 @@ func (ci *trayItem[T]) setActive(active bool) {
 	} else {
 		ci.desk.SetSystemTrayIcon(ci.iconSet.LogoPassive)
 	}
-
-	ci.Value().SetActive(ci.active)
-}
-
-func (ci *trayItem[T]) toggle() {
-	ci.setActive(!ci.isActive())
 }
 
 func (ci *trayItem[T]) isActive() bool {
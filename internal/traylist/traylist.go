@@ -7,6 +7,9 @@ package traylist
 import (
 	"errors"
 	"fmt"
+	"maps"
+	"slices"
+	"sync"
 	"sync/atomic"
 
 	"fyne.io/fyne/v2"
@@ -31,6 +34,7 @@ var defaultIconSet = func() *IconSet {
 
 var (
 	ErrItemNotFound = errors.New("item not found")
+	ErrNotActive    = errors.New("item is not active")
 )
 
 type IconSet struct {
@@ -49,11 +53,16 @@ type value interface {
 	comparable
 }
 
+// List is safe for concurrent use, clicks of the menu, Click and Reconnect are serialized so connection changes
+// never overlap.
 type List[T value] struct {
 	menu    *Menu[T]
 	nextID  atomic.Int64 // Generates external session-persistent IDs for items.
+	mu      sync.RWMutex // Guards items and the menu, it is not held while onClick runs.
+	clickMu sync.Mutex   // Serializes clicks.
 	items   map[int]*trayItem[T]
 	onClick func(int) error
+	labeler func(T) string
 
 	itemsStartIDx int
 	footerLen     int
@@ -107,6 +116,7 @@ func New[T value](desk desktop.App, menu *fyne.Menu, insertIDx int, footerLen in
 		menu:          &Menu[T]{menu: menu, footerLen: footerLen},
 		items:         make(map[int]*trayItem[T]),
 		onClick:       func(i int) error { return nil },
+		labeler:       T.Label,
 		desk:          desk,
 		itemsStartIDx: insertIDx + 1,
 		footerLen:     footerLen,
@@ -124,51 +134,101 @@ func (mb *List[T]) OnItemClick(f func(int) error) {
 	mb.onClick = f
 }
 
+// SetLabeler sets how menu labels of items are made, e.g. to mark some of them. Labels of the values are used
+// by default, labels are updated on Refresh.
+func (mb *List[T]) SetLabeler(f func(T) string) {
+	mb.labeler = f
+}
+
 func (mb *List[T]) Add(data T) int {
 	defer mb.updateValues()
 	newID := int(mb.nextID.Add(1))
 	item := newTrayItem[T](data, mb.desk, mb.iconSet)
+	item.menuItem.Action = func() {
+		mb.clickMu.Lock()
+		defer mb.clickMu.Unlock()
+		mb.click(item)
+	}
 
+	mb.mu.Lock()
+	defer mb.mu.Unlock()
 	mb.menu.Insert(item)
 	mb.items[newID] = item
 
-	item.menuItem.Action = func() {
-		curID := -1
-		for k, v := range mb.items {
-			if v == item {
-				curID = k
-			}
-		}
-		if curID == -1 {
-			return
-		}
+	return newID
+}
 
-		mb.disableAll(true)
-		defer mb.disableAll(false)
-		item.setInProgress()
+// click calls onClick for the item and toggles it, it must be called with clickMu held.
+func (mb *List[T]) click(item *trayItem[T]) {
+	curID := mb.idOf(item)
+	if curID == -1 {
+		return
+	}
 
-		if err := mb.onClick(curID); err != nil {
-			defer item.setWarning()
-			mb.setLabel(err.Error())
+	mb.disableAll(true)
+	defer mb.disableAll(false)
+	mb.setInProgress(item)
 
-			if active := mb.getActive(); active != nil {
-				active.setActive(false)
-			}
+	if err := mb.onClick(curID); err != nil {
+		mb.fail(item, err)
 
-			return
-		}
+		return
+	}
 
-		// Render all active items as not active except ours
-		for id, itm := range mb.items {
-			if curID != id && itm.isActive() {
-				itm.setActive(false)
-			}
+	// Render all active items as not active except ours
+	active := !mb.isActive(item)
+	for _, itm := range mb.all() {
+		if itm != item && mb.isActive(itm) {
+			mb.setActive(itm, false)
 		}
+	}
+	mb.setActive(item, active)
+}
+
+// SetActive clicks the item of data if it is not in the active state already, it returns whether it was clicked.
+// The state is checked and changed under the same lock, so concurrent callers never toggle the item back.
+func (mb *List[T]) SetActive(data T, active bool) (bool, error) {
+	mb.clickMu.Lock()
+	defer mb.clickMu.Unlock()
 
-		item.toggle()
+	item := mb.find(data)
+	if item == nil {
+		return false, ErrItemNotFound
 	}
+	if mb.isActive(item) == active {
+		return false, nil
+	}
+	mb.click(item)
 
-	return newID
+	return true, nil
+}
+
+// Reconnect runs reconnect for the active item data showing progress like a click does,
+// the item is deactivated with a warning if reconnect fails.
+func (mb *List[T]) Reconnect(data T, reconnect func(T) error) error {
+	mb.clickMu.Lock()
+	defer mb.clickMu.Unlock()
+
+	item := mb.find(data)
+	if item == nil {
+		return ErrItemNotFound
+	}
+	if !mb.isActive(item) {
+		return ErrNotActive
+	}
+
+	mb.disableAll(true)
+	defer mb.disableAll(false)
+	mb.setInProgress(item)
+
+	if err := reconnect(data); err != nil {
+		mb.fail(item, err)
+
+		return err
+	}
+	mb.setActive(item, true)
+
+	return nil
 }
 
 func (mb *List[T]) Remove(i T) error {
@@ -178,6 +238,8 @@ func (mb *List[T]) Remove(i T) error {
 	}
 	defer mb.updateValues()
 
+	mb.mu.Lock()
+	defer mb.mu.Unlock()
 	for id, itm := range mb.items {
 		if itm.Value() == i {
 			if itm.isActive() {
@@ -201,6 +263,8 @@ func (mb *List[T]) Swap(i1, i2 T) error {
 	}
 	defer mb.Refresh()
 
+	mb.mu.Lock()
+	defer mb.mu.Unlock()
 	id1, id2 := -1, -1
 	for id, itm := range mb.items {
 		if itm.Value() != i1 && itm.Value() != i2 {
@@ -214,6 +278,9 @@ func (mb *List[T]) Swap(i1, i2 T) error {
 			id2 = id
 		}
 	}
+	if id1 == -1 || id2 == -1 {
+		return ErrItemNotFound
+	}
 
 	mb.menu.Swap(mb.items[id2].menuItem, mb.items[id1].menuItem)
 	mb.items[id1], mb.items[id2] = mb.items[id2], mb.items[id1]
@@ -221,12 +288,30 @@ func (mb *List[T]) Swap(i1, i2 T) error {
 	return nil
 }
 
+// Click imitates click on the menu item of data, so it goes through OnItemClick and updates the menu state.
+// It waits for a click in progress to finish.
+func (mb *List[T]) Click(data T) error {
+	mb.clickMu.Lock()
+	defer mb.clickMu.Unlock()
+
+	item := mb.find(data)
+	if item == nil {
+		return ErrItemNotFound
+	}
+	mb.click(item)
+
+	return nil
+}
+
 func (mb *List[T]) Refresh() {
 	mb.updateValues()
 }
 
 func (mb *List[T]) Get(id int) T {
-	itm := mb.getItem(id)
+	mb.mu.RLock()
+	defer mb.mu.RUnlock()
+
+	itm := mb.items[id]
 	if itm == nil {
 		var zero T
 		return zero
@@ -236,16 +321,15 @@ func (mb *List[T]) Get(id int) T {
 }
 
 func (mb *List[T]) getItem(id int) *trayItem[T] {
-	if mb.items[id] == nil {
-		return nil
-	}
+	mb.mu.RLock()
+	defer mb.mu.RUnlock()
 
 	return mb.items[id]
 }
 
 func (mb *List[T]) IsActive(id int) bool {
+	item := mb.getItem(id)
 	active := mb.getActive()
-	item := mb.items[id]
 	if active == nil || item == nil {
 		return false
 	}
@@ -254,13 +338,7 @@ func (mb *List[T]) IsActive(id int) bool {
 }
 
 func (mb *List[T]) HasActive() bool {
-	for _, itm := range mb.items {
-		if itm.isActive() {
-			return true
-		}
-	}
-
-	return false
+	return mb.getActive() != nil
 }
 
 func (mb *List[T]) GetActive() T {
@@ -277,25 +355,100 @@ func (mb *List[T]) Show() {
 	mb.desk.SetSystemTrayMenu(mb.menu.Menu())
 }
 
-func (mb *List[T]) setLabel(label string) {
-	mb.menu.SetTitle(label)
+// fail shows the error in the menu title and deactivates the active item, the failed item gets a warning icon.
+func (mb *List[T]) fail(item *trayItem[T], err error) {
+	mb.mu.Lock()
+	mb.menu.SetTitle(err.Error())
+	mb.mu.Unlock()
+
+	if active := mb.getActive(); active != nil {
+		mb.setActive(active, false)
+	}
+
+	mb.mu.Lock()
+	item.setWarning()
+	mb.mu.Unlock()
+}
+
+func (mb *List[T]) setInProgress(item *trayItem[T]) {
+	mb.mu.Lock()
+	item.setInProgress()
+	mb.mu.Unlock()
+}
+
+// setActive changes the item state, the value is notified without the lock as it may call back into the list.
+func (mb *List[T]) setActive(item *trayItem[T], active bool) {
+	mb.mu.Lock()
+	item.setActive(active)
+	mb.mu.Unlock()
+
+	item.Value().SetActive(active)
+}
+
+func (mb *List[T]) isActive(item *trayItem[T]) bool {
+	mb.mu.RLock()
+	defer mb.mu.RUnlock()
+
+	return item.isActive()
 }
 
 func (mb *List[T]) disableAll(disable bool) {
+	mb.mu.Lock()
 	for _, itm := range mb.items {
 		itm.menuItem.Disabled = disable
 	}
+	mb.mu.Unlock()
 	mb.menu.Refresh()
 }
 
 func (mb *List[T]) updateValues() {
+	mb.mu.RLock()
 	for _, itm := range mb.items {
-		itm.menuItem.Label = itm.Value().Label()
+		itm.menuItem.Label = mb.labeler(itm.Value())
 	}
+	mb.mu.RUnlock()
 	mb.menu.Refresh()
 }
 
+// all returns a snapshot of the items.
+func (mb *List[T]) all() []*trayItem[T] {
+	mb.mu.RLock()
+	defer mb.mu.RUnlock()
+
+	return slices.Collect(maps.Values(mb.items))
+}
+
+// idOf returns the external ID of the item, -1 if it is removed.
+func (mb *List[T]) idOf(item *trayItem[T]) int {
+	mb.mu.RLock()
+	defer mb.mu.RUnlock()
+
+	for id, itm := range mb.items {
+		if itm == item {
+			return id
+		}
+	}
+
+	return -1
+}
+
+func (mb *List[T]) find(data T) *trayItem[T] {
+	mb.mu.RLock()
+	defer mb.mu.RUnlock()
+
+	for _, itm := range mb.items {
+		if itm.Value() == data {
+			return itm
+		}
+	}
+
+	return nil
+}
+
 func (mb *List[T]) getActive() *trayItem[T] {
+	mb.mu.RLock()
+	defer mb.mu.RUnlock()
+
 	for _, itm := range mb.items {
 		if itm.isActive() {
 			return itm
@@ -2,6 +2,7 @@ package traylist
 
 import (
 	"errors"
+	"sync"
 	"testing"
 
 	"fyne.io/fyne/v2"
@@ -119,6 +120,13 @@ func TestTrayList_Operations(t *testing.T) {
 	list.getItem(2).menuItem.Action()
 	assertActive(2)
 
+	// Click from code behaves the same as in tray menu.
+	require.NoError(t, list.Click(newItems[1]))
+	assertActive(1)
+	require.NoError(t, list.Click(newItems[1]))
+	require.False(t, list.HasActive())
+	require.ErrorIs(t, list.Click(newItems[3]), ErrItemNotFound)
+
 	// Delete inactive items.
 	require.NoError(t, list.Remove(newItems[1]))
 	require.NoError(t, list.Remove(newItems[4]))
@@ -192,3 +200,71 @@ func setupList(desk desktop.App) *List[*mockItem] {
 
 	return list
 }
+
+func TestTrayList_SetActive(t *testing.T) {
+	list := setupList(deskMock{})
+	items := []*mockItem{{l: "Test 1"}, {l: "Test 2"}}
+	for _, item := range items {
+		list.Add(item)
+	}
+	clicks := 0
+	list.OnItemClick(func(int) error {
+		clicks++
+		return nil
+	})
+
+	// Concurrent callers activate the item once and never toggle it back.
+	var wg sync.WaitGroup
+	for range 10 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := list.SetActive(items[0], true)
+			require.NoError(t, err)
+		}()
+	}
+	wg.Wait()
+	require.Equal(t, 1, clicks)
+	require.Equal(t, items[0], list.GetActive())
+
+	changed, err := list.SetActive(items[1], false)
+	require.NoError(t, err)
+	require.False(t, changed)
+	changed, err = list.SetActive(items[0], false)
+	require.NoError(t, err)
+	require.True(t, changed)
+	require.False(t, list.HasActive())
+
+	_, err = list.SetActive(&mockItem{l: "missing"}, true)
+	require.ErrorIs(t, err, ErrItemNotFound)
+}
+
+func TestTrayList_Reconnect(t *testing.T) {
+	var lastTrayIcon fyne.Resource
+	list := setupList(deskMock{onIconSet: func(ic fyne.Resource) { lastTrayIcon = ic }})
+	item := &mockItem{l: "Test 1"}
+	list.Add(item)
+
+	require.ErrorIs(t, list.Reconnect(item, func(*mockItem) error { return nil }), ErrNotActive)
+	require.NoError(t, list.Click(item))
+
+	require.NoError(t, list.Reconnect(item, func(*mockItem) error { return nil }))
+	require.Equal(t, item, list.GetActive())
+	require.Equal(t, theme.MediaPlayIcon(), lastTrayIcon)
+
+	require.Error(t, list.Reconnect(item, func(*mockItem) error { return errors.New("tunnel is down") }))
+	require.False(t, list.HasActive())
+	require.Equal(t, theme.WarningIcon(), lastTrayIcon)
+	require.Equal(t, "tunnel is down", list.menu.Menu().Items[0].Label)
+}
+
+func TestTrayList_SetLabeler(t *testing.T) {
+	list := setupList(deskMock{})
+	item := &mockItem{l: "Test 1"}
+	id := list.Add(item)
+	require.Equal(t, "Test 1", list.getItem(id).menuItem.Label)
+
+	list.SetLabeler(func(m *mockItem) string { return m.l + " (expired)" })
+	list.Refresh()
+	require.Equal(t, "Test 1 (expired)", list.getItem(id).menuItem.Label)
+}
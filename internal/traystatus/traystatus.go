@@ -0,0 +1,128 @@
+/*
+Package traystatus keeps the tray tooltip and title up to date with the active connection and its speed.
+*/
+package traystatus
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"github.com/goxray/desktop/internal/netchart"
+)
+
+const (
+	// DefaultInterval is how often the status is updated unless configured otherwise.
+	DefaultInterval = 2 * time.Second
+	// IdleInterval limits updates while there is no traffic, e.g. only uptime changes.
+	IdleInterval = 30 * time.Second
+)
+
+// Status of the active connection.
+type Status struct {
+	Label       string
+	ConnectedAt time.Time
+	Up, Down    float64 // Current speed in Unit.
+	Unit        netchart.Unit
+}
+
+// Idle reports whether there is no traffic.
+func (s Status) Idle() bool {
+	return s.Up == 0 && s.Down == 0
+}
+
+// Format returns tray tooltip and title for the status, the title is empty when not connected.
+func Format(appName string, s Status, connected bool, now time.Time) (tooltip, title string) {
+	if !connected {
+		return appName, ""
+	}
+
+	speed := fmt.Sprintf("↑%s ↓%s", netchart.FormatRate(s.Up, s.Unit), netchart.FormatRate(s.Down, s.Unit))
+	tooltip = fmt.Sprintf("%s\n%s · %s\n%s", appName, s.Label, now.Sub(s.ConnectedAt).Round(time.Second), speed)
+
+	return tooltip, speed
+}
+
+// Updater periodically sets the tray status, updates are skipped if nothing changed and limited to IdleInterval
+// while there is no traffic.
+type Updater struct {
+	appName  string
+	status   func() (s Status, connected bool)
+	set      func(tooltip, title string)
+	interval atomic.Int64
+	changed  chan struct{}
+
+	// Accessed by the running goroutine only.
+	lastTooltip, lastTitle string
+	lastKey                string // Label of the connection shown, empty if not connected.
+	lastIdle               bool   // Whether the status shown had no traffic.
+	lastSet                time.Time
+}
+
+// New creates an Updater showing status of the active connection with set, appName is shown when not connected.
+func New(appName string, status func() (s Status, connected bool), set func(tooltip, title string)) *Updater {
+	u := &Updater{appName: appName, status: status, set: set, changed: make(chan struct{}, 1)}
+	u.interval.Store(int64(DefaultInterval))
+
+	return u
+}
+
+// Interval returns how often the status is updated, 0 if updates are disabled.
+func (u *Updater) Interval() time.Duration {
+	return time.Duration(u.interval.Load())
+}
+
+// SetInterval changes how often the status is updated, 0 disables updates and shows just the app name.
+func (u *Updater) SetInterval(d time.Duration) {
+	u.interval.Store(int64(max(d, 0)))
+	select {
+	case u.changed <- struct{}{}:
+	default:
+	}
+}
+
+// Run updates the status every interval till ctx is done.
+func (u *Updater) Run(ctx context.Context) {
+	for {
+		d := u.Interval()
+		var tick <-chan time.Time
+		if d > 0 {
+			u.update(time.Now())
+			tick = time.After(d)
+		} else {
+			u.show(u.appName, "", "", true, time.Now())
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-u.changed:
+		case <-tick:
+		}
+	}
+}
+
+// update shows the current status unless it is the same or idle updates are limited.
+func (u *Updater) update(now time.Time) {
+	s, connected := u.status()
+	tooltip, title := Format(u.appName, s, connected, now)
+	key, idle := "", !connected || s.Idle()
+	if connected {
+		key = s.Label
+	}
+
+	// Show at once when the connection changes or traffic stops, so the last speed does not stick.
+	if key == u.lastKey && idle && u.lastIdle && now.Sub(u.lastSet) < IdleInterval {
+		return
+	}
+	u.show(tooltip, title, key, idle, now)
+}
+
+func (u *Updater) show(tooltip, title, key string, idle bool, now time.Time) {
+	if tooltip == u.lastTooltip && title == u.lastTitle {
+		return
+	}
+	u.set(tooltip, title)
+	u.lastTooltip, u.lastTitle, u.lastKey, u.lastIdle, u.lastSet = tooltip, title, key, idle, now
+}
@@ -0,0 +1,109 @@
+package traystatus
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/goxray/desktop/internal/netchart"
+)
+
+func TestFormat(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	tooltip, title := Format("App", Status{}, false, now)
+	require.Equal(t, "App", tooltip)
+	require.Empty(t, title)
+
+	s := Status{Label: "vpn", ConnectedAt: now.Add(-time.Hour - 2*time.Second), Up: 1.5, Down: 0.25, Unit: netchart.MegabitsPerSecond}
+	tooltip, title = Format("App", s, true, now)
+	require.Equal(t, "App\nvpn · 1h0m2s\n↑1.5 Mb/s ↓250 Kb/s", tooltip)
+	require.Equal(t, "↑1.5 Mb/s ↓250 Kb/s", title)
+}
+
+type fakeTray struct {
+	status    Status
+	connected bool
+	shown     []string
+}
+
+func (f *fakeTray) updater() *Updater {
+	return New("App",
+		func() (Status, bool) { return f.status, f.connected },
+		func(tooltip, title string) { f.shown = append(f.shown, title) },
+	)
+}
+
+func TestUpdater_RateLimit(t *testing.T) {
+	start := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	f := &fakeTray{}
+	u := f.updater()
+
+	u.update(start)
+	u.update(start.Add(time.Second))
+	require.Equal(t, []string{""}, f.shown, "not connected, shown once")
+
+	f.connected = true
+	f.status = Status{Label: "vpn", ConnectedAt: start, Unit: netchart.MegabitsPerSecond}
+	u.update(start.Add(2 * time.Second))
+	require.Len(t, f.shown, 2, "connection changed")
+	u.update(start.Add(3 * time.Second))
+	u.update(start.Add(10 * time.Second))
+	require.Len(t, f.shown, 2, "idle, only uptime changed")
+	u.update(start.Add(2*time.Second + IdleInterval))
+	require.Len(t, f.shown, 3, "idle interval passed")
+
+	f.status.Up, f.status.Down = 1, 2
+	u.update(start.Add(33 * time.Second))
+	u.update(start.Add(34 * time.Second))
+	require.Equal(t, "↑1 Mb/s ↓2 Mb/s", f.shown[len(f.shown)-1])
+	require.Len(t, f.shown, 5, "updated every time with traffic")
+
+	f.status.Up, f.status.Down = 0, 0
+	u.update(start.Add(35 * time.Second))
+	require.Len(t, f.shown, 6, "traffic stopped, shown at once")
+	require.Equal(t, "↑0 bit/s ↓0 bit/s", f.shown[5])
+
+	f.connected = false
+	u.update(start.Add(36 * time.Second))
+	require.Equal(t, "", f.shown[len(f.shown)-1], "disconnected, shown at once")
+}
+
+func TestUpdater_Run(t *testing.T) {
+	var mu sync.Mutex
+	var tooltips []string
+	u := New("App", func() (Status, bool) {
+		return Status{Label: "vpn", ConnectedAt: time.Now(), Up: 1, Unit: netchart.MegabitsPerSecond}, true
+	}, func(tooltip, _ string) {
+		mu.Lock()
+		defer mu.Unlock()
+		tooltips = append(tooltips, tooltip)
+	})
+	count := func() int {
+		mu.Lock()
+		defer mu.Unlock()
+		return len(tooltips)
+	}
+
+	u.SetInterval(0)
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		u.Run(ctx)
+		close(done)
+	}()
+
+	require.Eventually(t, func() bool { return count() == 1 }, time.Second, time.Millisecond)
+	mu.Lock()
+	require.Equal(t, "App", tooltips[0], "disabled")
+	mu.Unlock()
+
+	u.SetInterval(time.Millisecond)
+	require.Equal(t, time.Millisecond, u.Interval())
+	require.Eventually(t, func() bool { return count() > 1 }, time.Second, time.Millisecond)
+
+	cancel()
+	<-done
+}
@@ -0,0 +1,167 @@
+package usage
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+)
+
+// Resolution of exported persisted usage.
+type Resolution string
+
+const (
+	ResolutionDay   Resolution = "day"
+	ResolutionMonth Resolution = "month"
+)
+
+// Format of exported tables.
+type Format string
+
+const (
+	FormatCSV  Format = "csv"
+	FormatJSON Format = "json"
+)
+
+// UnitBytes is the unit of persisted usage, recorded series are in the unit of their recorder.
+const UnitBytes = "bytes"
+
+var (
+	ErrUnknownResolution = errors.New("unknown resolution")
+	ErrUnknownFormat     = errors.New("unknown format")
+)
+
+// Row is traffic in the period starting at Time.
+type Row struct {
+	Time     time.Time
+	Upload   float64
+	Download float64
+}
+
+// Table is exported traffic of a single item.
+type Table struct {
+	Item string
+	Unit string
+	Rows []Row
+}
+
+// Rows returns persisted usage of item for each day or month in [from, to], oldest first.
+// Days are only kept for a bit more than a year, older days are zero.
+func (s *Store) Rows(itemID string, from, to time.Time, res Resolution) ([]Row, error) {
+	from, to = from.Local(), to.Local()
+	var start time.Time
+	var next func(time.Time) time.Time
+	var layout string
+	switch res {
+	case ResolutionDay:
+		start = time.Date(from.Year(), from.Month(), from.Day(), 0, 0, 0, 0, time.Local)
+		next = func(t time.Time) time.Time { return t.AddDate(0, 0, 1) }
+		layout = dayLayout
+	case ResolutionMonth:
+		start = time.Date(from.Year(), from.Month(), 1, 0, 0, 0, 0, time.Local)
+		next = func(t time.Time) time.Time { return t.AddDate(0, 1, 0) }
+		layout = monthLayout
+	default:
+		return nil, fmt.Errorf("%w: %q", ErrUnknownResolution, res)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	u := s.items[itemID]
+	rows := make([]Row, 0)
+	for t := start; !t.After(to); t = next(t) {
+		var c Counter
+		if u != nil {
+			if res == ResolutionDay {
+				c = u.Days[t.Format(layout)]
+			} else {
+				c = u.Months[t.Format(layout)]
+			}
+		}
+		rows = append(rows, Row{Time: t, Upload: float64(c.Read), Download: float64(c.Written)})
+	}
+
+	return rows, nil
+}
+
+// SeriesRows converts recorder series with samples every interval, the last one recorded at end, into rows.
+func SeriesRows(read, written []float64, interval time.Duration, end time.Time) []Row {
+	rows := make([]Row, 0, len(read))
+	for i := range read {
+		rows = append(rows, Row{
+			Time:     end.Add(-time.Duration(len(read)-1-i) * interval),
+			Upload:   read[i],
+			Download: written[i],
+		})
+	}
+
+	return rows
+}
+
+// Write writes table in format, timestamps are in RFC 3339.
+func Write(w io.Writer, format Format, t Table) error {
+	switch format {
+	case FormatCSV:
+		return writeCSV(w, t)
+	case FormatJSON:
+		return writeJSON(w, t)
+	}
+
+	return fmt.Errorf("%w: %q", ErrUnknownFormat, format)
+}
+
+func writeCSV(w io.Writer, t Table) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write([]string{"item", "time", "unit", "upload", "download"}); err != nil {
+		return fmt.Errorf("write csv: %w", err)
+	}
+	for _, r := range t.Rows {
+		record := []string{
+			t.Item,
+			r.Time.Format(time.RFC3339),
+			t.Unit,
+			strconv.FormatFloat(r.Upload, 'f', -1, 64),
+			strconv.FormatFloat(r.Download, 'f', -1, 64),
+		}
+		if err := cw.Write(record); err != nil {
+			return fmt.Errorf("write csv: %w", err)
+		}
+	}
+	cw.Flush()
+	if err := cw.Error(); err != nil {
+		return fmt.Errorf("write csv: %w", err)
+	}
+
+	return nil
+}
+
+type jsonRow struct {
+	Time     string  `json:"time"`
+	Upload   float64 `json:"upload"`
+	Download float64 `json:"download"`
+}
+
+type jsonTable struct {
+	Item string    `json:"item"`
+	Unit string    `json:"unit"`
+	Rows []jsonRow `json:"rows"`
+}
+
+func writeJSON(w io.Writer, t Table) error {
+	out := jsonTable{Item: t.Item, Unit: t.Unit, Rows: make([]jsonRow, 0, len(t.Rows))}
+	for _, r := range t.Rows {
+		out.Rows = append(out.Rows, jsonRow{Time: r.Time.Format(time.RFC3339), Upload: r.Upload, Download: r.Download})
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(out); err != nil {
+		return fmt.Errorf("write json: %w", err)
+	}
+
+	return nil
+}
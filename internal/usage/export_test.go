@@ -0,0 +1,67 @@
+package usage
+
+import (
+	"bytes"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestStore_Rows(t *testing.T) {
+	s, err := Open(filepath.Join(t.TempDir(), "usage.json"))
+	require.NoError(t, err)
+	s.Add("a", 100, 1000, day(1, 10))
+	s.Add("a", 10, 20, day(3, 0))
+	s.Add("a", 1, 2, time.Date(2024, 2, 1, 0, 0, 0, 0, time.Local))
+
+	rows, err := s.Rows("a", day(1, 12), day(3, 1), ResolutionDay)
+	require.NoError(t, err)
+	require.Equal(t, []Row{
+		{Time: day(1, 0), Upload: 100, Download: 1000},
+		{Time: day(2, 0)},
+		{Time: day(3, 0), Upload: 10, Download: 20},
+	}, rows)
+
+	rows, err = s.Rows("a", day(15, 0), time.Date(2024, 2, 10, 0, 0, 0, 0, time.Local), ResolutionMonth)
+	require.NoError(t, err)
+	require.Equal(t, []Row{
+		{Time: day(1, 0), Upload: 110, Download: 1020},
+		{Time: time.Date(2024, 2, 1, 0, 0, 0, 0, time.Local), Upload: 1, Download: 2},
+	}, rows)
+
+	rows, err = s.Rows("unknown", day(1, 0), day(2, 0), ResolutionDay)
+	require.NoError(t, err)
+	require.Len(t, rows, 2)
+
+	_, err = s.Rows("a", day(1, 0), day(2, 0), "week")
+	require.ErrorIs(t, err, ErrUnknownResolution)
+}
+
+func TestSeriesRows(t *testing.T) {
+	end := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	rows := SeriesRows([]float64{1, 2, 3}, []float64{4, 5, 6}, time.Minute, end)
+	require.Equal(t, []Row{
+		{Time: end.Add(-2 * time.Minute), Upload: 1, Download: 4},
+		{Time: end.Add(-time.Minute), Upload: 2, Download: 5},
+		{Time: end, Upload: 3, Download: 6},
+	}, rows)
+}
+
+func TestWrite(t *testing.T) {
+	table := Table{Item: "my, item", Unit: UnitBytes, Rows: []Row{
+		{Time: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), Upload: 1, Download: 2.5},
+	}}
+
+	buf := &bytes.Buffer{}
+	require.NoError(t, Write(buf, FormatCSV, table))
+	require.Equal(t, "item,time,unit,upload,download\n\"my, item\",2024-01-01T00:00:00Z,bytes,1,2.5\n", buf.String())
+
+	buf.Reset()
+	require.NoError(t, Write(buf, FormatJSON, table))
+	require.JSONEq(t, `{"item":"my, item","unit":"bytes","rows":[{"time":"2024-01-01T00:00:00Z","upload":1,"download":2.5}]}`,
+		buf.String())
+
+	require.ErrorIs(t, Write(buf, "xml", table), ErrUnknownFormat)
+}
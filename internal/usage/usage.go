@@ -0,0 +1,233 @@
+/*
+Package usage keeps lifetime, daily and monthly traffic usage of connections on disk.
+*/
+package usage
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+const (
+	dayLayout   = "2006-01-02"
+	monthLayout = "2006-01"
+	// dayRetention bounds the file size, monthly totals are kept forever.
+	dayRetention = 400 * 24 * time.Hour
+)
+
+// Counter is number of bytes for uplink (Read) and downlink (Written).
+type Counter struct {
+	Read    int64 `json:"read"`
+	Written int64 `json:"written"`
+}
+
+func (c Counter) add(read, written int64) Counter {
+	return Counter{Read: c.Read + read, Written: c.Written + written}
+}
+
+// ItemUsage is usage of a single connection.
+type ItemUsage struct {
+	Lifetime Counter            `json:"lifetime"`
+	Days     map[string]Counter `json:"days"`   // By local date.
+	Months   map[string]Counter `json:"months"` // By local month.
+}
+
+func newItemUsage() *ItemUsage {
+	return &ItemUsage{Days: map[string]Counter{}, Months: map[string]Counter{}}
+}
+
+// Store is a file backed usage storage, changes are kept in memory till Flush.
+type Store struct {
+	path string
+
+	mu    sync.Mutex
+	items map[string]*ItemUsage
+	dirty bool
+}
+
+// New creates an empty Store, the file at path is overwritten on the first Flush.
+func New(path string) *Store {
+	return &Store{path: path, items: map[string]*ItemUsage{}}
+}
+
+// Open loads usage from the file at path, the file is created on the first Flush.
+func Open(path string) (*Store, error) {
+	s := New(path)
+
+	b, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read usage: %w", err)
+	}
+	if err := json.Unmarshal(b, &s.items); err != nil {
+		return nil, fmt.Errorf("unmarshal usage: %w", err)
+	}
+	for _, u := range s.items {
+		if u.Days == nil {
+			u.Days = map[string]Counter{}
+		}
+		if u.Months == nil {
+			u.Months = map[string]Counter{}
+		}
+	}
+
+	return s, nil
+}
+
+// Add counts bytes of item at the given time.
+func (s *Store) Add(itemID string, read, written int, at time.Time) {
+	if read <= 0 && written <= 0 {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	u, ok := s.items[itemID]
+	if !ok {
+		u = newItemUsage()
+		s.items[itemID] = u
+	}
+
+	at = at.Local()
+	day, month := at.Format(dayLayout), at.Format(monthLayout)
+	if _, ok := u.Days[day]; !ok {
+		prune(u, at)
+	}
+	u.Lifetime = u.Lifetime.add(int64(read), int64(written))
+	u.Days[day] = u.Days[day].add(int64(read), int64(written))
+	u.Months[month] = u.Months[month].add(int64(read), int64(written))
+	s.dirty = true
+}
+
+// prune removes days older than dayRetention.
+func prune(u *ItemUsage, now time.Time) {
+	oldest := now.Add(-dayRetention).Format(dayLayout)
+	for day := range u.Days {
+		if day < oldest {
+			delete(u.Days, day)
+		}
+	}
+}
+
+// Lifetime returns all bytes ever counted for item.
+func (s *Store) Lifetime(itemID string) (read, written int64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if u, ok := s.items[itemID]; ok {
+		return u.Lifetime.Read, u.Lifetime.Written
+	}
+
+	return 0, 0
+}
+
+// Month returns bytes of item counted in the month of t.
+func (s *Store) Month(itemID string, t time.Time) (read, written int64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if u, ok := s.items[itemID]; ok {
+		c := u.Months[t.Local().Format(monthLayout)]
+		return c.Read, c.Written
+	}
+
+	return 0, 0
+}
+
+// Daily returns bytes of item for each of the days ending with the day of to, oldest first.
+func (s *Store) Daily(itemID string, to time.Time, days int) (read, written []int64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	read, written = make([]int64, days), make([]int64, days)
+	u, ok := s.items[itemID]
+	if !ok {
+		return read, written
+	}
+
+	to = to.Local()
+	for i := range days {
+		c := u.Days[to.AddDate(0, 0, i-days+1).Format(dayLayout)]
+		read[i], written[i] = c.Read, c.Written
+	}
+
+	return read, written
+}
+
+// Range returns bytes of item counted in [from, to] by days.
+func (s *Store) Range(itemID string, from, to time.Time) (read, written int64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	u, ok := s.items[itemID]
+	if !ok {
+		return 0, 0
+	}
+
+	first, last := from.Local().Format(dayLayout), to.Local().Format(dayLayout)
+	for day, c := range u.Days {
+		if day >= first && day <= last {
+			read += c.Read
+			written += c.Written
+		}
+	}
+
+	return read, written
+}
+
+// Flush writes usage to the file if anything changed since the last Flush.
+func (s *Store) Flush() error {
+	s.mu.Lock()
+	if !s.dirty {
+		s.mu.Unlock()
+		return nil
+	}
+	b, err := json.Marshal(s.items)
+	s.dirty = false
+	s.mu.Unlock()
+	if err != nil {
+		return fmt.Errorf("marshal usage: %w", err)
+	}
+
+	// Write to a temporary file first, so the previous state survives a crash in the middle of writing.
+	if err := os.MkdirAll(filepath.Dir(s.path), 0o755); err != nil {
+		return fmt.Errorf("create usage dir: %w", err)
+	}
+	tmp := s.path + ".tmp"
+	if err := os.WriteFile(tmp, b, 0o644); err != nil {
+		return fmt.Errorf("write usage: %w", err)
+	}
+	if err := os.Rename(tmp, s.path); err != nil {
+		return fmt.Errorf("replace usage: %w", err)
+	}
+
+	return nil
+}
+
+// AutoFlush calls Flush every interval and once more when ctx is done. It blocks till ctx is done.
+func (s *Store) AutoFlush(ctx context.Context, interval time.Duration) {
+	for {
+		select {
+		case <-ctx.Done():
+			if err := s.Flush(); err != nil {
+				slog.Error("flush usage", "err", err)
+			}
+			return
+		case <-time.After(interval):
+		}
+
+		if err := s.Flush(); err != nil {
+			slog.Error("flush usage", "err", err)
+		}
+	}
+}
@@ -0,0 +1,90 @@
+package usage
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func day(d, hour int) time.Time {
+	return time.Date(2024, 1, d, hour, 0, 0, 0, time.Local)
+}
+
+func TestStore(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "sub", "usage.json")
+	s, err := Open(path)
+	require.NoError(t, err)
+
+	s.Add("a", 100, 1000, day(1, 10))
+	s.Add("a", 50, 500, day(1, 23))
+	s.Add("a", 10, 20, day(3, 0))
+	s.Add("a", 0, 0, day(4, 0)) // Ignored.
+	s.Add("b", 1, 2, time.Date(2024, 2, 1, 0, 0, 0, 0, time.Local))
+
+	read, written := s.Lifetime("a")
+	require.Equal(t, int64(160), read)
+	require.Equal(t, int64(1520), written)
+
+	read, written = s.Month("a", day(15, 0))
+	require.Equal(t, int64(160), read)
+	require.Equal(t, int64(1520), written)
+	read, _ = s.Month("b", day(15, 0))
+	require.Zero(t, read)
+
+	dr, dw := s.Daily("a", day(4, 12), 4)
+	require.Equal(t, []int64{150, 0, 10, 0}, dr)
+	require.Equal(t, []int64{1500, 0, 20, 0}, dw)
+	dr, _ = s.Daily("unknown", day(4, 12), 2)
+	require.Equal(t, []int64{0, 0}, dr)
+
+	read, written = s.Range("a", day(2, 0), day(3, 0))
+	require.Equal(t, int64(10), read)
+	require.Equal(t, int64(20), written)
+
+	// Usage survives restarts.
+	require.NoError(t, s.Flush())
+	loaded, err := Open(path)
+	require.NoError(t, err)
+	read, written = loaded.Lifetime("a")
+	require.Equal(t, int64(160), read)
+	require.Equal(t, int64(1520), written)
+	dr, _ = loaded.Daily("a", day(3, 0), 3)
+	require.Equal(t, []int64{150, 0, 10}, dr)
+}
+
+func TestStore_Retention(t *testing.T) {
+	s, err := Open(filepath.Join(t.TempDir(), "usage.json"))
+	require.NoError(t, err)
+
+	old := time.Date(2023, 1, 1, 12, 0, 0, 0, time.Local)
+	s.Add("a", 1, 1, old)
+	s.Add("a", 1, 1, old.Add(dayRetention+48*time.Hour))
+
+	require.Len(t, s.items["a"].Days, 1)
+	require.Len(t, s.items["a"].Months, 2, "months are kept")
+	read, _ := s.Lifetime("a")
+	require.Equal(t, int64(2), read)
+}
+
+func TestStore_Flush(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "usage.json")
+	s, err := Open(path)
+	require.NoError(t, err)
+
+	// Nothing to write.
+	require.NoError(t, s.Flush())
+	_, err = os.Stat(path)
+	require.ErrorIs(t, err, os.ErrNotExist)
+
+	s.Add("a", 1, 1, day(1, 0))
+	require.NoError(t, s.Flush())
+	_, err = os.Stat(path)
+	require.NoError(t, err)
+
+	require.NoError(t, os.WriteFile(path, []byte("{broken"), 0o644))
+	_, err = Open(path)
+	require.Error(t, err)
+}
@@ -1,13 +1,18 @@
 package main
 
 import (
+	"context"
 	"embed"
 	"errors"
 	"flag"
 	"fmt"
 	"log/slog"
+	"path/filepath"
 	"runtime"
 	"runtime/debug"
+	"sync"
+	"sync/atomic"
+	"time"
 
 	"fyne.io/fyne/v2"
 	"fyne.io/fyne/v2/app"
@@ -18,9 +23,22 @@ import (
 
 	"github.com/goxray/desktop/icon"
 	"github.com/goxray/desktop/internal/connlist"
+	"github.com/goxray/desktop/internal/events"
+	"github.com/goxray/desktop/internal/metrics"
+	"github.com/goxray/desktop/internal/netrules"
 	"github.com/goxray/desktop/internal/osspecific/dock"
+	"github.com/goxray/desktop/internal/osspecific/netid"
+	"github.com/goxray/desktop/internal/osspecific/netmon"
 	"github.com/goxray/desktop/internal/osspecific/root"
+	"github.com/goxray/desktop/internal/quota"
+	"github.com/goxray/desktop/internal/schedule"
+	"github.com/goxray/desktop/internal/sessions"
+	"github.com/goxray/desktop/internal/subscription"
+	"github.com/goxray/desktop/internal/trash"
+	"github.com/goxray/desktop/internal/trayicon"
 	"github.com/goxray/desktop/internal/traylist"
+	"github.com/goxray/desktop/internal/traystatus"
+	"github.com/goxray/desktop/internal/usage"
 	"github.com/goxray/desktop/theme"
 	"github.com/goxray/desktop/window"
 
@@ -29,6 +47,17 @@ import (
 
 const (
 	AppTitleName = "GoXRay VPN Client"
+
+	// Network changes come in bursts, wait for them to settle before reacting.
+	networkChangeDebounce = 3 * time.Second
+	// Reconnecting changes routes itself, so ignore network changes for a while after reacting.
+	networkChangeCooldown = 10 * time.Second
+	healthCheckTimeout    = 5 * time.Second
+	usageFlushInterval    = time.Minute
+	usageFileName         = "usage.json"
+	sessionsFileName      = "sessions.json"
+	sessionsLimit         = 1000
+	trashExpireInterval   = time.Hour
 )
 
 var MenuIcons = &traylist.IconSet{
@@ -50,7 +79,6 @@ func initialize() {
 }
 
 func onstart() {
-	systray.SetTooltip(AppTitleName)
 	dock.HideIconInDock()
 }
 
@@ -58,26 +86,61 @@ func main() {
 	a := app.New()
 	initialize()
 	a.Settings().SetTheme(&theme.AppTheme{Variant: getThemeVariant()})
-	a.Lifecycle().SetOnStarted(onstart)
 	if err := lang.AddTranslationsFS(translations, "translation"); err != nil {
 		slog.Warn("failed to init translations", "error", err)
 	}
 
 	items := connlist.New()
 	list := binding.BindUntypedList(items.AllUntyped())
-	trayMenu := traylist.NewDefault[*connlist.Item](lang.L(AppTitleName), toDesktopApp(a), MenuIcons)
+	trayIcon := trayicon.New(activeSamples(items), toDesktopApp(a).SetSystemTrayIcon, MenuIcons.LogoActive)
+	trayMenu := traylist.NewDefault[*connlist.Item](lang.L(AppTitleName), trayIconDesktop{toDesktopApp(a), trayIcon}, MenuIcons)
 	settingsLoader := NewSaveFile(a.Preferences())
+	networkRules := netrules.New()
+	scheduleRules := schedule.NewRules()
+	usageStore := openUsage(a)
+	quotas := quota.New()
+	collector := metrics.New(metricsItems(items))
+	metricsServer := metrics.NewServer(collector)
+	eventLog := events.NewLog(recentEventsLimit)
+	history := openSessions(a)
+	trayStatus := traystatus.New(lang.L(AppTitleName), activeStatus(items), setTrayStatus)
+	deleted := trash.New[SavedState]()
+	subs := subscription.New()
+	subscriptionSettings := NewSubscriptionSettings(subs, items, DeleteItemH(items, deleted))
 
 	// Tray menu setup.
-	var settingsWindow *window.Settings[*connlist.Item]
+	// Settings window is refreshed from background goroutines, e.g. the traffic sampler.
+	var settingsWindow atomic.Pointer[window.Settings[*connlist.Item]]
 	trayMenu.OnSettingsClick(func() {
-		if settingsWindow == nil {
-			settingsWindow = window.NewSettings(a, list, AddFormH(items), UpdateFormH(), DeleteItemH(items), SwapItemH(items))
-			settingsWindow.OnClosed(func() { settingsWindow = nil })
+		w := settingsWindow.Load()
+		if w == nil {
+			w = window.NewSettings(a, list, window.SettingsDeps[*connlist.Item]{
+				OnAdd:         AddFormH(items),
+				OnAddChain:    AddChainH(items),
+				OnAddBalancer: AddBalancerH(items),
+				OnUpdate:      UpdateFormH(),
+				ParseConfig:   connlist.ParseXRayConfig,
+				OnDelete:      DeleteItemH(items, deleted),
+				OnSwap:        SwapItemH(items),
+				NetworkRules:  NewNetworkRulesSettings(networkRules),
+				ScheduleRules: NewScheduleSettings(scheduleRules),
+				Usage:         NewUsageSettings(usageStore, items),
+				Quotas:        NewQuotaSettings(quotas, usageStore),
+				Subscriptions: subscriptionSettings,
+				Metrics:       NewMetricsSettings(metricsServer, settingsLoader),
+				Events:        NewConnectionEvents(eventLog),
+				Sessions:      NewSessionSettings(history),
+				Tray:          NewTraySettings(trayStatus, trayIcon, settingsLoader),
+				Versions:      NewVersionHistory(items),
+				Trash:         NewTrashSettings(deleted, items, settingsLoader),
+			})
+			w.OnClosed(func() { settingsWindow.Store(nil) })
+			settingsWindow.Store(w)
 		}
-		settingsWindow.Show()
+		w.Show()
 	})
-	trayMenu.OnItemClick(ConnectHandler(trayMenu))
+	trayMenu.OnItemClick(ConnectHandler(trayMenu, QuotaGuard(quotas, usageStore)))
+	trayMenu.SetLabeler(subscriptionTrayLabel(subs))
 	trayMenu.Show()
 
 	// Update all UI elements when items are updated.
@@ -102,20 +165,99 @@ func main() {
 	items.OnChange(func() {
 		trayMenu.Refresh()
 		settingsLoader.Update(items)
-		if settingsWindow != nil {
-			settingsWindow.Refresh()
+		if w := settingsWindow.Load(); w != nil {
+			w.Refresh()
+		}
+	})
+	networkRules.OnChange(func() { settingsLoader.UpdateNetworkRules(networkRules) })
+	scheduleRules.OnChange(func() { settingsLoader.UpdateSchedule(scheduleRules) })
+	quotas.OnChange(func() { settingsLoader.UpdateQuotas(quotas) })
+	deleted.OnChange(func() { settingsLoader.UpdateTrash(deleted) })
+	subs.OnChange(func() {
+		settingsLoader.UpdateSubscriptions(subs)
+		trayMenu.Refresh() // Update subscription marks.
+		if w := settingsWindow.Load(); w != nil {
+			w.Refresh()
+		}
+	})
+	items.OnConnect(func(item *connlist.Item, took time.Duration, err error) {
+		collector.ObserveConnect(item.ID(), took, err)
+		logConnectionEvent(eventLog, item, events.KindConnected, err)
+		if err != nil {
+			history.Fail(item.ID(), item.Label(), time.Now(), err)
+		} else {
+			history.Start(item.ID(), item.Label(), time.Now())
 		}
 	})
+	items.OnDisconnect(func(item *connlist.Item, reason connlist.DisconnectReason, err error) {
+		logConnectionEvent(eventLog, item, events.KindDisconnected, err)
+		history.End(item.ID(), time.Now(), sessions.Reason(reason), err)
+		if err := history.Flush(); err != nil {
+			slog.Error(err.Error())
+		}
+	})
+	checkQuota := QuotaH(a, trayMenu, items, quotas, usageStore)
+	items.OnTraffic(func(item *connlist.Item, read, written int) {
+		usageStore.Add(item.ID(), read, written, time.Now())
+		history.AddTraffic(item.ID(), read, written)
+		if w := settingsWindow.Load(); checkQuota(item) && w != nil {
+			w.Refresh() // Update quota badges.
+		}
+	})
+	go usageStore.AutoFlush(context.Background(), usageFlushInterval)
+	// Save usage and sessions counted till the very end, after connections are closed.
+	defer func() {
+		if err := errors.Join(usageStore.Flush(), history.Flush()); err != nil {
+			slog.Error(err.Error())
+		}
+	}()
 	// Disconnect any active connections on quitting/panic.
 	defer func() {
 		if trayMenu.HasActive() {
-			if err := trayMenu.GetActive().Disconnect(); err != nil {
+			if err := trayMenu.GetActive().DisconnectWith(connlist.DisconnectShutdown); err != nil {
 				slog.Error(err.Error())
 			}
 		}
 	}()
 
 	settingsLoader.Load(items) // Initialize items from savefile and update windows/tray with new items.
+	settingsLoader.LoadNetworkRules(networkRules)
+	settingsLoader.LoadSchedule(scheduleRules)
+	settingsLoader.LoadQuotas(quotas)
+	settingsLoader.LoadTrash(deleted)
+	settingsLoader.LoadSubscriptions(subs)
+	deleted.SetMaxAge(settingsLoader.LoadTrashExpiry())
+	deleted.Expire(time.Now())
+	go deleted.AutoExpire(context.Background(), trashExpireInterval)
+	trayStatus.SetInterval(settingsLoader.LoadTrayStatusInterval())
+	trayIcon.SetEnabled(settingsLoader.LoadTrayIconGraph())
+	if settingsLoader.LoadMetricsEnabled() {
+		if err := metricsServer.Start(metricsAddr); err != nil {
+			slog.Error("failed to start metrics", "error", err)
+		}
+	}
+	defer func() { _ = metricsServer.Stop() }()
+
+	// Apply network rules and restore the active connection after network changes and system resume.
+	applyNetworkRules := NetworkRulesH(trayMenu, items, networkRules)
+	onNetworkChange := NetworkChangeH(a, trayMenu)
+	monitor := netmon.New()
+	defer monitor.Close()
+	watcher := netmon.NewWatcher(monitor, networkChangeDebounce, networkChangeCooldown)
+	go watcher.Run(context.Background(), func(ev netmon.Event) {
+		if !applyNetworkRules() {
+			onNetworkChange(ev)
+		}
+	})
+	scheduler := schedule.NewScheduler(scheduleRules, nil)
+	a.Lifecycle().SetOnStarted(func() {
+		onstart()
+		go applyNetworkRules() // Rules for the network we start in.
+		go scheduler.Run(context.Background(), ScheduleH(trayMenu, items))
+		go trayStatus.Run(context.Background())
+		go trayIcon.Run(context.Background())
+		go RefreshSubscriptions(context.Background(), a, subscriptionSettings, subs)
+	})
 
 	if runtime.GOOS == "linux" {
 		systray.Register(trayMenu.Refresh, func() {})
@@ -123,8 +265,29 @@ func main() {
 	a.Run()
 }
 
-func DeleteItemH(list *connlist.Collection) func(itm *connlist.Item) error {
+// openUsage loads persisted traffic usage from the app storage, starting over if the file is broken.
+func openUsage(a fyne.App) *usage.Store {
+	path := filepath.Join(a.Storage().RootURI().Path(), usageFileName)
+	store, err := usage.Open(path)
+	if err != nil {
+		slog.Error("failed to load usage, starting over", "error", err)
+		return usage.New(path)
+	}
+
+	return store
+}
+
+// DeleteItemH moves the item to the trash, it can be restored until it expires.
+func DeleteItemH(list *connlist.Collection, bin *trash.Bin[SavedState]) func(itm *connlist.Item) error {
 	return func(itm *connlist.Item) error {
+		if deps := list.Dependents(itm); len(deps) > 0 {
+			if deps[0].IsBalancer() {
+				return fmt.Errorf("%w: %q", connlist.ErrItemIsMember, deps[0].Label())
+			}
+
+			return fmt.Errorf("%w: %q", connlist.ErrItemIsChainHop, deps[0].Label())
+		}
+		bin.Add(itm.ID(), itm.Label(), serialize(itm), time.Now())
 		list.RemoveItem(itm)
 
 		return nil
@@ -157,12 +320,28 @@ func AddFormH(list *connlist.Collection) func(data window.FormData) error {
 	}
 }
 
-func ConnectHandler(trayItems *traylist.List[*connlist.Item]) func(id int) error {
+func AddChainH(list *connlist.Collection) func(label string, hops []*connlist.Item) error {
+	return func(label string, hops []*connlist.Item) error {
+		return list.AddChain(label, hops...)
+	}
+}
+
+func AddBalancerH(list *connlist.Collection) func(label, strategy string, members []*connlist.Item) error {
+	return func(label, strategy string, members []*connlist.Item) error {
+		return list.AddBalancer(label, strategy, members...)
+	}
+}
+
+// ConnectHandler toggles the clicked item, guard may refuse to connect it.
+func ConnectHandler(trayItems *traylist.List[*connlist.Item], guard func(item *connlist.Item) error) func(id int) error {
 	return func(id int) error {
 		// If clicked item is connected - just disconnect and return.
 		if trayItems.IsActive(id) {
 			return trayItems.Get(id).Disconnect()
 		}
+		if err := guard(trayItems.Get(id)); err != nil {
+			return err
+		}
 
 		// Disconnect active connections before connecting the clicked one.
 		if trayItems.HasActive() {
@@ -176,6 +355,136 @@ func ConnectHandler(trayItems *traylist.List[*connlist.Item]) func(id int) error
 	}
 }
 
+// NetworkRulesH applies the first matching network rule when the network identity changes,
+// returns true if a connection was made or dropped by the rule.
+func NetworkRulesH(trayItems *traylist.List[*connlist.Item], list *connlist.Collection, rules *netrules.Rules) func() bool {
+	var mu sync.Mutex
+	var last *netid.Identity
+
+	return func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+
+		id := netid.Current()
+		if last != nil && *last == id {
+			return false // Same network, do not override user choice.
+		}
+		last = &id
+
+		rule, ok := rules.Match(id)
+		if !ok {
+			return false
+		}
+		slog.Info("network rule matched", "field", rule.Field, "value", rule.Value, "action", rule.Action)
+
+		return applyAction(trayItems, list, rule.Action == netrules.ActionConnect, rule.ItemID)
+	}
+}
+
+// ScheduleH applies triggers of scheduled rules.
+func ScheduleH(trayItems *traylist.List[*connlist.Item], list *connlist.Collection) func(t schedule.Trigger) {
+	return func(t schedule.Trigger) {
+		slog.Info("scheduled rule triggered", "action", t.Action, "at", t.At)
+		applyAction(trayItems, list, t.Action == schedule.ActionConnect, t.ItemID)
+	}
+}
+
+// applyAction connects item with itemID, or disconnects the active item (only if it is itemID when set).
+// It clicks the tray item, so it goes through ConnectHandler and the tray state is updated. The tray serializes
+// it with user clicks and other actions, and it is a no-op if the item is already in the wanted state.
+// Returns true if the connection was changed.
+func applyAction(trayItems *traylist.List[*connlist.Item], list *connlist.Collection, connect bool, itemID string) bool {
+	var target *connlist.Item
+	if connect || itemID != "" {
+		target = list.ByID(itemID)
+	} else {
+		target = trayItems.GetActive()
+	}
+	if target == nil {
+		if connect {
+			slog.Warn("connection of the rule not found", "id", itemID)
+		}
+		return false
+	}
+
+	changed, err := trayItems.SetActive(target, connect)
+	if err != nil {
+		slog.Error("apply rule", "err", err)
+	}
+
+	return changed
+}
+
+// QuotaH notifies when the item reaches a new level of its quota and disconnects it on every sample while
+// the quota is exceeded and requires so. Returns true if a new level was reached.
+func QuotaH(
+	a fyne.App,
+	trayItems *traylist.List[*connlist.Item],
+	list *connlist.Collection,
+	quotas *quota.Quotas,
+	usageStore *usage.Store,
+) func(item *connlist.Item) bool {
+	return func(item *connlist.Item) bool {
+		now := time.Now()
+		used, ok := cycleUsage(quotas, usageStore, item.ID(), now)
+		if !ok {
+			return false
+		}
+		if quotas.Blocks(item.ID(), used) {
+			// Called by the traffic sampler, disconnect in background so other connections are sampled meanwhile.
+			// It is a no-op if the item is already disconnected.
+			go applyAction(trayItems, list, false, item.ID())
+		}
+
+		alert, ok := quotas.Check(item.ID(), used, now)
+		if !ok {
+			return false
+		}
+		slog.Info("quota level reached", "item", item.Label(), "level", alert.Level, "used", alert.Used)
+
+		msg := lang.L("Quota of {{.Label}} is {{.Level}}% used", map[string]any{"Label": item.Label(), "Level": alert.Level})
+		if alert.Level == quota.Exceeded {
+			msg = lang.L("Quota of {{.Label}} is exceeded", map[string]any{"Label": item.Label()})
+		}
+		a.SendNotification(fyne.NewNotification(lang.L(AppTitleName), msg))
+
+		return true
+	}
+}
+
+// NetworkChangeH checks the active connection after network changes and reconnects it through the tray,
+// so the tray shows a warning and the item is deactivated if the reconnect fails.
+func NetworkChangeH(a fyne.App, trayItems *traylist.List[*connlist.Item]) func(ev netmon.Event) {
+	return func(ev netmon.Event) {
+		active := trayItems.GetActive()
+		if active == nil {
+			return
+		}
+
+		// Connection may survive network changes, but never survives suspend.
+		if ev.Kind != netmon.KindResume {
+			ctx, cancel := context.WithTimeout(context.Background(), healthCheckTimeout)
+			defer cancel()
+			err := active.HealthCheck(ctx)
+			if err == nil {
+				return
+			}
+			slog.Info("active connection is not healthy after network change", "event", ev.Kind, "err", err)
+		}
+
+		slog.Info("reconnecting active connection", "label", active.Label(), "event", ev.Kind)
+		err := trayItems.Reconnect(active, (*connlist.Item).Reconnect)
+		if errors.Is(err, traylist.ErrNotActive) || errors.Is(err, traylist.ErrItemNotFound) {
+			return // Disconnected or deleted meanwhile.
+		}
+		if err != nil {
+			slog.Error("reconnect failed", "label", active.Label(), "err", err)
+			a.SendNotification(fyne.NewNotification(lang.L(AppTitleName),
+				lang.L("Reconnecting {{.Label}} failed: {{.Error}}", map[string]any{"Label": active.Label(), "Error": err})))
+		}
+	}
+}
+
 func swapItems(list binding.ExternalUntypedList, item1 *connlist.Item, item2 *connlist.Item) error {
 	listVals, _ := list.Get()
 	id1, id2 := -1, -1
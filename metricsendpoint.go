@@ -0,0 +1,56 @@
+package main
+
+import (
+	"github.com/goxray/desktop/internal/connlist"
+	"github.com/goxray/desktop/internal/metrics"
+)
+
+// metricsAddr is the address of the opt-in metrics endpoint, it is only reachable from this machine.
+const metricsAddr = "127.0.0.1:9477"
+
+// MetricsSettings exposes the metrics endpoint toggle to the settings window.
+type MetricsSettings struct {
+	server *metrics.Server
+	save   *SaveFile
+}
+
+func NewMetricsSettings(server *metrics.Server, save *SaveFile) *MetricsSettings {
+	return &MetricsSettings{server: server, save: save}
+}
+
+func (m *MetricsSettings) MetricsURL() string {
+	return "http://" + metricsAddr + metrics.Path
+}
+
+func (m *MetricsSettings) MetricsEnabled() bool {
+	return m.server.Running()
+}
+
+func (m *MetricsSettings) SetMetricsEnabled(enabled bool) error {
+	var err error
+	if enabled {
+		err = m.server.Start(metricsAddr)
+	} else {
+		err = m.server.Stop()
+	}
+	if err != nil {
+		return err
+	}
+	m.save.UpdateMetricsEnabled(enabled)
+
+	return nil
+}
+
+// metricsItems returns a snapshot of the list items for metrics collection, it is called by the HTTP server
+// and relies on the collection being safe for concurrent reads.
+func metricsItems(list *connlist.Collection) func() []metrics.Item {
+	return func() []metrics.Item {
+		all := list.All()
+		res := make([]metrics.Item, 0, len(all))
+		for _, item := range all {
+			res = append(res, item)
+		}
+
+		return res
+	}
+}
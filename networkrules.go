@@ -0,0 +1,54 @@
+package main
+
+import (
+	"github.com/goxray/desktop/internal/netrules"
+	"github.com/goxray/desktop/internal/osspecific/netid"
+	"github.com/goxray/desktop/window"
+)
+
+// NetworkRulesSettings exposes network rules to the settings window.
+type NetworkRulesSettings struct {
+	rules *netrules.Rules
+}
+
+func NewNetworkRulesSettings(rules *netrules.Rules) *NetworkRulesSettings {
+	return &NetworkRulesSettings{rules: rules}
+}
+
+func (n *NetworkRulesSettings) CurrentNetwork() map[string]string {
+	id := netid.Current()
+	res := make(map[string]string, len(netrules.Fields()))
+	for _, f := range netrules.Fields() {
+		res[string(f)] = f.Value(id)
+	}
+
+	return res
+}
+
+func (n *NetworkRulesSettings) NetworkRules() []window.NetworkRule {
+	all := n.rules.All()
+	res := make([]window.NetworkRule, 0, len(all))
+	for _, r := range all {
+		res = append(res, window.NetworkRule{
+			Field:  string(r.Field),
+			Value:  r.Value,
+			Action: string(r.Action),
+			ItemID: r.ItemID,
+		})
+	}
+
+	return res
+}
+
+func (n *NetworkRulesSettings) AddNetworkRule(rule window.NetworkRule) error {
+	return n.rules.Add(netrules.Rule{
+		Field:  netrules.Field(rule.Field),
+		Value:  rule.Value,
+		Action: netrules.Action(rule.Action),
+		ItemID: rule.ItemID,
+	})
+}
+
+func (n *NetworkRulesSettings) RemoveNetworkRule(i int) error {
+	return n.rules.Remove(i)
+}
@@ -0,0 +1,75 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/goxray/desktop/internal/connlist"
+	"github.com/goxray/desktop/internal/quota"
+	"github.com/goxray/desktop/internal/usage"
+	"github.com/goxray/desktop/window"
+)
+
+var errQuotaExceeded = errors.New("quota is exceeded")
+
+// QuotaSettings exposes quotas and their usage to the settings window.
+type QuotaSettings struct {
+	quotas *quota.Quotas
+	usage  *usage.Store
+}
+
+func NewQuotaSettings(quotas *quota.Quotas, usage *usage.Store) *QuotaSettings {
+	return &QuotaSettings{quotas: quotas, usage: usage}
+}
+
+func (q *QuotaSettings) Quota(itemID string) (window.Quota, bool) {
+	res, ok := q.quotas.Get(itemID)
+
+	return window.Quota{
+		Limit:          res.Limit,
+		ResetDay:       res.ResetDay,
+		Thresholds:     res.Thresholds,
+		AutoDisconnect: res.AutoDisconnect,
+	}, ok
+}
+
+func (q *QuotaSettings) SetQuota(itemID string, set window.Quota) error {
+	return q.quotas.Set(itemID, quota.Quota{
+		Limit:          set.Limit,
+		ResetDay:       set.ResetDay,
+		Thresholds:     set.Thresholds,
+		AutoDisconnect: set.AutoDisconnect,
+	})
+}
+
+func (q *QuotaSettings) RemoveQuota(itemID string) {
+	q.quotas.Remove(itemID)
+}
+
+func (q *QuotaSettings) QuotaUsage(itemID string) (used int64, ok bool) {
+	return cycleUsage(q.quotas, q.usage, itemID, time.Now())
+}
+
+// cycleUsage returns bytes used by the item in its current billing cycle, ok is false if the item has no quota.
+func cycleUsage(quotas *quota.Quotas, usageStore *usage.Store, itemID string, now time.Time) (used int64, ok bool) {
+	q, ok := quotas.Get(itemID)
+	if !ok {
+		return 0, false
+	}
+	read, written := usageStore.Range(itemID, q.CycleStart(now), now)
+
+	return read + written, true
+}
+
+// QuotaGuard refuses to connect items whose quota is exceeded and disconnects automatically.
+func QuotaGuard(quotas *quota.Quotas, usageStore *usage.Store) func(item *connlist.Item) error {
+	return func(item *connlist.Item) error {
+		used, ok := cycleUsage(quotas, usageStore, item.ID(), time.Now())
+		if ok && quotas.Blocks(item.ID(), used) {
+			return fmt.Errorf("%w: %q", errQuotaExceeded, item.Label())
+		}
+
+		return nil
+	}
+}
@@ -3,12 +3,30 @@ package main
 import (
 	"encoding/json"
 	"log/slog"
+	"strconv"
+	"time"
 
 	"github.com/goxray/desktop/internal/connlist"
+	"github.com/goxray/desktop/internal/netrules"
+	"github.com/goxray/desktop/internal/quota"
+	"github.com/goxray/desktop/internal/schedule"
+	"github.com/goxray/desktop/internal/subscription"
+	"github.com/goxray/desktop/internal/trash"
+	"github.com/goxray/desktop/internal/traystatus"
 )
 
 const (
-	itemsConfigKey = "connections_config"
+	itemsConfigKey        = "connections_config"
+	networkRulesConfigKey = "network_rules_config"
+	scheduleConfigKey     = "schedule_config"
+	quotaConfigKey        = "quota_config"
+	quotaReportedKey      = "quota_reported"
+	subscriptionsKey      = "subscriptions_config"
+	metricsEnabledKey     = "metrics_enabled"
+	trayStatusIntervalKey = "tray_status_interval"
+	trayIconGraphKey      = "tray_icon_graph"
+	trashConfigKey        = "trash_config"
+	trashExpiryKey        = "trash_expiry"
 )
 
 // SaveFile is used to store and load connection items from memory.
@@ -24,14 +42,27 @@ type Source interface {
 }
 
 type SavedState struct {
-	Link  string `json:"link"`
-	Label string `json:"label"`
+	ID    string   `json:"id"`
+	Link  string   `json:"link"`
+	Label string   `json:"label"`
+	Chain []string `json:"chain,omitempty"` // IDs of chained items in dial order.
+	// IDs of balanced items and the balancer strategy.
+	Members  []string `json:"members,omitempty"`
+	Strategy string   `json:"strategy,omitempty"`
+	// Previous versions, the newest first.
+	History []connlist.Version `json:"history,omitempty"`
 }
 
 func serialize(item *connlist.Item) SavedState {
 	return SavedState{
+		ID:    item.ID(),
 		Link:  item.Link(),
 		Label: item.Label(),
+		Chain: item.HopIDs(),
+
+		Members:  item.MemberIDs(),
+		Strategy: item.Strategy(),
+		History:  item.History(),
 	}
 }
 
@@ -67,8 +98,186 @@ func (s *SaveFile) Load(list *connlist.Collection) {
 	}
 
 	for _, item := range loadedItems {
-		if err := list.AddItem(item.Label, item.Link); err != nil {
+		if err := deserialize(list, item); err != nil {
 			slog.Error("failed to load new item", "error", err)
 		}
 	}
 }
+
+// deserialize loads the saved item into list.
+func deserialize(list *connlist.Collection, item SavedState) error {
+	// Items saved before IDs were introduced get a new one.
+	if len(item.Members) > 0 {
+		_, err := list.LoadBalancer(item.ID, item.Label, item.Members, item.Strategy, item.History)
+		return err
+	}
+	_, err := list.LoadItem(item.ID, item.Label, item.Link, item.Chain, item.History)
+
+	return err
+}
+
+// UpdateNetworkRules saves network rules into config.
+func (s *SaveFile) UpdateNetworkRules(rules *netrules.Rules) {
+	b, err := json.MarshalIndent(rules.All(), "", "  ")
+	if err != nil {
+		slog.Warn(err.Error())
+	}
+
+	s.source.SetString(networkRulesConfigKey, string(b))
+}
+
+// LoadNetworkRules loads saved network rules.
+func (s *SaveFile) LoadNetworkRules(rules *netrules.Rules) {
+	loaded := make([]netrules.Rule, 0)
+	if err := json.Unmarshal([]byte(s.source.StringWithFallback(networkRulesConfigKey, "[]")), &loaded); err != nil {
+		slog.Error("failed to unmarshal network rules", "error", err)
+	}
+
+	rules.Load(loaded)
+}
+
+// UpdateSchedule saves schedule rules into config.
+func (s *SaveFile) UpdateSchedule(rules *schedule.Rules) {
+	b, err := json.MarshalIndent(rules.All(), "", "  ")
+	if err != nil {
+		slog.Warn(err.Error())
+	}
+
+	s.source.SetString(scheduleConfigKey, string(b))
+}
+
+// LoadSchedule loads saved schedule rules.
+func (s *SaveFile) LoadSchedule(rules *schedule.Rules) {
+	loaded := make([]schedule.Rule, 0)
+	if err := json.Unmarshal([]byte(s.source.StringWithFallback(scheduleConfigKey, "[]")), &loaded); err != nil {
+		slog.Error("failed to unmarshal schedule rules", "error", err)
+	}
+
+	rules.Load(loaded)
+}
+
+// UpdateQuotas saves quotas and their reported levels into config.
+func (s *SaveFile) UpdateQuotas(quotas *quota.Quotas) {
+	b, err := json.MarshalIndent(quotas.All(), "", "  ")
+	if err != nil {
+		slog.Warn(err.Error())
+	}
+	s.source.SetString(quotaConfigKey, string(b))
+
+	b, err = json.MarshalIndent(quotas.AllReported(), "", "  ")
+	if err != nil {
+		slog.Warn(err.Error())
+	}
+	s.source.SetString(quotaReportedKey, string(b))
+}
+
+// LoadQuotas loads saved quotas.
+func (s *SaveFile) LoadQuotas(quotas *quota.Quotas) {
+	loaded := make(map[string]quota.Quota)
+	if err := json.Unmarshal([]byte(s.source.StringWithFallback(quotaConfigKey, "{}")), &loaded); err != nil {
+		slog.Error("failed to unmarshal quotas", "error", err)
+	}
+	reported := make(map[string]quota.Reported)
+	if err := json.Unmarshal([]byte(s.source.StringWithFallback(quotaReportedKey, "{}")), &reported); err != nil {
+		slog.Error("failed to unmarshal reported quota levels", "error", err)
+	}
+
+	quotas.Load(loaded)
+	quotas.LoadReported(reported)
+}
+
+// UpdateSubscriptions saves subscriptions into config.
+func (s *SaveFile) UpdateSubscriptions(subs *subscription.Subscriptions) {
+	b, err := json.MarshalIndent(subs.All(), "", "  ")
+	if err != nil {
+		slog.Warn(err.Error())
+	}
+
+	s.source.SetString(subscriptionsKey, string(b))
+}
+
+// LoadSubscriptions loads saved subscriptions.
+func (s *SaveFile) LoadSubscriptions(subs *subscription.Subscriptions) {
+	loaded := make([]subscription.Subscription, 0)
+	if err := json.Unmarshal([]byte(s.source.StringWithFallback(subscriptionsKey, "[]")), &loaded); err != nil {
+		slog.Error("failed to unmarshal subscriptions", "error", err)
+	}
+
+	subs.Load(loaded)
+}
+
+// UpdateMetricsEnabled saves whether the metrics endpoint is enabled.
+func (s *SaveFile) UpdateMetricsEnabled(enabled bool) {
+	s.source.SetString(metricsEnabledKey, strconv.FormatBool(enabled))
+}
+
+// LoadMetricsEnabled loads whether the metrics endpoint is enabled, it is disabled by default.
+func (s *SaveFile) LoadMetricsEnabled() bool {
+	enabled, _ := strconv.ParseBool(s.source.StringWithFallback(metricsEnabledKey, "false"))
+
+	return enabled
+}
+
+// UpdateTrayStatusInterval saves how often the tray status is updated.
+func (s *SaveFile) UpdateTrayStatusInterval(d time.Duration) {
+	s.source.SetString(trayStatusIntervalKey, d.String())
+}
+
+// LoadTrayStatusInterval loads how often the tray status is updated, traystatus.DefaultInterval by default.
+func (s *SaveFile) LoadTrayStatusInterval() time.Duration {
+	d, err := time.ParseDuration(s.source.StringWithFallback(trayStatusIntervalKey, traystatus.DefaultInterval.String()))
+	if err != nil {
+		slog.Error("failed to parse tray status interval", "error", err)
+		return traystatus.DefaultInterval
+	}
+
+	return d
+}
+
+// UpdateTrayIconGraph saves whether the tray icon shows a graph of the active connection speed.
+func (s *SaveFile) UpdateTrayIconGraph(enabled bool) {
+	s.source.SetString(trayIconGraphKey, strconv.FormatBool(enabled))
+}
+
+// LoadTrayIconGraph loads whether the tray icon shows a graph of the active connection speed, it is disabled by default.
+func (s *SaveFile) LoadTrayIconGraph() bool {
+	enabled, _ := strconv.ParseBool(s.source.StringWithFallback(trayIconGraphKey, "false"))
+
+	return enabled
+}
+
+// UpdateTrash saves deleted items into config.
+func (s *SaveFile) UpdateTrash(bin *trash.Bin[SavedState]) {
+	b, err := json.MarshalIndent(bin.All(), "", "  ")
+	if err != nil {
+		slog.Warn(err.Error())
+	}
+
+	s.source.SetString(trashConfigKey, string(b))
+}
+
+// LoadTrash loads saved deleted items.
+func (s *SaveFile) LoadTrash(bin *trash.Bin[SavedState]) {
+	loaded := make([]trash.Entry[SavedState], 0)
+	if err := json.Unmarshal([]byte(s.source.StringWithFallback(trashConfigKey, "[]")), &loaded); err != nil {
+		slog.Error("failed to unmarshal trash", "error", err)
+	}
+
+	bin.Load(loaded)
+}
+
+// UpdateTrashExpiry saves how long deleted items are kept.
+func (s *SaveFile) UpdateTrashExpiry(d time.Duration) {
+	s.source.SetString(trashExpiryKey, d.String())
+}
+
+// LoadTrashExpiry loads how long deleted items are kept, trash.DefaultMaxAge by default.
+func (s *SaveFile) LoadTrashExpiry() time.Duration {
+	d, err := time.ParseDuration(s.source.StringWithFallback(trashExpiryKey, trash.DefaultMaxAge.String()))
+	if err != nil {
+		slog.Error("failed to parse trash expiry", "error", err)
+		return trash.DefaultMaxAge
+	}
+
+	return d
+}
@@ -0,0 +1,82 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/goxray/desktop/internal/connlist"
+	"github.com/goxray/desktop/internal/quota"
+)
+
+const testLink = "vless://b831381d-6324-4d53-ad4f-8cda48b30811@127.0.0.1:8080?type=tcp&security=tls&fp=chrome#Remark"
+
+type mapSource map[string]string
+
+func (m mapSource) SetString(key, value string) {
+	m[key] = value
+}
+
+func (m mapSource) StringWithFallback(key, fallback string) string {
+	if v, ok := m[key]; ok {
+		return v
+	}
+
+	return fallback
+}
+
+func TestSaveFile_LoadLegacy(t *testing.T) {
+	// Savefiles written before IDs and history were introduced only have link and label.
+	source := mapSource{itemsConfigKey: `[{"link":"` + testLink + `","label":"Legacy"}]`}
+	list := connlist.New()
+	NewSaveFile(source).Load(list)
+
+	require.Len(t, list.All(), 1)
+	item := list.All()[0]
+	require.NotEmpty(t, item.ID())
+	require.Equal(t, "Legacy", item.Label())
+	require.Equal(t, testLink, item.Link())
+	require.Empty(t, item.History())
+}
+
+func TestSaveFile_History(t *testing.T) {
+	source := mapSource{}
+	list := connlist.New()
+	require.NoError(t, list.AddItem("A", testLink))
+	require.NoError(t, list.All()[0].Update(testLink, "B"))
+	NewSaveFile(source).Update(list)
+
+	// Loaded items are saved as they are added, like in the app.
+	saveFile := NewSaveFile(source)
+	loaded := connlist.New()
+	loaded.OnAdd(func(*connlist.Item) {})
+	loaded.OnChange(func() { saveFile.Update(loaded) })
+	saveFile.Load(loaded)
+	require.Len(t, loaded.All(), 1)
+	require.Equal(t, list.All()[0].ID(), loaded.All()[0].ID())
+	require.Equal(t, "B", loaded.All()[0].Label())
+	require.Len(t, loaded.All()[0].History(), 1)
+	require.Equal(t, "A", loaded.All()[0].History()[0].Label)
+
+	reloaded := connlist.New()
+	saveFile.Load(reloaded)
+	require.Len(t, reloaded.All(), 1)
+	require.Equal(t, loaded.All()[0].History(), reloaded.All()[0].History())
+}
+
+func TestSaveFile_QuotaReported(t *testing.T) {
+	source := mapSource{}
+	quotas := quota.New()
+	require.NoError(t, quotas.Set("a", quota.Quota{Limit: 1000, ResetDay: 1}))
+	now := time.Date(2024, 1, 2, 0, 0, 0, 0, time.Local)
+	_, ok := quotas.Check("a", 1000, now)
+	require.True(t, ok)
+	NewSaveFile(source).UpdateQuotas(quotas)
+
+	// Levels reported before restart are not reported again.
+	loaded := quota.New()
+	NewSaveFile(source).LoadQuotas(loaded)
+	_, ok = loaded.Check("a", 1000, now)
+	require.False(t, ok)
+}
@@ -0,0 +1,61 @@
+package main
+
+import (
+	"github.com/goxray/desktop/internal/schedule"
+	"github.com/goxray/desktop/window"
+)
+
+// ScheduleSettings exposes schedule rules to the settings window.
+type ScheduleSettings struct {
+	rules *schedule.Rules
+}
+
+func NewScheduleSettings(rules *schedule.Rules) *ScheduleSettings {
+	return &ScheduleSettings{rules: rules}
+}
+
+func (s *ScheduleSettings) ScheduleRules() []window.ScheduleRule {
+	all := s.rules.All()
+	res := make([]window.ScheduleRule, 0, len(all))
+	for _, r := range all {
+		rule := window.ScheduleRule{
+			Days:   r.Days,
+			Start:  r.Start.String(),
+			Action: string(r.Action),
+			ItemID: r.ItemID,
+		}
+		if r.End != nil {
+			rule.End = r.End.String()
+		}
+		res = append(res, rule)
+	}
+
+	return res
+}
+
+func (s *ScheduleSettings) AddScheduleRule(rule window.ScheduleRule) error {
+	start, err := schedule.ParseTimeOfDay(rule.Start)
+	if err != nil {
+		return err
+	}
+
+	r := schedule.Rule{
+		Days:   rule.Days,
+		Start:  start,
+		Action: schedule.Action(rule.Action),
+		ItemID: rule.ItemID,
+	}
+	if rule.End != "" {
+		end, err := schedule.ParseTimeOfDay(rule.End)
+		if err != nil {
+			return err
+		}
+		r.End = &end
+	}
+
+	return s.rules.Add(r)
+}
+
+func (s *ScheduleSettings) RemoveScheduleRule(i int) error {
+	return s.rules.Remove(i)
+}
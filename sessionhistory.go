@@ -0,0 +1,55 @@
+package main
+
+import (
+	"io"
+	"log/slog"
+	"path/filepath"
+
+	"fyne.io/fyne/v2"
+
+	"github.com/goxray/desktop/internal/sessions"
+	"github.com/goxray/desktop/window"
+)
+
+// SessionSettings exposes the session history and its export to the settings window.
+type SessionSettings struct {
+	history *sessions.History
+}
+
+func NewSessionSettings(history *sessions.History) *SessionSettings {
+	return &SessionSettings{history: history}
+}
+
+func (s *SessionSettings) Sessions() []window.Session {
+	all := s.history.All()
+	res := make([]window.Session, 0, len(all))
+	for _, session := range all {
+		res = append(res, window.Session{
+			Label:   session.Label,
+			Start:   session.Start,
+			End:     session.End,
+			Read:    session.Read,
+			Written: session.Written,
+			Reason:  string(session.Reason),
+			Err:     session.Err,
+		})
+	}
+
+	return res
+}
+
+func (s *SessionSettings) ExportSessions(w io.Writer, format string) error {
+	return sessions.Write(w, sessions.Format(format), s.history.All())
+}
+
+// openSessions loads the session history from the app storage, starting over if the file is broken.
+func openSessions(a fyne.App) *sessions.History {
+	path := filepath.Join(a.Storage().RootURI().Path(), sessionsFileName)
+	history, err := sessions.Open(path, sessionsLimit)
+	if err != nil {
+		slog.Error("failed to load session history, starting over", "error", err)
+		return sessions.New(path, sessionsLimit)
+	}
+
+	return history
+}
@@ -0,0 +1,321 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/lang"
+
+	"github.com/goxray/desktop/internal/connlist"
+	"github.com/goxray/desktop/internal/sharelink"
+	"github.com/goxray/desktop/internal/subscription"
+	"github.com/goxray/desktop/window"
+	customwidget "github.com/goxray/desktop/window/widget"
+)
+
+const (
+	// subscriptionRefreshInterval is how often subscriptions are downloaded again.
+	subscriptionRefreshInterval = 12 * time.Hour
+	// subscriptionCheckInterval is how often subscriptions are checked for expiry and due refreshes.
+	subscriptionCheckInterval = time.Hour
+	subscriptionFetchTimeout  = 30 * time.Second
+	// subscriptionExpiryWarning is how long before expiry the user is notified.
+	subscriptionExpiryWarning = 3 * 24 * time.Hour
+)
+
+// SubscriptionSettings imports items from subscriptions and exposes them to the settings window.
+type SubscriptionSettings struct {
+	subs       *subscription.Subscriptions
+	list       *connlist.Collection
+	deleteItem func(*connlist.Item) error
+	client     *http.Client
+	mu         sync.Mutex // Serializes imports, so items are not imported twice.
+}
+
+// NewSubscriptionSettings creates settings, deleteItem is used to remove items the provider no longer sends.
+func NewSubscriptionSettings(
+	subs *subscription.Subscriptions,
+	list *connlist.Collection,
+	deleteItem func(*connlist.Item) error,
+) *SubscriptionSettings {
+	return &SubscriptionSettings{
+		subs:       subs,
+		list:       list,
+		deleteItem: deleteItem,
+		client:     &http.Client{Timeout: subscriptionFetchTimeout},
+	}
+}
+
+func (s *SubscriptionSettings) Subscriptions() []window.Subscription {
+	all := s.subs.All()
+	res := make([]window.Subscription, 0, len(all))
+	for _, sub := range all {
+		res = append(res, toWindowSubscription(sub, time.Now()))
+	}
+
+	return res
+}
+
+// AddSubscription adds the subscription and imports it, the subscription is removed if it can not be downloaded.
+func (s *SubscriptionSettings) AddSubscription(name, url string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	sub, err := s.subs.Add(name, url)
+	if err != nil {
+		return err
+	}
+	if err := s.refresh(sub); err != nil {
+		_ = s.subs.Remove(sub.ID)
+		return err
+	}
+
+	return nil
+}
+
+func (s *SubscriptionSettings) RefreshSubscription(id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	sub, ok := s.subs.Get(id)
+	if !ok {
+		return fmt.Errorf("%w: %s", subscription.ErrNotFound, id)
+	}
+
+	return s.refresh(sub)
+}
+
+func (s *SubscriptionSettings) RemoveSubscription(id string) error {
+	return s.subs.Remove(id)
+}
+
+func (s *SubscriptionSettings) ItemSubscription(itemID string) (window.Subscription, bool) {
+	sub, ok := s.subs.ByItem(itemID)
+	if !ok {
+		return window.Subscription{}, false
+	}
+
+	return toWindowSubscription(sub, time.Now()), true
+}
+
+// refresh downloads the subscription and imports its links, it must be called with mu held.
+func (s *SubscriptionSettings) refresh(sub subscription.Subscription) error {
+	ctx, cancel := context.WithTimeout(context.Background(), subscriptionFetchTimeout)
+	defer cancel()
+	content, err := subscription.Fetch(ctx, s.client, sub.URL)
+	if err != nil {
+		return fmt.Errorf("refresh %q: %w", sub.Name, err)
+	}
+
+	return s.subs.Update(sub.ID, content, s.importLinks(sub, content.Links), time.Now())
+}
+
+// importLinks adds new links of the subscription and moves items of links the provider no longer sends
+// to the trash, returns IDs of the subscription items. Items that can not be moved, e.g. the active one, are kept.
+//
+// Items are matched by the canonical form of their links, so reformatted links keep their items. Items of links
+// the provider edited are matched by remark and updated, so their history, usage and quotas are kept.
+func (s *SubscriptionSettings) importLinks(sub subscription.Subscription, links []string) []string {
+	var existing []subscriptionItem // Not matched to links yet.
+	for _, id := range sub.ItemIDs {
+		if item := s.list.ByID(id); item != nil {
+			existing = append(existing, subscriptionItem{item, parseSubscriptionLink(item.Link())})
+		}
+	}
+	match := func(matches func(subscriptionLink) bool) *connlist.Item {
+		i := slices.IndexFunc(existing, func(e subscriptionItem) bool { return matches(e.link) })
+		if i < 0 {
+			return nil
+		}
+		item := existing[i].item
+		existing = slices.Delete(existing, i, i+1)
+
+		return item
+	}
+
+	parsed := make([]subscriptionLink, 0, len(links))
+	for _, link := range links {
+		l := parseSubscriptionLink(link)
+		if !slices.ContainsFunc(parsed, func(p subscriptionLink) bool { return p.canonical == l.canonical }) {
+			parsed = append(parsed, l)
+		}
+	}
+	// Unchanged links are matched first, so edited links do not take items of other links with the same remark.
+	items := make([]*connlist.Item, len(parsed))
+	for i, l := range parsed {
+		items[i] = match(func(e subscriptionLink) bool { return e.canonical == l.canonical })
+	}
+
+	ids := make([]string, 0, len(parsed))
+	for i, l := range parsed {
+		if items[i] == nil && l.remark != "" {
+			if item := match(func(e subscriptionLink) bool { return e.remark == l.remark }); item != nil {
+				if err := item.Update(l.raw, item.Label()); err != nil {
+					slog.Warn("keep outdated subscription item", "subscription", sub.Name, "item", item.Label(), "err", err)
+				}
+				items[i] = item
+			}
+		}
+		if items[i] == nil {
+			item, err := s.list.LoadItem("", subscriptionItemLabel(sub, l.remark, i), l.raw, nil, nil)
+			if err != nil {
+				slog.Warn("skip subscription link", "subscription", sub.Name, "err", err)
+				continue
+			}
+			items[i] = item
+		}
+		ids = append(ids, items[i].ID())
+	}
+
+	for _, e := range existing {
+		if e.item.Active() {
+			ids = append(ids, e.item.ID())
+			continue
+		}
+		if err := s.deleteItem(e.item); err != nil {
+			slog.Warn("keep removed subscription item", "subscription", sub.Name, "item", e.item.Label(), "err", err)
+			ids = append(ids, e.item.ID())
+		}
+	}
+
+	return ids
+}
+
+// subscriptionLink is a link of a subscription with keys its item is matched by on updates.
+type subscriptionLink struct {
+	raw       string
+	canonical string // The raw link if it can not be parsed.
+	remark    string
+}
+
+func parseSubscriptionLink(link string) subscriptionLink {
+	res := subscriptionLink{raw: link, canonical: link}
+	if config, err := sharelink.Parse(link); err == nil {
+		res.remark = strings.TrimSpace(config.Remark)
+		if canonical, err := config.Link(); err == nil {
+			res.canonical = canonical
+		}
+	}
+
+	return res
+}
+
+type subscriptionItem struct {
+	item *connlist.Item
+	link subscriptionLink
+}
+
+// subscriptionItemLabel returns remark of the link, or the subscription name with the link number.
+func subscriptionItemLabel(sub subscription.Subscription, remark string, i int) string {
+	if remark != "" {
+		return remark
+	}
+
+	return fmt.Sprintf("%s %d", sub.Name, i+1)
+}
+
+func toWindowSubscription(sub subscription.Subscription, now time.Time) window.Subscription {
+	res := window.Subscription{
+		ID:        sub.ID,
+		Name:      sub.Name,
+		URL:       sub.URL,
+		HasInfo:   sub.HasInfo,
+		UpdatedAt: sub.UpdatedAt,
+		Items:     len(sub.ItemIDs),
+	}
+	if sub.HasInfo {
+		res.Used, res.Total, res.Expire = sub.Info.Used(), sub.Info.Total, sub.Info.Expire
+		res.Expired, res.Exhausted = sub.Info.Expired(now), sub.Info.Exhausted()
+	}
+
+	return res
+}
+
+// RefreshSubscriptions downloads subscriptions every subscriptionRefreshInterval and notifies about their expiry
+// and exhausted traffic till ctx is done.
+func RefreshSubscriptions(ctx context.Context, a fyne.App, settings *SubscriptionSettings, subs *subscription.Subscriptions) {
+	for {
+		now := time.Now()
+		for _, sub := range subs.All() {
+			if now.Sub(sub.UpdatedAt) >= subscriptionRefreshInterval {
+				if err := settings.RefreshSubscription(sub.ID); err != nil {
+					slog.Warn("failed to refresh subscription", "err", err)
+				}
+			}
+			notifySubscription(a, subs, sub.ID, time.Now())
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(subscriptionCheckInterval):
+		}
+	}
+}
+
+// notifySubscription sends a notification once the subscription is about to expire, expired or exhausted.
+func notifySubscription(a fyne.App, subs *subscription.Subscriptions, id string, now time.Time) {
+	state, ok := subs.Notify(id, now, subscriptionExpiryWarning)
+	if !ok {
+		return
+	}
+	sub, ok := subs.Get(id)
+	if !ok {
+		return
+	}
+	slog.Info("subscription state changed", "subscription", sub.Name, "state", state)
+
+	var msg string
+	switch state {
+	case subscription.StateExpiring:
+		msg = lang.L("Subscription {{.Name}} expires {{.Time}}", map[string]any{
+			"Name": sub.Name, "Time": sub.Info.Expire.Local().Format(time.DateTime)})
+	case subscription.StateExhausted:
+		msg = lang.L("Traffic of subscription {{.Name}} is exhausted", map[string]any{"Name": sub.Name})
+	case subscription.StateExpired:
+		msg = lang.L("Subscription {{.Name}} is expired", map[string]any{"Name": sub.Name})
+	default:
+		return
+	}
+	a.SendNotification(fyne.NewNotification(lang.L(AppTitleName), msg))
+}
+
+// subscriptionTrayLabel marks items of expired and exhausted subscriptions in the tray menu
+// and shows usage and expiry of the others.
+func subscriptionTrayLabel(subs *subscription.Subscriptions) func(item *connlist.Item) string {
+	return func(item *connlist.Item) string {
+		sub, ok := subs.ByItem(item.ID())
+		if !ok || !sub.HasInfo {
+			return item.Label()
+		}
+
+		now := time.Now()
+		switch {
+		case sub.Info.Expired(now):
+			return lang.L("{{.Label}} (expired)", map[string]any{"Label": item.Label()})
+		case sub.Info.Exhausted():
+			return lang.L("{{.Label}} (exhausted)", map[string]any{"Label": item.Label()})
+		}
+
+		var info []string
+		if sub.Info.Total > 0 {
+			info = append(info, lang.L("{{.Used}} of {{.Limit}}", map[string]any{
+				"Used": customwidget.FormatBytes(sub.Info.Used()), "Limit": customwidget.FormatBytes(sub.Info.Total)}))
+		}
+		if !sub.Info.Expire.IsZero() {
+			info = append(info, lang.L("until {{.Date}}", map[string]any{"Date": sub.Info.Expire.Local().Format(time.DateOnly)}))
+		}
+		if len(info) == 0 {
+			return item.Label()
+		}
+
+		return fmt.Sprintf("%s (%s)", item.Label(), strings.Join(info, ", "))
+	}
+}
@@ -0,0 +1,73 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/goxray/desktop/internal/connlist"
+	"github.com/goxray/desktop/internal/subscription"
+	"github.com/goxray/desktop/internal/trash"
+)
+
+func TestSubscriptionSettings_Refresh(t *testing.T) {
+	const (
+		link1 = "vless://b831381d-6324-4d53-ad4f-8cda48b30811@127.0.0.1:8080?type=tcp&security=tls&fp=chrome#Remark"
+		link2 = "vless://b831381d-6324-4d53-ad4f-8cda48b30811@127.0.0.2:8080?type=tcp&security=tls&fp=chrome#Second"
+	)
+	body := link1 + "\n" + link2
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/missing" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set(subscription.UserInfoHeader, "upload=1; download=2; total=3")
+		_, _ = w.Write([]byte(body))
+	}))
+	defer srv.Close()
+
+	list := connlist.New()
+	bin := trash.New[SavedState]()
+	subs := subscription.New()
+	settings := NewSubscriptionSettings(subs, list, DeleteItemH(list, bin))
+
+	require.NoError(t, settings.AddSubscription("Provider", srv.URL))
+	require.Len(t, list.All(), 2)
+	require.Equal(t, "Remark", list.All()[0].Label())
+	shown := settings.Subscriptions()
+	require.Len(t, shown, 1)
+	require.Equal(t, 2, shown[0].Items)
+	require.True(t, shown[0].Exhausted)
+	sub, ok := settings.ItemSubscription(list.All()[1].ID())
+	require.True(t, ok)
+	require.Equal(t, "Provider", sub.Name)
+
+	// Links the provider no longer sends go to the trash, the others are kept.
+	kept := list.All()[0]
+	body = link1
+	require.NoError(t, settings.RefreshSubscription(shown[0].ID))
+	require.Equal(t, []*connlist.Item{kept}, list.All())
+	require.Len(t, bin.All(), 1)
+
+	// Reformatted links keep their items, edited links update them and duplicates are imported once.
+	const (
+		reformatted = "vless://b831381d-6324-4d53-ad4f-8cda48b30811@127.0.0.1:8080?fp=chrome&security=tls&type=tcp#Remark"
+		edited      = "vless://b831381d-6324-4d53-ad4f-8cda48b30811@127.0.0.3:8443?type=tcp&security=tls&fp=chrome#Remark"
+	)
+	body = reformatted + "\n" + reformatted
+	require.NoError(t, settings.RefreshSubscription(shown[0].ID))
+	require.Equal(t, []*connlist.Item{kept}, list.All())
+	require.Equal(t, link1, kept.Link())
+	body = edited
+	require.NoError(t, settings.RefreshSubscription(shown[0].ID))
+	require.Equal(t, []*connlist.Item{kept}, list.All())
+	require.Equal(t, edited, kept.Link())
+	require.Len(t, kept.History(), 1)
+	require.Equal(t, 1, settings.Subscriptions()[0].Items)
+	require.Len(t, bin.All(), 1)
+
+	require.ErrorIs(t, settings.AddSubscription("Broken", srv.URL+"/missing"), subscription.ErrUnexpectedStatus)
+	require.Len(t, settings.Subscriptions(), 1, "not kept if it can not be downloaded")
+}
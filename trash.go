@@ -0,0 +1,82 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/goxray/desktop/internal/connlist"
+	"github.com/goxray/desktop/internal/trash"
+	"github.com/goxray/desktop/window"
+)
+
+// TrashSettings exposes deleted items, their restore and expiry to the settings window.
+type TrashSettings struct {
+	bin  *trash.Bin[SavedState]
+	list *connlist.Collection
+	save *SaveFile
+}
+
+func NewTrashSettings(bin *trash.Bin[SavedState], list *connlist.Collection, save *SaveFile) *TrashSettings {
+	return &TrashSettings{bin: bin, list: list, save: save}
+}
+
+func (t *TrashSettings) TrashEntries() []window.TrashEntry {
+	all := t.bin.All()
+	res := make([]window.TrashEntry, 0, len(all))
+	for _, e := range all {
+		res = append(res, window.TrashEntry{ID: e.ID, Label: e.Label, DeletedAt: e.DeletedAt, ExpiresAt: t.bin.ExpiresAt(e)})
+	}
+
+	return res
+}
+
+// Restore loads the deleted item back to the end of the list, it stays in the trash if it can not be loaded,
+// e.g. a hop of the chain was deleted since.
+func (t *TrashSettings) Restore(id string) error {
+	e, err := t.bin.Get(id)
+	if err != nil {
+		return err
+	}
+	if err := validateDependencies(t.list, e.Item); err != nil {
+		return fmt.Errorf("restore %q: %w", e.Label, err)
+	}
+	if err := deserialize(t.list, e.Item); err != nil {
+		return fmt.Errorf("restore %q: %w", e.Label, err)
+	}
+
+	return t.bin.Remove(id)
+}
+
+// validateDependencies checks that hops of the saved chain and members of the saved balancer exist.
+func validateDependencies(list *connlist.Collection, item SavedState) error {
+	for _, id := range item.Chain {
+		if list.ByID(id) == nil {
+			return fmt.Errorf("%w: %s", connlist.ErrHopNotFound, id)
+		}
+	}
+	for _, id := range item.Members {
+		if list.ByID(id) == nil {
+			return fmt.Errorf("%w: %s", connlist.ErrMemberNotFound, id)
+		}
+	}
+
+	return nil
+}
+
+func (t *TrashSettings) Purge(id string) error {
+	return t.bin.Remove(id)
+}
+
+func (t *TrashSettings) PurgeAll() {
+	t.bin.Clear()
+}
+
+func (t *TrashSettings) TrashExpiry() time.Duration {
+	return t.bin.MaxAge()
+}
+
+func (t *TrashSettings) SetTrashExpiry(d time.Duration) {
+	t.bin.SetMaxAge(d)
+	t.save.UpdateTrashExpiry(d)
+	t.bin.Expire(time.Now())
+}
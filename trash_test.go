@@ -0,0 +1,25 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/goxray/desktop/internal/connlist"
+	"github.com/goxray/desktop/internal/trash"
+)
+
+func TestTrashSettings_RestoreMissingHop(t *testing.T) {
+	list := connlist.New()
+	require.NoError(t, list.AddItem("A", testLink))
+	bin := trash.New[SavedState]()
+	chain := SavedState{ID: "chain", Label: "Chain", Chain: []string{list.All()[0].ID(), "deleted"}}
+	bin.Add(chain.ID, chain.Label, chain, time.Now())
+
+	settings := NewTrashSettings(bin, list, NewSaveFile(mapSource{}))
+	require.ErrorIs(t, settings.Restore(chain.ID), connlist.ErrHopNotFound)
+	require.Len(t, list.All(), 1)
+	_, err := bin.Get(chain.ID)
+	require.NoError(t, err, "kept in the trash")
+}
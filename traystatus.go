@@ -0,0 +1,92 @@
+package main
+
+import (
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/driver/desktop"
+	"fyne.io/systray"
+
+	"github.com/goxray/desktop/internal/connlist"
+	"github.com/goxray/desktop/internal/trayicon"
+	"github.com/goxray/desktop/internal/traystatus"
+)
+
+// TraySettings exposes the tray status interval and the icon graph to the settings window.
+type TraySettings struct {
+	status *traystatus.Updater
+	icon   *trayicon.Updater
+	save   *SaveFile
+}
+
+func NewTraySettings(status *traystatus.Updater, icon *trayicon.Updater, save *SaveFile) *TraySettings {
+	return &TraySettings{status: status, icon: icon, save: save}
+}
+
+func (t *TraySettings) TrayStatusInterval() time.Duration {
+	return t.status.Interval()
+}
+
+func (t *TraySettings) SetTrayStatusInterval(d time.Duration) {
+	t.status.SetInterval(d)
+	t.save.UpdateTrayStatusInterval(d)
+}
+
+func (t *TraySettings) TrayIconGraph() bool {
+	return t.icon.Enabled()
+}
+
+func (t *TraySettings) SetTrayIconGraph(enabled bool) {
+	t.icon.SetEnabled(enabled)
+	t.save.UpdateTrayIconGraph(enabled)
+}
+
+// trayIconDesktop sets tray icons of the tray menu through the icon updater, so the graph does not overwrite
+// progress and warning icons and is shown back when the item is active again.
+type trayIconDesktop struct {
+	desktop.App
+	icon *trayicon.Updater
+}
+
+func (d trayIconDesktop) SetSystemTrayIcon(icon fyne.Resource) {
+	d.icon.SetIcon(icon)
+}
+
+// activeSamples returns recent speed samples of the active item from its recorder.
+func activeSamples(list *connlist.Collection) func() (up, down []float64, connected bool) {
+	return func() ([]float64, []float64, bool) {
+		for _, item := range list.All() {
+			if item.Active() {
+				return item.Read(), item.Written(), true
+			}
+		}
+
+		return nil, nil, false
+	}
+}
+
+// activeStatus returns status of the active item from its recorder.
+func activeStatus(list *connlist.Collection) func() (traystatus.Status, bool) {
+	return func() (traystatus.Status, bool) {
+		for _, item := range list.All() {
+			if !item.Active() {
+				continue
+			}
+
+			s := traystatus.Status{Label: item.Label(), ConnectedAt: item.ConnectedAt(), Unit: item.Unit()}
+			if read, written := item.Read(), item.Written(); len(read) > 0 && len(written) > 0 {
+				s.Up, s.Down = read[len(read)-1], written[len(written)-1]
+			}
+
+			return s, true
+		}
+
+		return traystatus.Status{}, false
+	}
+}
+
+// setTrayStatus shows tooltip and title of the tray icon, the title is only shown on some platforms.
+func setTrayStatus(tooltip, title string) {
+	systray.SetTooltip(tooltip)
+	systray.SetTitle(title)
+}
@@ -0,0 +1,53 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/goxray/desktop/internal/connlist"
+	"github.com/goxray/desktop/internal/usage"
+	"github.com/goxray/desktop/window"
+)
+
+// usageExportLive is the export source of the recorded series, other sources are usage resolutions.
+const usageExportLive = "live"
+
+var errExportItemNotFound = errors.New("connection to export not found")
+
+// UsageSettings exposes persisted usage and its export to the settings window.
+type UsageSettings struct {
+	*usage.Store
+	list *connlist.Collection
+}
+
+func NewUsageSettings(store *usage.Store, list *connlist.Collection) *UsageSettings {
+	return &UsageSettings{Store: store, list: list}
+}
+
+// ExportUsage writes recorded series or persisted usage of the item.
+func (u *UsageSettings) ExportUsage(w io.Writer, req window.UsageExport) error {
+	item := u.list.ByID(req.ItemID)
+	table := usage.Table{Item: req.ItemID, Unit: usage.UnitBytes}
+	if item != nil {
+		table.Item = item.Label()
+	}
+
+	if req.Source == usageExportLive {
+		if item == nil {
+			return fmt.Errorf("%w: %s", errExportItemNotFound, req.ItemID)
+		}
+		read, written, interval := item.Range(req.Span)
+		table.Unit = item.Unit().Name
+		table.Rows = usage.SeriesRows(read, written, interval, time.Now())
+	} else {
+		rows, err := u.Rows(req.ItemID, req.From, req.To, usage.Resolution(req.Source))
+		if err != nil {
+			return err
+		}
+		table.Rows = rows
+	}
+
+	return usage.Write(w, usage.Format(req.Format), table)
+}
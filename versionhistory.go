@@ -0,0 +1,44 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/goxray/desktop/internal/connlist"
+	"github.com/goxray/desktop/window"
+)
+
+var errHistoryItemNotFound = errors.New("connection not found")
+
+// VersionHistory exposes previous versions of connections and their rollback to the settings window.
+type VersionHistory struct {
+	list *connlist.Collection
+}
+
+func NewVersionHistory(list *connlist.Collection) *VersionHistory {
+	return &VersionHistory{list: list}
+}
+
+func (h *VersionHistory) Versions(itemID string) []window.ItemVersion {
+	item := h.list.ByID(itemID)
+	if item == nil {
+		return nil
+	}
+
+	history := item.History()
+	res := make([]window.ItemVersion, 0, len(history))
+	for _, v := range history {
+		res = append(res, window.ItemVersion{Time: v.Time, Label: v.Label, Link: v.Link})
+	}
+
+	return res
+}
+
+func (h *VersionHistory) Rollback(itemID string, i int) error {
+	item := h.list.ByID(itemID)
+	if item == nil {
+		return fmt.Errorf("%w: %s", errHistoryItemNotFound, itemID)
+	}
+
+	return item.Rollback(i)
+}
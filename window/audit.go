@@ -0,0 +1,65 @@
+package window
+
+import (
+	"fmt"
+	"image/color"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/lang"
+	"fyne.io/fyne/v2/theme"
+
+	"github.com/goxray/desktop/internal/audit"
+	customtheme "github.com/goxray/desktop/theme"
+	customwidget "github.com/goxray/desktop/window/widget"
+)
+
+// severityTitles are translation keys of finding severities.
+var severityTitles = map[audit.Severity]string{
+	audit.Info:     "Info",
+	audit.Warning:  "Warning",
+	audit.Critical: "Critical",
+}
+
+// auditItem returns security findings of the item config, the most severe first.
+func auditItem(val ListItem) []audit.Finding {
+	return audit.Default.Audit(val.XRayConfig())
+}
+
+// createFindingBadges creates a badge for each finding worse than info, info findings are only listed in details.
+func createFindingBadges(findings []audit.Finding) []fyne.CanvasObject {
+	badges := make([]fyne.CanvasObject, 0, len(findings))
+	for _, f := range findings {
+		if f.Severity > audit.Info {
+			badges = append(badges, customwidget.NewBadge(lang.L(f.Title), findingColor(f.Severity)))
+		}
+	}
+
+	return badges
+}
+
+func findingColor(s audit.Severity) color.Color {
+	switch s {
+	case audit.Critical:
+		return theme.Color(customtheme.ColorNameTextErrorMuted)
+	case audit.Warning:
+		return theme.Color(theme.ColorNameWarning)
+	default:
+		return theme.Color(customtheme.ColorNameTextMuted)
+	}
+}
+
+// findingsMarkdown returns findings with explanations as markdown, empty if there are none.
+func findingsMarkdown(findings []audit.Finding) string {
+	if len(findings) == 0 {
+		return ""
+	}
+
+	var md strings.Builder
+	md.WriteString("**" + lang.L("Security findings") + "**\n\n")
+	for _, f := range findings {
+		fmt.Fprintf(&md, "- **%s, %s:** %s\n", lang.L(f.Title), lang.L(severityTitles[f.Severity]), lang.L(f.Explanation))
+	}
+
+	return md.String()
+}
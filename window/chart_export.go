@@ -0,0 +1,94 @@
+package window
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/lang"
+	"fyne.io/fyne/v2/widget"
+
+	"github.com/goxray/desktop/internal/netchart"
+	customwidget "github.com/goxray/desktop/window/widget"
+)
+
+// chartExportSize is the default size of exported charts.
+var chartExportSize = fyne.NewSize(1200, 500)
+
+// chartExportMaxSide limits width and height of exported charts, larger images take too much memory to render.
+const chartExportMaxSide = 8192
+
+// chartExportFormats are image formats offered for chart export.
+var chartExportFormats = []string{string(netchart.ImagePNG), string(netchart.ImageSVG)}
+
+// showChartExportDialog asks for the range, size and format of the item traffic chart and saves it to the chosen file.
+func (w *Settings[T]) showChartExportDialog(item T, rangeIndex int) {
+	rangeTitles := make([]string, 0, len(customwidget.ChartRanges))
+	for _, r := range customwidget.ChartRanges {
+		rangeTitles = append(rangeTitles, r.Title)
+	}
+	span := widget.NewSelect(rangeTitles, nil)
+	span.SetSelectedIndex(rangeIndex)
+
+	width := widget.NewEntry()
+	width.SetText(strconv.Itoa(int(chartExportSize.Width)))
+	height := widget.NewEntry()
+	height.SetText(strconv.Itoa(int(chartExportSize.Height)))
+
+	format := widget.NewRadioGroup(chartExportFormats, nil)
+	format.Horizontal = true
+	format.SetSelected(chartExportFormats[0])
+
+	dialog.ShowForm(lang.L("Export chart"), lang.L("Export"), lang.L("Cancel"), []*widget.FormItem{
+		widget.NewFormItem(lang.L("Range"), span),
+		widget.NewFormItem(lang.L("Width"), width),
+		widget.NewFormItem(lang.L("Height"), height),
+		widget.NewFormItem(lang.L("Format"), format),
+	}, func(ok bool) {
+		if !ok {
+			return
+		}
+
+		var size fyne.Size
+		for _, dim := range []struct {
+			entry *widget.Entry
+			value *float32
+		}{{width, &size.Width}, {height, &size.Height}} {
+			v, err := strconv.Atoi(strings.TrimSpace(dim.entry.Text))
+			if err != nil || v <= 0 {
+				dialog.ShowError(fmt.Errorf("%w: %q", errInvalidNumber, dim.entry.Text), w.window)
+				return
+			}
+			if v > chartExportMaxSide {
+				dialog.ShowError(fmt.Errorf("%w: %d, at most %d", errChartTooLarge, v, chartExportMaxSide), w.window)
+				return
+			}
+			*dim.value = float32(v)
+		}
+
+		w.saveChartExport(item, customwidget.ChartRanges[span.SelectedIndex()], size, netchart.ImageFormat(format.Selected))
+	}, w.window)
+}
+
+// saveChartExport asks for a file and writes the chart image to it.
+func (w *Settings[T]) saveChartExport(item T, r customwidget.ChartRange, size fyne.Size, format netchart.ImageFormat) {
+	save := dialog.NewFileSave(func(file fyne.URIWriteCloser, err error) {
+		if err != nil {
+			dialog.ShowError(err, w.window)
+			return
+		}
+		if file == nil { // Canceled.
+			return
+		}
+		defer file.Close()
+
+		err = customwidget.ExportNetworkChart(file, format, lang.L("upload"), lang.L("download"), r.Span, size, item)
+		if err != nil {
+			dialog.ShowError(err, w.window)
+		}
+	}, w.window)
+	save.SetFileName(fmt.Sprintf("chart-%s-%s.%s", item.Label(), r.Title, format))
+	save.Show()
+}
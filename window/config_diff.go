@@ -0,0 +1,94 @@
+package window
+
+import (
+	"maps"
+	"slices"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/lang"
+	"fyne.io/fyne/v2/layout"
+	"fyne.io/fyne/v2/widget"
+)
+
+// fieldChange is how a config field changes in an update.
+type fieldChange int
+
+const (
+	fieldUnchanged fieldChange = iota
+	fieldChanged
+	fieldAdded
+	fieldRemoved
+)
+
+// fieldChangeImportance highlights changed fields, e.g. added ones are shown as success.
+var fieldChangeImportance = map[fieldChange]widget.Importance{
+	fieldUnchanged: widget.MediumImportance,
+	fieldChanged:   widget.WarningImportance,
+	fieldAdded:     widget.SuccessImportance,
+	fieldRemoved:   widget.DangerImportance,
+}
+
+type configFieldDiff struct {
+	key, old, new string
+	change        fieldChange
+}
+
+// configDiff are fields of old and new configs, ordered by key.
+type configDiff []configFieldDiff
+
+// diffConfigs compares old and new configs, fields empty in both are left out.
+func diffConfigs(old, new map[string]string) configDiff {
+	keys := slices.AppendSeq(slices.Collect(maps.Keys(old)), maps.Keys(new))
+	slices.Sort(keys)
+	keys = slices.Compact(keys)
+
+	diff := make(configDiff, 0, len(keys))
+	for _, k := range keys {
+		d := configFieldDiff{key: k, old: old[k], new: new[k]}
+		switch {
+		case d.old == d.new:
+			d.change = fieldUnchanged
+		case d.old == "":
+			d.change = fieldAdded
+		case d.new == "":
+			d.change = fieldRemoved
+		default:
+			d.change = fieldChanged
+		}
+		if d.old != "" || d.new != "" {
+			diff = append(diff, d)
+		}
+	}
+
+	return diff
+}
+
+// changed reports whether any field changed.
+func (d configDiff) changed() bool {
+	return slices.ContainsFunc(d, func(f configFieldDiff) bool { return f.change != fieldUnchanged })
+}
+
+// showConfigDiff shows the current and new config side by side, onConfirm is called if the change is confirmed.
+func (w *Settings[T]) showConfigDiff(diff configDiff, onConfirm func()) {
+	bold := func(text string) *widget.Label {
+		return widget.NewLabelWithStyle(text, fyne.TextAlignLeading, fyne.TextStyle{Bold: true})
+	}
+	grid := container.New(layout.NewGridLayout(3), bold(lang.L("Field")), bold(lang.L("Current")), bold(lang.L("New")))
+	for _, f := range diff {
+		importance := fieldChangeImportance[f.change]
+		for _, text := range []string{lang.L(f.key), f.old, f.new} {
+			grid.Add(&widget.Label{Text: text, Importance: importance, Truncation: fyne.TextTruncateEllipsis})
+		}
+	}
+
+	d := dialog.NewCustomConfirm(lang.L("Review changes"), lang.L("Update"), lang.L("Cancel"),
+		container.NewVScroll(grid), func(ok bool) {
+			if ok {
+				onConfirm()
+			}
+		}, w.window)
+	d.Resize(fyne.NewSize(650, 450))
+	d.Show()
+}
@@ -0,0 +1,58 @@
+package window
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiffConfigs(t *testing.T) {
+	tests := []struct {
+		name     string
+		old, new map[string]string
+		want     configDiff
+		changed  bool
+	}{
+		{
+			name:    "unchanged",
+			old:     map[string]string{"Address": "1.1.1.1", "Port": "443"},
+			new:     map[string]string{"Address": "1.1.1.1", "Port": "443"},
+			want:    configDiff{{"Address", "1.1.1.1", "1.1.1.1", fieldUnchanged}, {"Port", "443", "443", fieldUnchanged}},
+			changed: false,
+		},
+		{
+			name:    "added",
+			old:     map[string]string{"Port": "443"},
+			new:     map[string]string{"Port": "443", "SNI": "example.com"},
+			want:    configDiff{{"Port", "443", "443", fieldUnchanged}, {"SNI", "", "example.com", fieldAdded}},
+			changed: true,
+		},
+		{
+			name:    "removed",
+			old:     map[string]string{"Port": "443", "SNI": "example.com"},
+			new:     map[string]string{"Port": "443", "SNI": ""},
+			want:    configDiff{{"Port", "443", "443", fieldUnchanged}, {"SNI", "example.com", "", fieldRemoved}},
+			changed: true,
+		},
+		{
+			name:    "changed",
+			old:     map[string]string{"Port": "443"},
+			new:     map[string]string{"Port": "8443"},
+			want:    configDiff{{"Port", "443", "8443", fieldChanged}},
+			changed: true,
+		},
+		{
+			name:    "empty in both is left out",
+			old:     map[string]string{"Port": "443", "Flow": ""},
+			new:     map[string]string{"Port": "443", "Flow": ""},
+			want:    configDiff{{"Port", "443", "443", fieldUnchanged}},
+			changed: false,
+		},
+	}
+
+	for _, tt := range tests {
+		diff := diffConfigs(tt.old, tt.new)
+		require.Equal(t, tt.want, diff, tt.name)
+		require.Equal(t, tt.changed, diff.changed(), tt.name)
+	}
+}
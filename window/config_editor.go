@@ -0,0 +1,255 @@
+package window
+
+import (
+	"fmt"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/lang"
+	"fyne.io/fyne/v2/layout"
+	"fyne.io/fyne/v2/widget"
+
+	"github.com/goxray/desktop/internal/sharelink"
+)
+
+// configEditorRow is a labeled input of the editor shown only for configs it applies to.
+type configEditorRow struct {
+	label *widget.Label
+	input fyne.CanvasObject
+	shown func(protocol, security string) bool
+}
+
+// configEditor edits share links with typed fields per protocol, fields are regenerated to a canonical link.
+type configEditor struct {
+	protocol, security                 *widget.Select
+	cipher, flow, fingerprint, network *widget.SelectEntry
+	remark, address, port, id, alterID *widget.Entry
+	sni, alpn, publicKey, shortID      *widget.Entry
+	host, path                         *widget.Entry
+	idLabel, pathLabel                 *widget.Label
+	rows                               []configEditorRow
+	extra                              url.Values // Parameters without a field, kept as is for the same protocol.
+	extraProtocol                      string
+	container                          *fyne.Container
+}
+
+func newConfigEditor(c sharelink.Config) *configEditor {
+	e := &configEditor{
+		security:    widget.NewSelect(sharelink.Securities, nil),
+		cipher:      widget.NewSelectEntry(nil),
+		flow:        widget.NewSelectEntry(sharelink.Flows),
+		fingerprint: widget.NewSelectEntry(sharelink.Fingerprints),
+		network:     widget.NewSelectEntry(sharelink.Networks),
+		remark:      widget.NewEntry(),
+		address:     &widget.Entry{PlaceHolder: "example.com"},
+		port:        widget.NewEntry(),
+		id:          widget.NewEntry(),
+		alterID:     widget.NewEntry(),
+		sni:         widget.NewEntry(),
+		alpn:        &widget.Entry{PlaceHolder: "h2,http/1.1"},
+		publicKey:   widget.NewEntry(),
+		shortID:     widget.NewEntry(),
+		host:        widget.NewEntry(),
+		path:        &widget.Entry{PlaceHolder: "/"},
+		idLabel:     widget.NewLabel(""),
+		pathLabel:   widget.NewLabel(""),
+	}
+	e.protocol = widget.NewSelect(sharelink.Protocols, func(p string) {
+		// Keep the cipher only if the new protocol has it.
+		if !slices.Contains(cipherOptions(p), e.cipher.Text) {
+			e.cipher.SetText(sharelink.New(p).Cipher)
+		}
+		e.update()
+	})
+	e.security.OnChanged = func(string) { e.update() }
+	e.network.OnChanged = func(string) { e.update() }
+
+	always := func(string, string) bool { return true }
+	only := func(protocols ...string) func(string, string) bool {
+		return func(p, _ string) bool { return slices.Contains(protocols, p) }
+	}
+	transport := only(sharelink.VLESS, sharelink.VMess, sharelink.Trojan)
+	tls := func(p, s string) bool { return transport(p, s) && (s == "tls" || s == "reality") }
+	reality := func(p, s string) bool { return transport(p, s) && s == "reality" }
+	e.rows = []configEditorRow{
+		{widget.NewLabel(lang.L("Protocol")), e.protocol, always},
+		{widget.NewLabel(lang.L("Remark")), e.remark, always},
+		{widget.NewLabel(lang.L("Address")), e.address, always},
+		{widget.NewLabel(lang.L("Port")), e.port, always},
+		{e.idLabel, e.id, always},
+		{widget.NewLabel(lang.L("Encryption")), e.cipher, only(sharelink.VMess, sharelink.Shadowsocks)},
+		{widget.NewLabel(lang.L("Alter ID")), e.alterID, only(sharelink.VMess)},
+		{widget.NewLabel(lang.L("Flow")), e.flow, only(sharelink.VLESS)},
+		{widget.NewLabel(lang.L("Security")), e.security, transport},
+		{widget.NewLabel(lang.L("SNI")), e.sni, tls},
+		{widget.NewLabel(lang.L("Fingerprint")), e.fingerprint, tls},
+		{widget.NewLabel(lang.L("ALPN")), e.alpn, tls},
+		{widget.NewLabel(lang.L("Public key")), e.publicKey, reality},
+		{widget.NewLabel(lang.L("Short ID")), e.shortID, reality},
+		{widget.NewLabel(lang.L("Network")), e.network, transport},
+		{widget.NewLabel(lang.L("Host")), e.host, transport},
+		{e.pathLabel, e.path, transport},
+	}
+
+	e.container = container.New(layout.NewFormLayout())
+	for _, r := range e.rows {
+		e.container.Add(r.label)
+		e.container.Add(r.input)
+	}
+	e.set(c)
+
+	return e
+}
+
+// cipherOptions returns ciphers offered for the protocol.
+func cipherOptions(protocol string) []string {
+	switch protocol {
+	case sharelink.VMess:
+		return sharelink.VMessCiphers
+	case sharelink.Shadowsocks:
+		return sharelink.ShadowCiphers
+	default:
+		return nil
+	}
+}
+
+func (e *configEditor) Container() *fyne.Container {
+	return e.container
+}
+
+// set fills the fields with the config.
+func (e *configEditor) set(c sharelink.Config) {
+	e.protocol.SetSelected(c.Protocol)
+	e.cipher.SetText(c.Cipher)
+	e.remark.SetText(c.Remark)
+	e.address.SetText(c.Address)
+	e.port.SetText(strconv.Itoa(c.Port))
+	e.id.SetText(c.ID)
+	e.alterID.SetText(strconv.Itoa(c.AlterID))
+	e.flow.SetText(c.Flow)
+	e.sni.SetText(c.SNI)
+	e.fingerprint.SetText(c.Fingerprint)
+	e.alpn.SetText(c.ALPN)
+	e.publicKey.SetText(c.PublicKey)
+	e.shortID.SetText(c.ShortID)
+	e.network.SetText(c.Network)
+	e.host.SetText(c.Host)
+	e.path.SetText(c.Path)
+	e.extra, e.extraProtocol = c.Extra, c.Protocol
+	if c.Security == "" {
+		c.Security = sharelink.Securities[0]
+	}
+	e.security.SetSelected(c.Security)
+	e.update()
+}
+
+// config returns the config of the fields, fields hidden for the protocol are left out.
+func (e *configEditor) config() (sharelink.Config, error) {
+	port, err := strconv.Atoi(strings.TrimSpace(e.port.Text))
+	if err != nil {
+		return sharelink.Config{}, fmt.Errorf("%w: %q", errInvalidNumber, e.port.Text)
+	}
+
+	c := sharelink.Config{
+		Protocol: e.protocol.Selected,
+		Remark:   e.remark.Text,
+		Address:  strings.TrimSpace(e.address.Text),
+		Port:     port,
+		ID:       strings.TrimSpace(e.id.Text),
+	}
+	if c.Protocol == e.extraProtocol {
+		c.Extra = e.extra
+	}
+	switch c.Protocol {
+	case sharelink.VLESS:
+		c.Flow = e.flow.Text
+	case sharelink.VMess:
+		c.Cipher = e.cipher.Text
+		if text := strings.TrimSpace(e.alterID.Text); text != "" {
+			if c.AlterID, err = strconv.Atoi(text); err != nil {
+				return sharelink.Config{}, fmt.Errorf("%w: %q", errInvalidNumber, e.alterID.Text)
+			}
+		}
+	case sharelink.Shadowsocks:
+		c.Cipher = e.cipher.Text
+
+		return c, nil // No transport settings.
+	}
+
+	c.Security, c.Network, c.Host, c.Path = e.security.Selected, e.network.Text, e.host.Text, e.path.Text
+	c.SNI, c.Fingerprint, c.ALPN = e.sni.Text, e.fingerprint.Text, e.alpn.Text
+	c.PublicKey, c.ShortID = e.publicKey.Text, e.shortID.Text
+
+	return c, nil
+}
+
+// update shows fields of the selected protocol and security.
+func (e *configEditor) update() {
+	p, s := e.protocol.Selected, e.security.Selected
+	for _, r := range e.rows {
+		if r.shown(p, s) {
+			r.label.Show()
+			r.input.Show()
+		} else {
+			r.label.Hide()
+			r.input.Hide()
+		}
+	}
+
+	e.cipher.SetOptions(cipherOptions(p))
+	if p == sharelink.VLESS || p == sharelink.VMess {
+		e.idLabel.SetText(lang.L("UUID"))
+	} else {
+		e.idLabel.SetText(lang.L("Password"))
+	}
+	if e.network.Text == "grpc" {
+		e.pathLabel.SetText(lang.L("ServiceName"))
+	} else {
+		e.pathLabel.SetText(lang.L("Path"))
+	}
+	e.container.Refresh()
+}
+
+// showConfigEditor opens the structured editor of the link, a new config is built from scratch if link is empty.
+// onApply receives the regenerated link and its remark.
+func (w *Settings[T]) showConfigEditor(link string, onApply func(link, remark string)) {
+	title := lang.L("Edit configuration")
+	c := sharelink.New(sharelink.VLESS)
+	if strings.TrimSpace(link) == "" {
+		title = lang.L("New configuration")
+	} else {
+		var err error
+		if c, err = sharelink.Parse(link); err != nil {
+			dialog.ShowError(err, w.window)
+			return
+		}
+	}
+
+	editor := newConfigEditor(c)
+	var d *dialog.ConfirmDialog
+	d = dialog.NewCustomConfirm(title, lang.L("Apply"), lang.L("Cancel"), container.NewVScroll(editor.Container()),
+		func(ok bool) {
+			if !ok {
+				return
+			}
+
+			c, err := editor.config()
+			if err == nil {
+				link, err = c.Link()
+			}
+			if err != nil {
+				d.Show() // Keep the fields for the user to fix.
+				dialog.ShowError(err, w.window)
+
+				return
+			}
+			onApply(link, c.Remark)
+		}, w.window)
+	d.Resize(fyne.NewSize(500, 550))
+	d.Show()
+}
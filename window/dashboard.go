@@ -0,0 +1,159 @@
+package window
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/lang"
+	"fyne.io/fyne/v2/widget"
+
+	"github.com/goxray/desktop/internal/netchart"
+	customwidget "github.com/goxray/desktop/window/widget"
+)
+
+const (
+	// dashboardRefreshInterval is how often the dashboard is updated while the window is open.
+	dashboardRefreshInterval = time.Second
+	// dashboardEvents is the number of recent events shown.
+	dashboardEvents = 10
+)
+
+// itemUsage is traffic of an item in the current month.
+type itemUsage struct {
+	label string
+	bytes int64
+}
+
+// createDashboardContainer creates tab with aggregated traffic of all connections and recent connection events.
+func (w *Settings[T]) createDashboardContainer() *fyne.Container {
+	totals := widget.NewRichText()
+	current := widget.NewLabel("")
+	breakdown := container.NewVBox()
+	events := container.NewVBox()
+
+	refresh := func() {
+		vals, _ := w.list.Get()
+		now := time.Now()
+
+		var speedUp, speedDown float64
+		var todayRead, todayWritten, monthRead, monthWritten int64
+		unit := netchart.MegabitsPerSecond
+		var active T
+		var hasActive bool
+		usages := make([]itemUsage, 0, len(vals))
+		for _, v := range vals {
+			item := v.(T)
+			if item.Active() {
+				active, hasActive = item, true
+				if read, written := item.Read(), item.Written(); len(read) > 0 && len(written) > 0 {
+					speedUp += read[len(read)-1]
+					speedDown += written[len(written)-1]
+					unit = item.Unit()
+				}
+			}
+
+			dailyRead, dailyWritten := w.usage.Daily(item.ID(), now, 1)
+			todayRead, todayWritten = todayRead+dailyRead[0], todayWritten+dailyWritten[0]
+			read, written := w.usage.Month(item.ID(), now)
+			monthRead, monthWritten = monthRead+read, monthWritten+written
+			if read+written > 0 {
+				usages = append(usages, itemUsage{label: item.Label(), bytes: read + written})
+			}
+		}
+
+		totals.ParseMarkdown(fmt.Sprintf(
+			"**%s:** ↑%s ↓%s\n\n**%s:** ↑%s ↓%s\n\n**%s:** ↑%s ↓%s",
+			lang.L("Speed"), netchart.FormatRate(speedUp, unit), netchart.FormatRate(speedDown, unit),
+			lang.L("Today"), customwidget.FormatBytes(todayRead), customwidget.FormatBytes(todayWritten),
+			lang.L("This month"), customwidget.FormatBytes(monthRead), customwidget.FormatBytes(monthWritten),
+		))
+
+		if hasActive {
+			current.SetText(lang.L("Current connection: {{.Label}}, uptime {{.Uptime}}", map[string]any{
+				"Label": active.Label(), "Uptime": now.Sub(active.ConnectedAt()).Round(time.Second).String()}))
+		} else {
+			current.SetText(lang.L("Not connected"))
+		}
+
+		breakdown.Objects = w.createUsageBreakdown(usages, monthRead+monthWritten)
+		breakdown.Refresh()
+		events.Objects = w.createRecentEvents(now)
+		events.Refresh()
+	}
+	refresh()
+
+	go func() {
+		for {
+			select {
+			case <-w.ctx.Done():
+				return
+			case <-time.After(dashboardRefreshInterval):
+				refresh()
+			}
+		}
+	}()
+
+	return container.NewBorder(
+		container.NewVBox(totals, current, widget.NewSeparator()),
+		container.NewVBox(widget.NewSeparator(), w.createTraySettings()), nil, nil,
+		container.NewGridWithColumns(2,
+			container.NewBorder(widget.NewLabelWithStyle(lang.L("Usage this month by connection"), fyne.TextAlignLeading,
+				fyne.TextStyle{Bold: true}), nil, nil, nil, container.NewVScroll(breakdown)),
+			container.NewBorder(widget.NewLabelWithStyle(lang.L("Recent events"), fyne.TextAlignLeading,
+				fyne.TextStyle{Bold: true}), nil, nil, nil, container.NewVScroll(events)),
+		),
+	)
+}
+
+// createUsageBreakdown creates a bar of each item usage share of the total, the biggest first.
+func (w *Settings[T]) createUsageBreakdown(usages []itemUsage, total int64) []fyne.CanvasObject {
+	slices.SortStableFunc(usages, func(a, b itemUsage) int { return cmp.Compare(b.bytes, a.bytes) })
+
+	rows := make([]fyne.CanvasObject, 0, len(usages))
+	for _, u := range usages {
+		bar := widget.NewProgressBar()
+		bar.TextFormatter = func() string { return customwidget.FormatBytes(u.bytes) }
+		bar.SetValue(float64(u.bytes) / float64(total))
+		rows = append(rows, container.NewBorder(nil, nil, widget.NewLabel(u.label), nil, bar))
+	}
+	if len(rows) == 0 {
+		rows = append(rows, widget.NewLabel(lang.L("No traffic yet")))
+	}
+
+	return rows
+}
+
+// createRecentEvents creates a line for each recent connection event, the newest first.
+func (w *Settings[T]) createRecentEvents(now time.Time) []fyne.CanvasObject {
+	recent := w.events.RecentEvents(dashboardEvents)
+	rows := make([]fyne.CanvasObject, 0, len(recent))
+	for _, e := range recent {
+		layout := time.TimeOnly
+		if y, m, d := e.Time.Date(); y != now.Year() || m != now.Month() || d != now.Day() {
+			layout = time.DateTime
+		}
+		kind := e.Kind
+		for _, k := range connectionEventKinds {
+			if k.name == e.Kind {
+				kind = lang.L(k.title)
+			}
+		}
+
+		text := fmt.Sprintf("%s  %s: %s", e.Time.Format(layout), kind, e.Label)
+		if e.Err != "" {
+			text += " (" + e.Err + ")"
+		}
+		label := widget.NewLabel(text)
+		label.Truncation = fyne.TextTruncateEllipsis
+		rows = append(rows, label)
+	}
+	if len(rows) == 0 {
+		rows = append(rows, widget.NewLabel(lang.L("No events yet")))
+	}
+
+	return rows
+}
@@ -3,7 +3,15 @@ package window
 import (
 	_ "embed"
 	"errors"
+	"io"
+	"strings"
 	"time"
+
+	"fyne.io/fyne/v2/data/binding"
+	"fyne.io/fyne/v2/lang"
+
+	"github.com/goxray/desktop/internal/netchart"
+	"github.com/goxray/desktop/internal/sharelink"
 )
 
 //go:embed about_static.md
@@ -14,9 +22,93 @@ const (
 	repositoryLink = "https://github.com/goxray"
 )
 
+// balancerStrategies must match strategies supported by composite balancer.
+var balancerStrategies = []string{"random", "roundRobin", "leastPing"}
+
+// networkFields must match fields supported by network rules, values are translation keys.
+var networkFields = []struct{ name, title string }{
+	{"gateway_ip", "Gateway IP"},
+	{"gateway_mac", "Gateway MAC"},
+	{"interface", "Interface"},
+	{"dns_suffix", "DNS suffix"},
+}
+
+// ruleActions must match actions supported by rules, connect must go first as it requires a connection.
+var ruleActions = []struct{ name, title string }{
+	{"connect", "Connect"},
+	{"disconnect", "Disconnect"},
+}
+
+// weekdays in display order, titles are translation keys.
+var weekdays = []struct {
+	day   time.Weekday
+	title string
+}{
+	{time.Monday, "Mon"},
+	{time.Tuesday, "Tue"},
+	{time.Wednesday, "Wed"},
+	{time.Thursday, "Thu"},
+	{time.Friday, "Fri"},
+	{time.Saturday, "Sat"},
+	{time.Sunday, "Sun"},
+}
+
+// usageExportSources must match sources supported by usage export, titles are translation keys.
+var usageExportSources = []struct{ name, title string }{
+	{"day", "Daily history"},
+	{"month", "Monthly history"},
+	{"live", "Recorded chart"},
+}
+
+// connectionEventKinds must match kinds of connection events, titles are translation keys.
+var connectionEventKinds = []struct{ name, title string }{
+	{"connected", "Connected"},
+	{"disconnected", "Disconnected"},
+	{"failed", "Connection failed"},
+}
+
+// sessionReasons must match reasons sessions end with, titles are translation keys.
+var sessionReasons = []struct{ name, title string }{
+	{"user", "By user"},
+	{"error", "Error"},
+	{"reconnect", "Reconnect"},
+	{"shutdown", "Shutdown"},
+}
+
+// trayStatusIntervals are offered intervals of tray status updates, titles are translation keys.
+var trayStatusIntervals = []struct {
+	interval time.Duration
+	title    string
+}{
+	{0, "Off"},
+	{time.Second, "Every second"},
+	{2 * time.Second, "Every 2 seconds"},
+	{5 * time.Second, "Every 5 seconds"},
+	{10 * time.Second, "Every 10 seconds"},
+}
+
+// trashExpiries are offered ages after which deleted items are purged, titles are translation keys.
+var trashExpiries = []struct {
+	age   time.Duration
+	title string
+}{
+	{24 * time.Hour, "After a day"},
+	{7 * 24 * time.Hour, "After a week"},
+	{30 * 24 * time.Hour, "After 30 days"},
+	{90 * 24 * time.Hour, "After 90 days"},
+	{0, "Never"},
+}
+
+// usageExportFormats must match formats supported by usage export.
+var usageExportFormats = []string{"csv", "json"}
+
 var (
 	errChangeActiveItem     = errors.New("disconnect before editing")
 	errEmptyUpdateFormValue = errors.New("label or link empty")
+	errNoItemSelected       = errors.New("connection is not selected")
+	errInvalidNumber        = errors.New("invalid number")
+	errInvalidDate          = errors.New("invalid date, expected YYYY-MM-DD")
+	errChartTooLarge        = errors.New("chart size is too large")
 )
 
 type FormData struct {
@@ -28,8 +120,29 @@ func (f *FormData) Validate() error {
 	if f.Label == "" || f.Link == "" {
 		return errEmptyUpdateFormValue
 	}
+	_, err := linkDiagnostics(f.Link)
+
+	return err
+}
+
+// linkDiagnostics returns translated problems of the link fields, err joins the ones that prevent saving.
+func linkDiagnostics(link string) (problems []string, err error) {
+	if strings.TrimSpace(link) == "" {
+		return nil, nil
+	}
+
+	var errs []error
+	for _, p := range sharelink.Diagnose(link) {
+		text := lang.L(p.Field) + ": " + lang.L(p.Message)
+		if p.Warning {
+			problems = append(problems, "⚠ "+text)
+			continue
+		}
+		problems = append(problems, text)
+		errs = append(errs, errors.New(text))
+	}
 
-	return nil
+	return problems, errors.Join(errs...)
 }
 
 type NetworkRecorder interface {
@@ -44,13 +157,208 @@ type NetworkRecorder interface {
 	// BytesWritten should return the total number of bytes for downlink.
 	BytesWritten() int
 	RecordInterval() time.Duration
+	// Range should return uplink and downlink values covering the last span and their interval.
+	Range(span time.Duration) (read, written []float64, interval time.Duration)
+	// Unit should return the unit of recorded values.
+	Unit() netchart.Unit
+	// Updates should notify listeners when new values are recorded.
+	Updates() binding.DataItem
 }
 
 type ListItem interface {
 	NetworkRecorder
 
+	ID() string
 	Label() string
 	Link() string
 	XRayConfig() map[string]string
 	Active() bool
+	// ConnectedAt should return time of the last successful connect, zero if not connected.
+	ConnectedAt() time.Time
+	// IsComposite reports whether the item is combined from other items (chain, balancer) and has no link.
+	IsComposite() bool
+	// Members returns labels of the balanced items, empty for other items.
+	Members() []string
+	// MemberTraffic returns number of bytes for uplink and downlink of the i-th member.
+	MemberTraffic(i int) (read int, written int)
+	// MemberHealth returns the last health check result of the i-th member, observed is false if not checked yet.
+	MemberHealth(i int) (observed, alive bool, delay time.Duration)
+}
+
+// NetworkRule connects ItemID or disconnects when the current network Field has Value.
+type NetworkRule struct {
+	Field  string
+	Value  string
+	Action string
+	ItemID string
+}
+
+type NetworkRules interface {
+	// CurrentNetwork returns identity of the current network as field name to value, undetected fields are empty.
+	CurrentNetwork() map[string]string
+	NetworkRules() []NetworkRule
+	AddNetworkRule(rule NetworkRule) error
+	RemoveNetworkRule(i int) error
+}
+
+// ScheduleRule performs Action at Start on Days (every day if empty), connect rules disconnect at End if set.
+type ScheduleRule struct {
+	Days   []time.Weekday
+	Start  string // In HH:MM format.
+	End    string // In HH:MM format, empty if not set.
+	Action string
+	ItemID string
+}
+
+type ScheduleRules interface {
+	ScheduleRules() []ScheduleRule
+	AddScheduleRule(rule ScheduleRule) error
+	RemoveScheduleRule(i int) error
+}
+
+// UsageStats is persisted traffic usage of items, values are uplink (read) and downlink (written) bytes.
+type UsageStats interface {
+	// Lifetime should return all bytes ever counted for the item.
+	Lifetime(itemID string) (read, written int64)
+	// Month should return bytes of the item counted in the month of t.
+	Month(itemID string, t time.Time) (read, written int64)
+	// Daily should return bytes of the item for each of the days ending with the day of to, oldest first.
+	Daily(itemID string, to time.Time, days int) (read, written []int64)
+	// ExportUsage should write traffic of the item described by req to w.
+	ExportUsage(w io.Writer, req UsageExport) error
+}
+
+// UsageExport describes traffic of an item to export.
+type UsageExport struct {
+	ItemID   string
+	Source   string        // One of usageExportSources.
+	From, To time.Time     // Range of persisted usage.
+	Span     time.Duration // Range of the recorded series, until now.
+	Format   string        // One of usageExportFormats.
+}
+
+// Quota limits traffic (uplink and downlink together) of an item per billing cycle.
+type Quota struct {
+	Limit          int64 // Bytes per billing cycle.
+	ResetDay       int   // Day of month the billing cycle starts.
+	Thresholds     []int // Warning levels in percent of Limit.
+	AutoDisconnect bool
+}
+
+type Quotas interface {
+	// Quota should return quota of the item, ok is false if the item has no quota.
+	Quota(itemID string) (q Quota, ok bool)
+	SetQuota(itemID string, q Quota) error
+	RemoveQuota(itemID string)
+	// QuotaUsage should return bytes used in the current billing cycle, ok is false if the item has no quota.
+	QuotaUsage(itemID string) (used int64, ok bool)
+}
+
+// Subscription is a provider URL connections are imported from, with usage and expiry reported by the provider.
+type Subscription struct {
+	ID        string
+	Name      string
+	URL       string
+	HasInfo   bool      // Whether the provider reports usage and expiry, the fields below are zero otherwise.
+	Used      int64     // Bytes in both directions.
+	Total     int64     // Bytes, 0 if unlimited.
+	Expire    time.Time // Zero if the subscription never expires.
+	Expired   bool
+	Exhausted bool
+	UpdatedAt time.Time // Zero if never updated.
+	Items     int
+}
+
+type Subscriptions interface {
+	Subscriptions() []Subscription
+	// AddSubscription should download the subscription and import its connections.
+	AddSubscription(name, url string) error
+	// RefreshSubscription should download the subscription again, importing new connections and moving
+	// the ones the provider no longer sends to the trash.
+	RefreshSubscription(id string) error
+	// RemoveSubscription should forget the subscription, its connections stay in the list.
+	RemoveSubscription(id string) error
+	// ItemSubscription should return the subscription the item was imported from.
+	ItemSubscription(itemID string) (Subscription, bool)
+}
+
+type MetricsEndpoint interface {
+	// MetricsURL should return the URL metrics are served at.
+	MetricsURL() string
+	MetricsEnabled() bool
+	SetMetricsEnabled(enabled bool) error
+}
+
+// ConnectionEvent is a connect, disconnect or a failed connection attempt of an item.
+type ConnectionEvent struct {
+	Time  time.Time
+	Label string
+	Kind  string // One of connectionEventKinds.
+	Err   string
+}
+
+type ConnectionEvents interface {
+	// RecentEvents should return up to n latest events, the newest first.
+	RecentEvents(n int) []ConnectionEvent
+}
+
+// Session is a single connection of an item, failed connection attempts have the same Start and End.
+type Session struct {
+	Label         string
+	Start, End    time.Time
+	Read, Written int64
+	Reason        string // One of sessionReasons.
+	Err           string
+}
+
+type SessionHistory interface {
+	// Sessions should return ended sessions, the newest first.
+	Sessions() []Session
+	// ExportSessions should write all sessions in format, one of usageExportFormats.
+	ExportSessions(w io.Writer, format string) error
+}
+
+type TraySettings interface {
+	// TrayStatusInterval should return how often the tray tooltip and title are updated, 0 if disabled.
+	TrayStatusInterval() time.Duration
+	SetTrayStatusInterval(d time.Duration)
+	// TrayIconGraph should return whether the tray icon shows a graph of the active connection speed.
+	TrayIconGraph() bool
+	SetTrayIconGraph(enabled bool)
+}
+
+// ItemVersion is a previous link and label of an item.
+type ItemVersion struct {
+	Time  time.Time
+	Label string
+	Link  string // Empty for composite items.
+}
+
+type VersionHistory interface {
+	// Versions should return previous versions of the item, the newest first.
+	Versions(itemID string) []ItemVersion
+	// Rollback should restore the i-th version of Versions, keeping the current one in history.
+	Rollback(itemID string, i int) error
+}
+
+// TrashEntry is a deleted item that can be restored.
+type TrashEntry struct {
+	ID        string
+	Label     string
+	DeletedAt time.Time
+	ExpiresAt time.Time // Zero if the entry never expires.
+}
+
+type Trash interface {
+	// TrashEntries should return deleted items, the most recently deleted first.
+	TrashEntries() []TrashEntry
+	// Restore should put the deleted item back to the list and remove it from the trash.
+	Restore(id string) error
+	// Purge should delete the item permanently.
+	Purge(id string) error
+	// PurgeAll should delete all items in the trash permanently.
+	PurgeAll()
+	// TrashExpiry should return how long deleted items are kept, 0 if forever.
+	TrashExpiry() time.Duration
+	SetTrashExpiry(d time.Duration)
 }
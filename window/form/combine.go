@@ -0,0 +1,114 @@
+package form
+
+import (
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+// Combine is a form to combine existing connections into one, e.g. a chain where each hop dials
+// through the next one or a balancer.
+type Combine struct {
+	errLabel       *widget.Label
+	label          *widget.Entry
+	hops           *fyne.Container
+	hopPlaceholder string
+	strategy       *widget.Select
+	options        []string
+	onCreate       func(label string, hops []int, strategy string) error
+	container      *fyne.Container
+}
+
+// NewCombine creates the form, strategy selector is shown only if strategies are provided.
+func NewCombine(labelPlaceholder, hopPlaceholder, createBtnTitle string, strategies []string) *Combine {
+	f := &Combine{
+		errLabel:       &widget.Label{Importance: widget.DangerImportance, Wrapping: fyne.TextWrapWord},
+		label:          &widget.Entry{PlaceHolder: labelPlaceholder},
+		hops:           container.NewVBox(),
+		hopPlaceholder: hopPlaceholder,
+		strategy:       widget.NewSelect(strategies, nil),
+		onCreate:       func(string, []int, string) error { return nil },
+	}
+	f.errLabel.Hide()
+	if len(strategies) == 0 {
+		f.strategy.Hide()
+	}
+	f.reset()
+
+	addHopBtn := widget.NewButtonWithIcon("", theme.ContentAddIcon(), f.addHop)
+	createBtn := &widget.Button{
+		Text:       createBtnTitle,
+		Icon:       theme.ContentAddIcon(),
+		Importance: widget.HighImportance,
+		OnTapped:   f.submit,
+	}
+
+	f.container = container.NewVBox(
+		f.label,
+		f.strategy,
+		f.hops,
+		f.errLabel,
+		container.NewBorder(nil, nil, addHopBtn, createBtn),
+	)
+
+	return f
+}
+
+func (f *Combine) Container() *fyne.Container {
+	return f.container
+}
+
+// SetOptions sets items available as hops, OnCreate receives indexes of these options.
+func (f *Combine) SetOptions(options []string) {
+	f.options = options
+	for _, obj := range f.hops.Objects {
+		sel := obj.(*widget.Select)
+		selected := sel.SelectedIndex()
+		sel.SetOptions(options)
+		if selected >= len(options) {
+			sel.ClearSelected()
+		}
+	}
+}
+
+// OnCreate sets handler for form submission, hops are in the order they were added.
+func (f *Combine) OnCreate(fn func(label string, hops []int, strategy string) error) {
+	f.onCreate = fn
+}
+
+func (f *Combine) submit() {
+	hops := make([]int, 0, len(f.hops.Objects))
+	for _, obj := range f.hops.Objects {
+		if i := obj.(*widget.Select).SelectedIndex(); i >= 0 {
+			hops = append(hops, i)
+		}
+	}
+
+	if err := f.onCreate(f.label.Text, hops, f.strategy.Selected); err != nil {
+		f.errLabel.SetText(err.Error())
+		f.errLabel.Show()
+
+		return
+	}
+
+	f.errLabel.Hide()
+	f.reset()
+}
+
+func (f *Combine) addHop() {
+	sel := widget.NewSelect(f.options, nil)
+	sel.PlaceHolder = f.hopPlaceholder
+	f.hops.Add(sel)
+}
+
+// reset clears the form leaving two empty hops.
+func (f *Combine) reset() {
+	f.label.SetText("")
+	if len(f.strategy.Options) > 0 {
+		f.strategy.SetSelectedIndex(0)
+	}
+	f.hops.RemoveAll()
+	f.addHop()
+	f.addHop()
+}
@@ -0,0 +1,35 @@
+package form
+
+import (
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/widget"
+)
+
+// BindLinkDiagnostics highlights problems of the link while it is edited. The entry is marked invalid if diagnose
+// returns an error and all problems, warnings included, are listed in the label, it is hidden when there are none.
+func BindLinkDiagnostics(link *widget.Entry, problems *widget.Label, diagnose func(link string) ([]string, error)) {
+	problems.Wrapping = fyne.TextWrapWord
+	link.Validator = func(text string) error {
+		_, err := diagnose(text)
+
+		return err
+	}
+
+	onChanged := link.OnChanged
+	link.OnChanged = func(text string) {
+		if onChanged != nil {
+			onChanged(text)
+		}
+
+		found, _ := diagnose(text)
+		problems.SetText(strings.Join(found, "\n"))
+		if len(found) == 0 {
+			problems.Hide()
+		} else {
+			problems.Show()
+		}
+	}
+	link.OnChanged(link.Text)
+}
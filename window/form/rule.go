@@ -0,0 +1,87 @@
+package form
+
+import (
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+// Rule is a form to create rules that perform an action on a connection when condition is met.
+// The condition widgets are provided by the caller, the first action requires a connection to be selected.
+type Rule struct {
+	errLabel  *widget.Label
+	action    *widget.Select
+	item      *widget.Select
+	onCreate  func(action, item int) error
+	container *fyne.Container
+}
+
+func NewRule(condition fyne.CanvasObject, actions []string, itemPlaceholder, createBtnTitle string) *Rule {
+	f := &Rule{
+		errLabel: &widget.Label{Importance: widget.DangerImportance, Wrapping: fyne.TextWrapWord},
+		item:     &widget.Select{PlaceHolder: itemPlaceholder},
+		onCreate: func(int, int) error { return nil },
+	}
+	f.errLabel.Hide()
+	f.action = widget.NewSelect(actions, func(string) {
+		if f.action.SelectedIndex() == 0 {
+			f.item.Show()
+		} else {
+			f.item.Hide()
+		}
+	})
+	f.action.SetSelectedIndex(0)
+
+	createBtn := &widget.Button{
+		Text:       createBtnTitle,
+		Icon:       theme.ContentAddIcon(),
+		Importance: widget.HighImportance,
+		OnTapped:   f.submit,
+	}
+
+	f.container = container.NewVBox(
+		condition,
+		f.action,
+		f.item,
+		f.errLabel,
+		container.NewBorder(nil, nil, nil, createBtn),
+	)
+
+	return f
+}
+
+func (f *Rule) Container() *fyne.Container {
+	return f.container
+}
+
+// SetItems sets connections available for the first action, OnCreate receives index of these options.
+func (f *Rule) SetItems(options []string) {
+	selected := f.item.SelectedIndex()
+	f.item.SetOptions(options)
+	if selected >= len(options) {
+		f.item.ClearSelected()
+	}
+}
+
+// OnCreate sets handler for form submission, item is -1 if not selected or not needed by the action.
+func (f *Rule) OnCreate(fn func(action, item int) error) {
+	f.onCreate = fn
+}
+
+func (f *Rule) submit() {
+	item := -1
+	if f.action.SelectedIndex() == 0 {
+		item = f.item.SelectedIndex()
+	}
+
+	if err := f.onCreate(f.action.SelectedIndex(), item); err != nil {
+		f.errLabel.SetText(err.Error())
+		f.errLabel.Show()
+
+		return
+	}
+
+	f.errLabel.Hide()
+	f.item.ClearSelected()
+}
@@ -1,39 +1,55 @@
 package form
 
 import (
+	"errors"
+
 	"fyne.io/fyne/v2"
 	"fyne.io/fyne/v2/container"
 	"fyne.io/fyne/v2/theme"
 	"fyne.io/fyne/v2/widget"
 )
 
+// ErrPending is returned by OnUpdate handlers that finish later, e.g. after a confirmation. The result is then
+// reported with SetError.
+var ErrPending = errors.New("update is pending")
+
 type UpdateConfig struct {
 	errLabel           *widget.Label
 	onSubmit           func()
 	saveBtn, deleteBtn *widget.Button
+	editBtn            *widget.Button
 	newLabel, newLink  *widget.Entry
+	problems           *widget.Label
+	linkRow            *fyne.Container
 	container          *fyne.Container
 }
 
-func NewUpdateConfig(updateBtnTitle, deleteBtnTitle string) *UpdateConfig {
+func NewUpdateConfig(updateBtnTitle, deleteBtnTitle, editBtnTitle string) *UpdateConfig {
 	errLabel := &widget.Label{Text: "error", Importance: widget.DangerImportance}
 	errLabel.Hide()
 	newLabelInput := widget.NewEntry()
 	newLinkInput := widget.NewEntry()
+	problemsLabel := &widget.Label{Importance: widget.WarningImportance}
+	problemsLabel.Hide()
 
 	saveBtn := &widget.Button{Text: updateBtnTitle, Icon: theme.DocumentCreateIcon(), Importance: widget.HighImportance}
 	deleteBtn := &widget.Button{Text: deleteBtnTitle, Icon: theme.DeleteIcon(), Importance: widget.DangerImportance}
+	editBtn := &widget.Button{Text: editBtnTitle, Icon: theme.DocumentCreateIcon()}
+	linkRow := container.NewBorder(nil, nil, nil, editBtn, newLinkInput)
 
 	return &UpdateConfig{
 		errLabel:  errLabel,
 		saveBtn:   saveBtn,
 		deleteBtn: deleteBtn,
+		editBtn:   editBtn,
 		newLabel:  newLabelInput,
 		newLink:   newLinkInput,
+		problems:  problemsLabel,
+		linkRow:   linkRow,
 		onSubmit:  func() {},
 		container: container.NewVBox(
 			widget.NewSeparator(),
-			container.NewVBox(errLabel, newLabelInput, newLinkInput),
+			container.NewVBox(errLabel, newLabelInput, linkRow, problemsLabel),
 			container.NewBorder(nil, nil, nil, deleteBtn, saveBtn),
 		),
 	}
@@ -47,11 +63,13 @@ func (f *UpdateConfig) ToggleDisable(disable bool) {
 	if disable {
 		f.saveBtn.Disable()
 		f.deleteBtn.Disable()
+		f.editBtn.Disable()
 		f.newLabel.Disable()
 		f.newLink.Disable()
 	} else {
 		f.saveBtn.Enable()
 		f.deleteBtn.Enable()
+		f.editBtn.Enable()
 		f.newLabel.Enable()
 		f.newLink.Enable()
 	}
@@ -65,11 +83,29 @@ func (f *UpdateConfig) ToggleHide(hide bool) {
 	}
 }
 
+// HideLink hides link input, used for items that have no link of their own.
+func (f *UpdateConfig) HideLink(hide bool) {
+	if hide {
+		f.linkRow.Hide()
+		f.problems.Hide()
+	} else {
+		f.linkRow.Show()
+		if f.newLink.OnChanged != nil {
+			f.newLink.OnChanged(f.newLink.Text) // Problems are shown again if any.
+		}
+	}
+}
+
 func (f *UpdateConfig) SetInputs(label, link string) {
 	f.newLink.SetText(link)
 	f.newLabel.SetText(label)
 }
 
+// SetLink replaces the link input, e.g. with a link regenerated by an editor.
+func (f *UpdateConfig) SetLink(link string) {
+	f.newLink.SetText(link)
+}
+
 func (f *UpdateConfig) OnSubmit(fn func()) {
 	f.onSubmit = fn
 }
@@ -95,7 +131,9 @@ func (f *UpdateConfig) InputLink() string {
 
 func (f *UpdateConfig) OnUpdate(fn func() error) {
 	f.saveBtn.OnTapped = func() {
-		f.SetError(fn())
+		if err := fn(); !errors.Is(err, ErrPending) {
+			f.SetError(err)
+		}
 	}
 }
 
@@ -104,3 +142,13 @@ func (f *UpdateConfig) OnDelete(fn func() error) {
 		f.SetError(fn())
 	}
 }
+
+// OnEdit sets handler of the edit button, used to edit the link in a structured editor.
+func (f *UpdateConfig) OnEdit(fn func()) {
+	f.editBtn.OnTapped = fn
+}
+
+// SetLinkDiagnostics sets diagnostics of the link input, see BindLinkDiagnostics.
+func (f *UpdateConfig) SetLinkDiagnostics(diagnose func(link string) ([]string, error)) {
+	BindLinkDiagnostics(f.newLink, f.problems, diagnose)
+}
@@ -0,0 +1,46 @@
+package window
+
+import (
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/lang"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+// showVersionHistory lists previous versions of the item, onRollback is called after a version is restored.
+func (w *Settings[T]) showVersionHistory(item ListItem, onRollback func()) {
+	versions := w.versions.Versions(item.ID())
+	rows := container.NewVBox()
+	var d *dialog.CustomDialog
+	for i, v := range versions {
+		title := widget.NewLabelWithStyle(v.Time.Local().Format(time.DateTime)+"  "+v.Label, fyne.TextAlignLeading,
+			fyne.TextStyle{Bold: true})
+		link := &widget.Label{Text: v.Link, Importance: widget.LowImportance, Truncation: fyne.TextTruncateEllipsis}
+		link.Hidden = v.Link == ""
+		restore := widget.NewButtonWithIcon(lang.L("Restore"), theme.HistoryIcon(), func() {
+			if item.Active() {
+				dialog.ShowError(errChangeActiveItem, w.window)
+				return
+			}
+			if err := w.versions.Rollback(item.ID(), i); err != nil {
+				dialog.ShowError(err, w.window)
+				return
+			}
+			d.Hide()
+			onRollback()
+		})
+		rows.Add(container.NewBorder(nil, nil, nil, container.NewCenter(restore), container.NewVBox(title, link)))
+	}
+	if len(versions) == 0 {
+		rows.Add(widget.NewLabel(lang.L("No previous versions")))
+	}
+
+	d = dialog.NewCustom(lang.L("History of {{.Label}}", map[string]any{"Label": item.Label()}), lang.L("Close"),
+		container.NewVScroll(rows), w.window)
+	d.Resize(fyne.NewSize(600, 400))
+	d.Show()
+}
@@ -0,0 +1,120 @@
+package window
+
+import (
+	"fmt"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/lang"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+
+	"github.com/goxray/desktop/window/form"
+)
+
+// createNetworksContainer creates tab with rules to connect or disconnect depending on the current network.
+func (w *Settings[T]) createNetworksContainer() *fyne.Container {
+	current := widget.NewRichText()
+	refreshCurrent := func() {
+		network := w.networkRules.CurrentNetwork()
+		md := strings.Builder{}
+		for _, f := range networkFields {
+			val := network[f.name]
+			if val == "" {
+				val = "*" + lang.L("not detected") + "*"
+			}
+			md.WriteString(fmt.Sprintf("**%s:** %s\n\n", lang.L(f.title), val))
+		}
+		current.ParseMarkdown(md.String())
+	}
+	refreshCurrent()
+
+	rules := newRulesList(
+		func() []string {
+			all := w.networkRules.NetworkRules()
+			res := make([]string, 0, len(all))
+			for _, r := range all {
+				res = append(res, w.describeNetworkRule(r))
+			}
+
+			return res
+		},
+		w.networkRules.RemoveNetworkRule,
+	)
+
+	return container.NewBorder(nil, nil,
+		w.createNetworkRuleForm(rules.Refresh), nil,
+		container.NewBorder(nil, nil, widget.NewSeparator(), nil,
+			container.NewBorder(
+				container.NewVBox(
+					container.NewBorder(nil, nil, nil,
+						widget.NewButtonWithIcon("", theme.ViewRefreshIcon(), refreshCurrent),
+						widget.NewLabel(lang.L("Current network:"))),
+					current,
+					widget.NewSeparator(),
+					widget.NewLabel(lang.L("Rules (the first matching rule is applied):")),
+				),
+				nil, nil, nil, rules,
+			),
+		),
+	)
+}
+
+func (w *Settings[T]) createNetworkRuleForm(onCreated func()) *fyne.Container {
+	fieldTitles := make([]string, 0, len(networkFields))
+	for _, f := range networkFields {
+		fieldTitles = append(fieldTitles, lang.L(f.title))
+	}
+	field := widget.NewSelect(fieldTitles, nil)
+	field.SetSelectedIndex(0)
+	value := &widget.Entry{PlaceHolder: "192.168.1.1"}
+	useCurrent := widget.NewButtonWithIcon(lang.L("Use current"), theme.DownloadIcon(), func() {
+		value.SetText(w.networkRules.CurrentNetwork()[networkFields[field.SelectedIndex()].name])
+	})
+
+	actionTitles := make([]string, 0, len(ruleActions))
+	for _, a := range ruleActions {
+		actionTitles = append(actionTitles, lang.L(a.title))
+	}
+	ruleForm := form.NewRule(
+		container.NewVBox(field, container.NewBorder(nil, nil, nil, useCurrent, value)),
+		actionTitles, lang.L("Select connection"), lang.L("Add rule"),
+	)
+
+	items := w.bindItemOptions(ruleForm.SetItems)
+	ruleForm.OnCreate(func(action, item int) error {
+		rule := NetworkRule{
+			Field:  networkFields[field.SelectedIndex()].name,
+			Value:  value.Text,
+			Action: ruleActions[action].name,
+		}
+		if item >= 0 {
+			rule.ItemID = (*items)[item].ID()
+		}
+		if err := w.networkRules.AddNetworkRule(rule); err != nil {
+			return err
+		}
+
+		value.SetText("")
+		onCreated()
+
+		return nil
+	})
+
+	return container.NewVBox(
+		widget.NewLabel(lang.L("When the network matches")),
+		ruleForm.Container(),
+	)
+}
+
+func (w *Settings[T]) describeNetworkRule(rule NetworkRule) string {
+	field := rule.Field
+	for _, f := range networkFields {
+		if f.name == rule.Field {
+			field = lang.L(f.title)
+		}
+	}
+
+	return fmt.Sprintf("%s = %s → %s", field, rule.Value, w.describeRuleAction(rule.Action, rule.ItemID))
+}
@@ -0,0 +1,82 @@
+package window
+
+import (
+	"fmt"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/data/binding"
+	"fyne.io/fyne/v2/lang"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+// newRulesList creates list of rule descriptions with delete button for each rule.
+func newRulesList(describe func() []string, remove func(i int) error) *widget.List {
+	rules := widget.NewList(
+		func() int { return len(describe()) },
+		func() fyne.CanvasObject {
+			return container.NewBorder(nil, nil, nil,
+				widget.NewButtonWithIcon("", theme.DeleteIcon(), nil), widget.NewLabel("template"))
+		},
+		nil,
+	)
+	rules.UpdateItem = func(id widget.ListItemID, o fyne.CanvasObject) {
+		label := o.(*fyne.Container).Objects[0].(*widget.Label)
+		deleteBtn := o.(*fyne.Container).Objects[1].(*widget.Button)
+
+		all := describe()
+		if id >= len(all) {
+			return
+		}
+		label.SetText(all[id])
+		deleteBtn.OnTapped = func() {
+			if err := remove(id); err != nil {
+				fyne.LogError("remove rule", err)
+			}
+			rules.Refresh()
+		}
+	}
+
+	return rules
+}
+
+func (w *Settings[T]) describeRuleAction(action, itemID string) string {
+	title := action
+	for _, a := range ruleActions {
+		if a.name == action {
+			title = lang.L(a.title)
+		}
+	}
+	if itemID == "" {
+		return title
+	}
+
+	label := lang.L("deleted connection")
+	vals, _ := w.list.Get()
+	for _, v := range vals {
+		if item := v.(T); item.ID() == itemID {
+			label = item.Label()
+		}
+	}
+
+	return fmt.Sprintf("%s «%s»", title, label)
+}
+
+// bindItemOptions keeps options of setOptions in sync with the list, returns items matching the options.
+func (w *Settings[T]) bindItemOptions(setOptions func([]string)) *[]T {
+	items := make([]T, 0)
+	w.list.AddListener(binding.NewDataListener(func() {
+		vals, _ := w.list.Get()
+		items = items[:0]
+		options := make([]string, 0, len(vals))
+		for _, v := range vals {
+			item := v.(T)
+			items = append(items, item)
+			options = append(options, fmt.Sprintf("%s [%s]", item.Label(), item.XRayConfig()["Address"]))
+		}
+		setOptions(options)
+	}))
+
+	return &items
+}
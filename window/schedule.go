@@ -0,0 +1,112 @@
+package window
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/lang"
+	"fyne.io/fyne/v2/widget"
+
+	"github.com/goxray/desktop/window/form"
+)
+
+// createScheduleContainer creates tab with rules to connect or disconnect at the set time.
+func (w *Settings[T]) createScheduleContainer() *fyne.Container {
+	rules := newRulesList(
+		func() []string {
+			all := w.scheduleRules.ScheduleRules()
+			res := make([]string, 0, len(all))
+			for _, r := range all {
+				res = append(res, w.describeScheduleRule(r))
+			}
+
+			return res
+		},
+		w.scheduleRules.RemoveScheduleRule,
+	)
+
+	return container.NewBorder(nil, nil,
+		w.createScheduleRuleForm(rules.Refresh), nil,
+		container.NewBorder(nil, nil, widget.NewSeparator(), nil,
+			container.NewBorder(
+				container.NewVBox(widget.NewLabel(lang.L("Scheduled rules:")), widget.NewSeparator()),
+				nil, nil, nil, rules,
+			),
+		),
+	)
+}
+
+func (w *Settings[T]) createScheduleRuleForm(onCreated func()) *fyne.Container {
+	days := container.NewGridWithColumns(4)
+	for _, d := range weekdays {
+		days.Add(widget.NewCheck(lang.L(d.title), nil))
+	}
+	start := &widget.Entry{PlaceHolder: "09:00"}
+	end := &widget.Entry{PlaceHolder: lang.L("End (optional)")}
+
+	actionTitles := make([]string, 0, len(ruleActions))
+	for _, a := range ruleActions {
+		actionTitles = append(actionTitles, lang.L(a.title))
+	}
+	ruleForm := form.NewRule(
+		container.NewVBox(days, container.NewGridWithColumns(2, start, end)),
+		actionTitles, lang.L("Select connection"), lang.L("Add rule"),
+	)
+
+	items := w.bindItemOptions(ruleForm.SetItems)
+	ruleForm.OnCreate(func(action, item int) error {
+		rule := ScheduleRule{
+			Start:  strings.TrimSpace(start.Text),
+			End:    strings.TrimSpace(end.Text),
+			Action: ruleActions[action].name,
+		}
+		for i, obj := range days.Objects {
+			if obj.(*widget.Check).Checked {
+				rule.Days = append(rule.Days, weekdays[i].day)
+			}
+		}
+		if item >= 0 {
+			rule.ItemID = (*items)[item].ID()
+		}
+		if err := w.scheduleRules.AddScheduleRule(rule); err != nil {
+			return err
+		}
+
+		start.SetText("")
+		end.SetText("")
+		for _, obj := range days.Objects {
+			obj.(*widget.Check).SetChecked(false)
+		}
+		onCreated()
+
+		return nil
+	})
+
+	return container.NewVBox(
+		widget.NewLabel(lang.L("On days (every day if none)")),
+		ruleForm.Container(),
+	)
+}
+
+func (w *Settings[T]) describeScheduleRule(rule ScheduleRule) string {
+	days := lang.L("Every day")
+	if len(rule.Days) > 0 && len(rule.Days) < len(weekdays) {
+		titles := make([]string, 0, len(rule.Days))
+		for _, d := range weekdays {
+			if slices.Contains(rule.Days, d.day) {
+				titles = append(titles, lang.L(d.title))
+			}
+		}
+		days = strings.Join(titles, ", ")
+	}
+
+	at := rule.Start
+	if rule.End != "" {
+		at += "–" + rule.End
+	}
+
+	return fmt.Sprintf("%s %s → %s", days, at, w.describeRuleAction(rule.Action, rule.ItemID))
+}
@@ -0,0 +1,208 @@
+package window
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/lang"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+
+	customwidget "github.com/goxray/desktop/window/widget"
+)
+
+// sessionsRefreshInterval is how often the sessions table is updated while the window is open.
+const sessionsRefreshInterval = 5 * time.Second
+
+// sessionColumn is a column of the sessions table, title is a translation key.
+type sessionColumn struct {
+	title   string
+	width   float32
+	value   func(s Session) string
+	compare func(a, b Session) int
+}
+
+var sessionColumns = []sessionColumn{
+	{
+		title:   "Connection",
+		width:   140,
+		value:   func(s Session) string { return s.Label },
+		compare: func(a, b Session) int { return strings.Compare(a.Label, b.Label) },
+	},
+	{
+		title:   "Start",
+		width:   150,
+		value:   func(s Session) string { return s.Start.Local().Format(time.DateTime) },
+		compare: func(a, b Session) int { return a.Start.Compare(b.Start) },
+	},
+	{
+		title:   "Duration",
+		width:   90,
+		value:   func(s Session) string { return s.End.Sub(s.Start).Round(time.Second).String() },
+		compare: func(a, b Session) int { return cmp.Compare(a.End.Sub(a.Start), b.End.Sub(b.Start)) },
+	},
+	{
+		title:   "Upload",
+		width:   80,
+		value:   func(s Session) string { return customwidget.FormatBytes(s.Read) },
+		compare: func(a, b Session) int { return cmp.Compare(a.Read, b.Read) },
+	},
+	{
+		title:   "Download",
+		width:   80,
+		value:   func(s Session) string { return customwidget.FormatBytes(s.Written) },
+		compare: func(a, b Session) int { return cmp.Compare(a.Written, b.Written) },
+	},
+	{
+		title:   "Reason",
+		width:   90,
+		value:   func(s Session) string { return sessionReasonTitle(s.Reason) },
+		compare: func(a, b Session) int { return strings.Compare(a.Reason, b.Reason) },
+	},
+	{
+		title:   "Error",
+		width:   200,
+		value:   func(s Session) string { return s.Err },
+		compare: func(a, b Session) int { return strings.Compare(a.Err, b.Err) },
+	},
+}
+
+func sessionReasonTitle(reason string) string {
+	for _, r := range sessionReasons {
+		if r.name == reason {
+			return lang.L(r.title)
+		}
+	}
+
+	return reason
+}
+
+// createSessionsContainer creates tab with a table of ended sessions sortable by any column, the newest first by default.
+func (w *Settings[T]) createSessionsContainer() *fyne.Container {
+	var mu sync.Mutex // Guards rows and sorting, they are refreshed in background.
+	var rows []Session
+	sortColumn, descending := 1, true
+
+	table := widget.NewTable(
+		func() (int, int) {
+			mu.Lock()
+			defer mu.Unlock()
+			return len(rows), len(sessionColumns)
+		},
+		func() fyne.CanvasObject {
+			label := widget.NewLabel("")
+			label.Truncation = fyne.TextTruncateEllipsis
+			return label
+		},
+		func(id widget.TableCellID, o fyne.CanvasObject) {
+			mu.Lock()
+			defer mu.Unlock()
+			if id.Row < len(rows) {
+				o.(*widget.Label).SetText(sessionColumns[id.Col].value(rows[id.Row]))
+			}
+		},
+	)
+	// sortRows must be called with mu held.
+	sortRows := func() {
+		slices.SortStableFunc(rows, func(a, b Session) int {
+			if descending {
+				return sessionColumns[sortColumn].compare(b, a)
+			}
+			return sessionColumns[sortColumn].compare(a, b)
+		})
+	}
+	refresh := func() {
+		mu.Lock()
+		rows = w.sessions.Sessions()
+		sortRows()
+		mu.Unlock()
+		table.Refresh()
+	}
+
+	table.ShowHeaderRow = true
+	table.CreateHeader = func() fyne.CanvasObject {
+		return widget.NewButton("", nil)
+	}
+	table.UpdateHeader = func(id widget.TableCellID, o fyne.CanvasObject) {
+		mu.Lock()
+		defer mu.Unlock()
+		header := o.(*widget.Button)
+		header.SetText(lang.L(sessionColumns[id.Col].title))
+		header.SetIcon(nil)
+		if id.Col == sortColumn {
+			header.SetIcon(theme.MenuDropUpIcon())
+			if descending {
+				header.SetIcon(theme.MenuDropDownIcon())
+			}
+		}
+		header.OnTapped = func() {
+			mu.Lock()
+			if sortColumn == id.Col {
+				descending = !descending
+			} else {
+				sortColumn, descending = id.Col, false
+			}
+			sortRows()
+			mu.Unlock()
+			table.Refresh()
+		}
+	}
+	for i, c := range sessionColumns {
+		table.SetColumnWidth(i, c.width)
+	}
+	refresh()
+
+	go func() {
+		for {
+			select {
+			case <-w.ctx.Done():
+				return
+			case <-time.After(sessionsRefreshInterval):
+				refresh()
+			}
+		}
+	}()
+
+	export := widget.NewButtonWithIcon(lang.L("Export..."), theme.DocumentSaveIcon(), w.showSessionsExportDialog)
+
+	return container.NewBorder(nil, container.NewBorder(nil, nil, nil, export), nil, nil, table)
+}
+
+// showSessionsExportDialog asks for the format and saves all sessions to the chosen file.
+func (w *Settings[T]) showSessionsExportDialog() {
+	format := widget.NewRadioGroup(usageExportFormats, nil)
+	format.Horizontal = true
+	format.SetSelected(usageExportFormats[0])
+
+	dialog.ShowForm(lang.L("Export sessions"), lang.L("Export"), lang.L("Cancel"), []*widget.FormItem{
+		widget.NewFormItem(lang.L("Format"), format),
+	}, func(ok bool) {
+		if !ok {
+			return
+		}
+
+		save := dialog.NewFileSave(func(file fyne.URIWriteCloser, err error) {
+			if err != nil {
+				dialog.ShowError(err, w.window)
+				return
+			}
+			if file == nil { // Canceled.
+				return
+			}
+			defer file.Close()
+
+			if err := w.sessions.ExportSessions(file, format.Selected); err != nil {
+				dialog.ShowError(err, w.window)
+			}
+		}, w.window)
+		save.SetFileName(fmt.Sprintf("sessions.%s", format.Selected))
+		save.Show()
+	}, w.window)
+}
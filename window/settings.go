@@ -26,23 +26,53 @@ type Settings[T ListItem] struct {
 	window fyne.Window
 	list   binding.ExternalUntypedList
 
-	onAdd    func(data FormData) error
-	onUpdate func(FormData, T) error
-	onDelete func(T) error
-	onSwap   func(T, T) error
+	onAdd         func(data FormData) error
+	onAddChain    func(label string, hops []T) error
+	onAddBalancer func(label, strategy string, members []T) error
+	onUpdate      func(FormData, T) error
+	parseConfig   func(link string) (map[string]string, error)
+	onDelete      func(T) error
+	onSwap        func(T, T) error
+	networkRules  NetworkRules
+	scheduleRules ScheduleRules
+	usage         UsageStats
+	quotas        Quotas
+	subscriptions Subscriptions
+	metrics       MetricsEndpoint
+	events        ConnectionEvents
+	sessions      SessionHistory
+	tray          TraySettings
+	versions      VersionHistory
+	trash         Trash
 
 	ctx       context.Context
 	ctxCancel context.CancelFunc
 }
 
-func NewSettings[T ListItem](
-	a fyne.App,
-	list binding.ExternalUntypedList,
-	onAdd func(data FormData) error,
-	onUpdate func(FormData, T) error,
-	onDelete func(T) error,
-	onSwap func(T, T) error,
-) *Settings[T] {
+// SettingsDeps are handlers and services used by the settings window, all of them are required.
+type SettingsDeps[T ListItem] struct {
+	OnAdd         func(data FormData) error
+	OnAddChain    func(label string, hops []T) error
+	OnAddBalancer func(label, strategy string, members []T) error
+	OnUpdate      func(FormData, T) error
+	// ParseConfig should parse the link to the same config map as ListItem.XRayConfig.
+	ParseConfig   func(link string) (map[string]string, error)
+	OnDelete      func(T) error
+	OnSwap        func(T, T) error
+	NetworkRules  NetworkRules
+	ScheduleRules ScheduleRules
+	Usage         UsageStats
+	Quotas        Quotas
+	Subscriptions Subscriptions
+	Metrics       MetricsEndpoint
+	Events        ConnectionEvents
+	Sessions      SessionHistory
+	Tray          TraySettings
+	Versions      VersionHistory
+	Trash         Trash
+}
+
+func NewSettings[T ListItem](a fyne.App, list binding.ExternalUntypedList, deps SettingsDeps[T]) *Settings[T] {
 	w := a.NewWindow(lang.L("Settings"))
 	w.CenterOnScreen()
 	w.RequestFocus()
@@ -51,14 +81,28 @@ func NewSettings[T ListItem](
 	ctx, cancel := context.WithCancel(context.Background())
 
 	s := &Settings[T]{
-		window:    w,
-		onAdd:     onAdd,
-		onUpdate:  onUpdate,
-		onDelete:  onDelete,
-		onSwap:    onSwap,
-		list:      list,
-		ctx:       ctx,
-		ctxCancel: cancel,
+		window:        w,
+		onAdd:         deps.OnAdd,
+		onAddChain:    deps.OnAddChain,
+		onAddBalancer: deps.OnAddBalancer,
+		onUpdate:      deps.OnUpdate,
+		parseConfig:   deps.ParseConfig,
+		onDelete:      deps.OnDelete,
+		onSwap:        deps.OnSwap,
+		networkRules:  deps.NetworkRules,
+		scheduleRules: deps.ScheduleRules,
+		usage:         deps.Usage,
+		quotas:        deps.Quotas,
+		subscriptions: deps.Subscriptions,
+		metrics:       deps.Metrics,
+		events:        deps.Events,
+		sessions:      deps.Sessions,
+		tray:          deps.Tray,
+		versions:      deps.Versions,
+		trash:         deps.Trash,
+		list:          list,
+		ctx:           ctx,
+		ctxCancel:     cancel,
 	}
 	s.init()
 
@@ -95,11 +139,46 @@ func (w *Settings[T]) init() {
 	w.window.SetContent(content)
 
 	tabs := container.NewAppTabs(
+		container.NewTabItemWithIcon( // Aggregated traffic and events of all connections
+			lang.L("Dashboard"),
+			theme.HomeIcon(),
+			w.createDashboardContainer(),
+		),
 		container.NewTabItemWithIcon( // Connections list settings tab
 			lang.L("Configs"),
 			icon.Settings,
 			w.createSettingsContainer(),
 		),
+		container.NewTabItemWithIcon( // Provider subscriptions connections are imported from
+			lang.L("Subscriptions"),
+			theme.DownloadIcon(),
+			w.createSubscriptionsContainer(),
+		),
+		container.NewTabItemWithIcon( // Rules for trusted and untrusted networks
+			lang.L("Networks"),
+			theme.ComputerIcon(),
+			w.createNetworksContainer(),
+		),
+		container.NewTabItemWithIcon( // Time based rules
+			lang.L("Schedule"),
+			theme.HistoryIcon(),
+			w.createScheduleContainer(),
+		),
+		container.NewTabItemWithIcon( // Persisted traffic usage
+			lang.L("Usage"),
+			theme.StorageIcon(),
+			w.createUsageContainer(),
+		),
+		container.NewTabItemWithIcon( // Persisted history of connection sessions
+			lang.L("Sessions"),
+			theme.ListIcon(),
+			w.createSessionsContainer(),
+		),
+		container.NewTabItemWithIcon( // Deleted connections that can be restored
+			lang.L("Trash"),
+			theme.DeleteIcon(),
+			w.createTrashContainer(),
+		),
 		container.NewTabItemWithIcon( // About tab with static app info
 			lang.L("About"),
 			theme.QuestionIcon(),
@@ -142,6 +221,8 @@ func (w *Settings[T]) createAddForm() *fyne.Container {
 	inputLabel := &widget.Entry{PlaceHolder: lang.L("Display name")}
 	errLabel := &widget.Label{Importance: widget.DangerImportance}
 	errLabel.Hide()
+	problemsLabel := &widget.Label{Importance: widget.WarningImportance}
+	form.BindLinkDiagnostics(inputLink, problemsLabel, linkDiagnostics)
 
 	addBtn := &widget.Button{
 		Icon: theme.ContentAddIcon(),
@@ -149,24 +230,67 @@ func (w *Settings[T]) createAddForm() *fyne.Container {
 		OnTapped: func() {
 			data := FormData{Label: inputLabel.Text, Link: inputLink.Text}
 			if handleAddItem(data, errLabel, w.onAdd) {
-				inputLabel.Text = ""
-				inputLink.Text = ""
-				inputLabel.Refresh()
-				inputLink.Refresh()
+				inputLabel.SetText("")
+				inputLink.SetText("") // Also clears problems of the link.
 			}
 		},
 		Importance: widget.HighImportance,
 	}
+	newBtn := widget.NewButtonWithIcon(lang.L("New from scratch"), theme.DocumentCreateIcon(), func() {
+		w.showConfigEditor("", func(link, remark string) {
+			inputLink.SetText(link)
+			if inputLabel.Text == "" {
+				inputLabel.SetText(remark)
+			}
+		})
+	})
 
 	return container.NewVBox(
 		widget.NewLabel(lang.L("Insert your connection URL")),
 		inputLabel,
 		inputLink,
+		problemsLabel,
 		errLabel,
-		container.NewBorder(nil, nil, nil, addBtn), // Fit button to the right side
+		container.NewBorder(nil, nil, newBtn, addBtn), // Fit buttons to the sides
+		widget.NewSeparator(),
+		widget.NewAccordion(
+			widget.NewAccordionItem(lang.L("Chain connections"), w.createCombineForm(
+				lang.L("Select hop"), lang.L("Create chain"), nil,
+				func(label string, items []T, _ string) error { return w.onAddChain(label, items) },
+			).Container()),
+			widget.NewAccordionItem(lang.L("Balance connections"), w.createCombineForm(
+				lang.L("Select member"), lang.L("Create balancer"), balancerStrategies,
+				func(label string, items []T, strategy string) error { return w.onAddBalancer(label, strategy, items) },
+			).Container()),
+		),
 	)
 }
 
+// createCombineForm creates form to combine several list items into one, e.g. chain or balancer.
+func (w *Settings[T]) createCombineForm(
+	hopPlaceholder, createBtnTitle string,
+	strategies []string,
+	onCreate func(label string, items []T, strategy string) error,
+) *form.Combine {
+	combineForm := form.NewCombine(lang.L("Display name"), hopPlaceholder, createBtnTitle, strategies)
+
+	items := w.bindItemOptions(combineForm.SetOptions)
+	combineForm.OnCreate(func(label string, hops []int, strategy string) error {
+		if label == "" {
+			return errEmptyUpdateFormValue
+		}
+
+		selected := make([]T, 0, len(hops))
+		for _, i := range hops {
+			selected = append(selected, (*items)[i])
+		}
+
+		return onCreate(label, selected, strategy)
+	})
+
+	return combineForm
+}
+
 type HoverList struct {
 	fyne.CanvasObject
 	onMouseIn  func()
@@ -184,26 +308,60 @@ func (h *HoverList) MouseOut() {
 func (h *HoverList) MouseMoved(*desktop.MouseEvent) {}
 
 func (w *Settings[T]) createDynamicList() *fyne.Container {
-	updateForm := form.NewUpdateConfig(lang.L("Update"), lang.L("Delete"))
+	updateForm := form.NewUpdateConfig(lang.L("Update"), lang.L("Delete"), lang.L("Edit fields"))
+	updateForm.SetLinkDiagnostics(linkDiagnostics)
 	configInfoText := customwidget.NewTextWithCopy(w.window.Clipboard())
 
-	netStatsChart := container.NewWithoutLayout(&fyne.Container{})
+	netStatsChart := container.NewStack(&fyne.Container{})
+	chartRangeTitles := make([]string, 0, len(customwidget.ChartRanges))
+	for _, r := range customwidget.ChartRanges {
+		chartRangeTitles = append(chartRangeTitles, r.Title)
+	}
+	chartRange := widget.NewSelect(chartRangeTitles, nil)
+	chartRange.SetSelectedIndex(0)
+	exportChart := widget.NewButtonWithIcon(lang.L("Export chart"), theme.DocumentSaveIcon(), nil)
+	showHistory := widget.NewButtonWithIcon(lang.L("History"), theme.HistoryIcon(), nil)
+	memberStats := container.NewStack(&fyne.Container{}) // Per member stats for balancers.
+	findings := &widget.RichText{Wrapping: fyne.TextWrapWord}
 	itemSettings := container.NewBorder(
 		widget.NewSeparator(),
 		updateForm.Container(),
 		nil, nil,
-		container.NewBorder(nil, nil, netStatsChart, nil, configInfoText.Container()),
+		container.NewBorder(nil, nil, container.NewVBox(netStatsChart, container.NewHBox(chartRange, exportChart, showHistory)), nil,
+			container.NewBorder(memberStats, findings, nil, nil, configInfoText.Container())),
 	)
 	itemSettings.Hidden = true
+	undoBar, showUndo := w.createUndoNotification()
 
 	list := widget.NewListWithData(w.list, nil, nil)
 	list.HideSeparators = true
 
 	var selectedItem widget.ListItemID = -1
 	// Small caches to reuse sensitive widgets.
-	activeCharts := map[widget.ListItemID]*fyne.Container{}       // Cache for active live charts
-	renderedBadges := map[widget.ListItemID][]fyne.CanvasObject{} // Cache for badges
-	activeNetStats := map[widget.ListItemID]*fyne.Container{}     // Cache for net stats counters
+	activeCharts := map[widget.ListItemID]*customwidget.LiveNetworkChart{} // Cache for active live charts
+	renderedBadges := map[widget.ListItemID][]fyne.CanvasObject{}          // Cache for badges
+	activeNetStats := map[widget.ListItemID]*fyne.Container{}              // Cache for net stats counters
+	activeMembers := map[widget.ListItemID]*fyne.Container{}               // Cache for balancer members stats
+	chartRange.OnChanged = func(string) {
+		if chart, ok := activeCharts[selectedItem]; ok {
+			chart.SetSpan(customwidget.ChartRanges[chartRange.SelectedIndex()].Span)
+		}
+	}
+	exportChart.OnTapped = func() {
+		if selectedItem >= 0 {
+			w.showChartExportDialog(getListItem(w.list, selectedItem).(T), chartRange.SelectedIndex())
+		}
+	}
+	showHistory.OnTapped = func() {
+		if selectedItem < 0 {
+			return
+		}
+		val := getListItem(w.list, selectedItem)
+		w.showVersionHistory(val, func() {
+			renderedBadges[selectedItem] = createBadgesForVal(val)
+			list.UnselectAll()
+		})
+	}
 	swapItems := func(id1, id2 int) {
 		list.UnselectAll()
 		defer list.Refresh()
@@ -212,6 +370,7 @@ func (w *Settings[T]) createDynamicList() *fyne.Container {
 		activeCharts[id1], activeCharts[id2] = activeCharts[id2], activeCharts[id1]
 		activeNetStats[id1], activeNetStats[id2] = activeNetStats[id2], activeNetStats[id1]
 		renderedBadges[id1], renderedBadges[id2] = renderedBadges[id2], renderedBadges[id1]
+		activeMembers[id1], activeMembers[id2] = activeMembers[id2], activeMembers[id1]
 	}
 
 	list.CreateItem = func() fyne.CanvasObject {
@@ -282,7 +441,7 @@ func (w *Settings[T]) createDynamicList() *fyne.Container {
 		netStats.Objects = activeNetStats[id].Objects
 
 		if _, ok := activeCharts[id]; !ok {
-			activeCharts[id] = customwidget.NewLiveNetworkChart(w.ctx, " ● "+lang.L("upload"), "● "+lang.L("download"),
+			activeCharts[id] = customwidget.NewLiveNetworkChart(w.ctx, lang.L("upload"), lang.L("download"),
 				fyne.NewSize(250, 100), val)
 		}
 
@@ -290,7 +449,7 @@ func (w *Settings[T]) createDynamicList() *fyne.Container {
 			renderedBadges[id] = createBadgesForVal(val)
 		}
 
-		badges.Objects = renderedBadges[id]
+		badges.Objects = slices.Concat(renderedBadges[id], w.createQuotaBadges(val), w.createSubscriptionBadges(val))
 	}
 	list.OnUnselected = func(id widget.ListItemID) {
 		itemSettings.Hide()
@@ -302,10 +461,19 @@ func (w *Settings[T]) createDynamicList() *fyne.Container {
 
 		val := getListItem(w.list, id)
 
-		netStatsChart.Objects[0] = activeCharts[id]
+		activeCharts[id].SetSpan(customwidget.ChartRanges[chartRange.SelectedIndex()].Span)
+		netStatsChart.Objects[0] = activeCharts[id].Container()
 		configInfoText.ParseMarkdown(xrayConfigToStrings(val.XRayConfig()))
+		findings.ParseMarkdown(findingsMarkdown(auditItem(val)))
+		findings.Hidden = len(findings.Segments) == 0
+
+		if _, ok := activeMembers[id]; !ok {
+			activeMembers[id] = customwidget.NewLiveMemberStats(w.ctx, val)
+		}
+		memberStats.Objects[0] = activeMembers[id]
 
 		updateForm.ToggleHide(val.Active())
+		updateForm.HideLink(val.IsComposite())
 		updateForm.SetInputs(val.Label(), val.Link())
 		updateForm.OnUpdate(func() error {
 			// Update badges to reflect config changes in update.
@@ -317,34 +485,71 @@ func (w *Settings[T]) createDynamicList() *fyne.Container {
 				return errChangeActiveItem
 			}
 
+			if val.IsComposite() {
+				if data.Label == "" {
+					return errEmptyUpdateFormValue
+				}
+
+				return w.onUpdate(data, val.(T))
+			}
+
 			if err := data.Validate(); err != nil {
 				return err
 			}
+			if data.Link == val.Link() {
+				return w.onUpdate(data, val.(T))
+			}
+
+			// Show what changes in the config and update only when confirmed.
+			updated, err := w.parseConfig(data.Link)
+			if err != nil {
+				return err
+			}
+			diff := diffConfigs(val.XRayConfig(), updated)
+			if !diff.changed() {
+				return w.onUpdate(data, val.(T))
+			}
+			w.showConfigDiff(diff, func() {
+				defer func() { renderedBadges[id] = createBadgesForVal(val) }()
+				updateForm.SetError(w.onUpdate(data, val.(T)))
+			})
 
-			return w.onUpdate(data, val.(T))
+			return form.ErrPending
+		})
+		updateForm.OnEdit(func() {
+			w.showConfigEditor(updateForm.InputLink(), func(link, _ string) { updateForm.SetLink(link) })
 		})
 		updateForm.OnDelete(func() error {
 			if val.Active() {
 				return errChangeActiveItem
 			}
 
-			return w.onDelete(val.(T))
+			if err := w.onDelete(val.(T)); err != nil {
+				return err
+			}
+			showUndo(val.ID(), val.Label())
+
+			return nil
 		})
 		updateForm.OnSubmit(func() {
 			list.UnselectAll()
 		})
 	}
 
-	return container.NewBorder(nil, itemSettings, nil, nil, list)
+	return container.NewBorder(nil, container.NewVBox(undoBar, itemSettings), nil, nil, list)
 }
 
 // createBadgesForVal generates badges set for list value.
 func createBadgesForVal(val ListItem) []fyne.CanvasObject {
 	showTagsFor := []string{"Protocol", "TLS", "Flow"}
 	// Specify specific key:values that should be marked with different badge color.
+	// Security issues are marked by findings of the audit.
 	specialColors := map[string]map[string]color.Color{
-		// TLS none is a terrible security issue, mark it red.
-		"TLS": {"none": theme.Color(customtheme.ColorNameTextErrorMuted)},
+		// Chains and balancers are combined from other items, make them stand out.
+		"Protocol": {
+			"chain":    theme.Color(customtheme.ColorNameGraphBlue),
+			"balancer": theme.Color(customtheme.ColorNameGraphBlue),
+		},
 	}
 
 	badges := make([]fyne.CanvasObject, 0, len(showTagsFor))
@@ -361,7 +566,7 @@ func createBadgesForVal(val ListItem) []fyne.CanvasObject {
 		badges = append(badges, customwidget.NewBadge(val.XRayConfig()[tag], clr))
 	}
 
-	return badges
+	return append(badges, createFindingBadges(auditItem(val))...)
 }
 
 func handleAddItem(data FormData, errLabel *widget.Label, onAdd func(FormData) error) bool {
@@ -396,7 +601,7 @@ func getListItem(list binding.DataList, id widget.ListItemID) ListItem {
 func xrayConfigToStrings(x map[string]string) (md string, toCopy string) {
 	const separator = "separator"
 	includeOrder := []string{
-		"Address",
+		"Address", "Chain", "Members", "Strategy",
 		"Type", "TLS", "Protocol", "Port",
 		"ID", "Remark", "TlsFingerprint", "SNI",
 		"Security", "Aid", "Host", "Network", "Path", "ALPN", "Authority", "ServiceName", "Mode",
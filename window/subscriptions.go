@@ -0,0 +1,186 @@
+package window
+
+import (
+	"slices"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/lang"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+
+	customtheme "github.com/goxray/desktop/theme"
+	customwidget "github.com/goxray/desktop/window/widget"
+)
+
+// subscriptionsRefreshInterval is how often subscriptions are checked for changes while the window is open.
+const subscriptionsRefreshInterval = 2 * time.Second
+
+// createSubscriptionsContainer creates tab to add subscriptions and see their usage and expiry.
+func (w *Settings[T]) createSubscriptionsContainer() *fyne.Container {
+	rows := container.NewVBox()
+	var shown []Subscription
+	var refresh func(force bool)
+	refresh = func(force bool) {
+		subs := w.subscriptions.Subscriptions()
+		if !force && slices.Equal(subs, shown) {
+			return // Keep buttons under the cursor when nothing changed.
+		}
+		shown = subs
+		rows.Objects = w.createSubscriptionRows(subs, func() { refresh(false) })
+		rows.Refresh()
+	}
+	refresh(true)
+
+	inputName := &widget.Entry{PlaceHolder: lang.L("Display name")}
+	inputURL := &widget.Entry{PlaceHolder: "https://example.com/sub..."}
+	errLabel := &widget.Label{Importance: widget.DangerImportance, Wrapping: fyne.TextWrapWord}
+	errLabel.Hide()
+	addBtn := &widget.Button{Icon: theme.ContentAddIcon(), Text: lang.L("Add"), Importance: widget.HighImportance}
+	addBtn.OnTapped = func() {
+		addBtn.Disable()
+		go func() { // Downloading may take a while.
+			defer addBtn.Enable()
+			if err := w.subscriptions.AddSubscription(inputName.Text, inputURL.Text); err != nil {
+				errLabel.SetText(err.Error())
+				errLabel.Show()
+				return
+			}
+			errLabel.Hide()
+			inputName.SetText("")
+			inputURL.SetText("")
+			refresh(false)
+		}()
+	}
+
+	go func() {
+		for {
+			select {
+			case <-w.ctx.Done():
+				return
+			case <-time.After(subscriptionsRefreshInterval):
+				refresh(false)
+			}
+		}
+	}()
+
+	return container.NewBorder(
+		container.NewVBox(
+			widget.NewLabel(lang.L("Import connections from a subscription URL of your provider")),
+			container.NewBorder(nil, nil, nil, addBtn, container.NewGridWithColumns(2, inputName, inputURL)),
+			errLabel,
+			widget.NewSeparator(),
+		),
+		nil, nil, nil,
+		container.NewVScroll(rows),
+	)
+}
+
+// createSubscriptionRows creates a line with usage, expiry, refresh and remove buttons for each subscription.
+func (w *Settings[T]) createSubscriptionRows(subs []Subscription, onChanged func()) []fyne.CanvasObject {
+	rows := make([]fyne.CanvasObject, 0, len(subs))
+	for _, sub := range subs {
+		label := widget.NewLabelWithStyle(sub.Name, fyne.TextAlignLeading, fyne.TextStyle{Bold: true})
+		label.Truncation = fyne.TextTruncateEllipsis
+		details := &widget.Label{Text: subscriptionDetails(sub), Importance: widget.LowImportance,
+			Truncation: fyne.TextTruncateEllipsis}
+		status := container.NewHBox(subscriptionBadges(sub)...)
+
+		var usage fyne.CanvasObject = widget.NewLabel(subscriptionUsage(sub))
+		if sub.HasInfo && sub.Total > 0 {
+			bar := widget.NewProgressBar()
+			bar.TextFormatter = func() string { return subscriptionUsage(sub) }
+			bar.SetValue(min(float64(sub.Used)/float64(sub.Total), 1))
+			usage = bar
+		}
+
+		refresh := widget.NewButtonWithIcon(lang.L("Refresh"), theme.ViewRefreshIcon(), nil)
+		refresh.OnTapped = func() {
+			refresh.Disable()
+			go func() { // Downloading may take a while.
+				defer refresh.Enable()
+				if err := w.subscriptions.RefreshSubscription(sub.ID); err != nil {
+					dialog.ShowError(err, w.window)
+				}
+				onChanged()
+			}()
+		}
+		remove := widget.NewButtonWithIcon(lang.L("Remove"), theme.DeleteIcon(), func() {
+			dialog.ShowConfirm(lang.L("Remove subscription"),
+				lang.L("Remove subscription {{.Name}}? Its connections stay in the list.", map[string]any{"Name": sub.Name}),
+				func(ok bool) {
+					if !ok {
+						return
+					}
+					if err := w.subscriptions.RemoveSubscription(sub.ID); err != nil {
+						dialog.ShowError(err, w.window)
+					}
+					onChanged()
+				}, w.window)
+		})
+
+		rows = append(rows, container.NewBorder(nil, nil, nil, container.NewHBox(refresh, remove),
+			container.NewVBox(container.NewBorder(nil, nil, nil, status, label), usage, details)))
+	}
+	if len(rows) == 0 {
+		rows = append(rows, widget.NewLabel(lang.L("No subscriptions")))
+	}
+
+	return rows
+}
+
+// subscriptionUsage returns used traffic of the subscription out of its total.
+func subscriptionUsage(sub Subscription) string {
+	switch {
+	case !sub.HasInfo:
+		return lang.L("Usage is not reported by the provider")
+	case sub.Total <= 0:
+		return lang.L("{{.Used}} used, unlimited", map[string]any{"Used": customwidget.FormatBytes(sub.Used)})
+	}
+
+	return lang.L("{{.Used}} of {{.Limit}}", map[string]any{
+		"Used": customwidget.FormatBytes(sub.Used), "Limit": customwidget.FormatBytes(sub.Total)})
+}
+
+// subscriptionDetails returns expiry and the last update of the subscription.
+func subscriptionDetails(sub Subscription) string {
+	details := lang.L("{{.Count}} connections", map[string]any{"Count": sub.Items})
+	if sub.HasInfo && !sub.Expire.IsZero() {
+		expire := map[string]any{"Time": sub.Expire.Local().Format(time.DateTime)}
+		if sub.Expired {
+			details += ", " + lang.L("expired {{.Time}}", expire)
+		} else {
+			details += ", " + lang.L("valid until {{.Time}}", expire)
+		}
+	}
+	if !sub.UpdatedAt.IsZero() {
+		details += ", " + lang.L("updated {{.Time}}", map[string]any{"Time": sub.UpdatedAt.Local().Format(time.DateTime)})
+	}
+
+	return details
+}
+
+// subscriptionBadges returns badges of an expired or exhausted subscription.
+func subscriptionBadges(sub Subscription) []fyne.CanvasObject {
+	var badges []fyne.CanvasObject
+	if sub.Expired {
+		badges = append(badges, customwidget.NewBadge(lang.L("Expired"), theme.Color(customtheme.ColorNameTextErrorMuted)))
+	}
+	if sub.Exhausted {
+		badges = append(badges, customwidget.NewBadge(lang.L("Exhausted"), theme.Color(customtheme.ColorNameTextErrorMuted)))
+	}
+
+	return badges
+}
+
+// createSubscriptionBadges marks the item if its subscription is expired or exhausted.
+func (w *Settings[T]) createSubscriptionBadges(val ListItem) []fyne.CanvasObject {
+	sub, ok := w.subscriptions.ItemSubscription(val.ID())
+	if !ok {
+		return nil
+	}
+
+	return subscriptionBadges(sub)
+}
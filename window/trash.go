@@ -0,0 +1,150 @@
+package window
+
+import (
+	"slices"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/lang"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+const (
+	// trashRefreshInterval is how often the trash is checked for changes while the window is open.
+	trashRefreshInterval = 2 * time.Second
+	// undoTimeout is how long the undo notification is shown after an item is deleted.
+	undoTimeout = 10 * time.Second
+)
+
+// createTrashContainer creates tab with deleted items that can be restored or purged.
+func (w *Settings[T]) createTrashContainer() *fyne.Container {
+	rows := container.NewVBox()
+	var shown []TrashEntry
+	refresh := func(force bool) {
+		entries := w.trash.TrashEntries()
+		if !force && slices.Equal(entries, shown) {
+			return // Keep buttons under the cursor when nothing changed.
+		}
+		shown = entries
+		rows.Objects = w.createTrashRows(entries)
+		rows.Refresh()
+	}
+	refresh(true)
+
+	titles := make([]string, 0, len(trashExpiries))
+	selected := 0
+	for i, v := range trashExpiries {
+		titles = append(titles, lang.L(v.title))
+		if v.age == w.trash.TrashExpiry() {
+			selected = i
+		}
+	}
+	expiry := widget.NewSelect(titles, nil)
+	expiry.SetSelectedIndex(selected)
+	expiry.OnChanged = func(string) {
+		w.trash.SetTrashExpiry(trashExpiries[expiry.SelectedIndex()].age)
+		refresh(true) // Expiry dates changed.
+	}
+
+	empty := widget.NewButtonWithIcon(lang.L("Empty trash"), theme.DeleteIcon(), func() {
+		dialog.ShowConfirm(lang.L("Empty trash"), lang.L("Delete all connections in the trash permanently?"),
+			func(ok bool) {
+				if ok {
+					w.trash.PurgeAll()
+					refresh(false)
+				}
+			}, w.window)
+	})
+	empty.Importance = widget.DangerImportance
+
+	go func() {
+		for {
+			select {
+			case <-w.ctx.Done():
+				return
+			case <-time.After(trashRefreshInterval):
+				refresh(false)
+			}
+		}
+	}()
+
+	return container.NewBorder(
+		container.NewVBox(
+			container.NewBorder(nil, nil,
+				container.NewHBox(widget.NewLabel(lang.L("Delete from trash")), expiry), empty),
+			widget.NewSeparator(),
+		),
+		nil, nil, nil,
+		container.NewVScroll(rows),
+	)
+}
+
+// createTrashRows creates a line with restore and purge buttons for each deleted item.
+func (w *Settings[T]) createTrashRows(entries []TrashEntry) []fyne.CanvasObject {
+	rows := make([]fyne.CanvasObject, 0, len(entries))
+	for _, e := range entries {
+		details := lang.L("Deleted {{.Time}}", map[string]any{"Time": e.DeletedAt.Local().Format(time.DateTime)})
+		if !e.ExpiresAt.IsZero() {
+			details += ", " + lang.L("expires {{.Time}}", map[string]any{"Time": e.ExpiresAt.Local().Format(time.DateTime)})
+		}
+		label := widget.NewLabelWithStyle(e.Label, fyne.TextAlignLeading, fyne.TextStyle{Bold: true})
+		label.Truncation = fyne.TextTruncateEllipsis
+
+		restore := widget.NewButtonWithIcon(lang.L("Restore"), theme.ContentUndoIcon(), func() {
+			if err := w.trash.Restore(e.ID); err != nil {
+				dialog.ShowError(err, w.window)
+			}
+		})
+		purge := widget.NewButtonWithIcon(lang.L("Delete permanently"), theme.DeleteIcon(), func() {
+			dialog.ShowConfirm(lang.L("Delete permanently"),
+				lang.L("Delete {{.Label}} permanently?", map[string]any{"Label": e.Label}), func(ok bool) {
+					if !ok {
+						return
+					}
+					if err := w.trash.Purge(e.ID); err != nil {
+						dialog.ShowError(err, w.window)
+					}
+				}, w.window)
+		})
+
+		rows = append(rows, container.NewBorder(nil, nil, nil, container.NewHBox(restore, purge),
+			container.NewVBox(label, &widget.Label{Text: details, Importance: widget.LowImportance})))
+	}
+	if len(rows) == 0 {
+		rows = append(rows, widget.NewLabel(lang.L("Trash is empty")))
+	}
+
+	return rows
+}
+
+// createUndoNotification creates a hidden bar that offers to restore the last deleted item for undoTimeout.
+// The returned function shows the bar for the item.
+func (w *Settings[T]) createUndoNotification() (*fyne.Container, func(id, label string)) {
+	text := widget.NewLabel("")
+	text.Truncation = fyne.TextTruncateEllipsis
+	undo := widget.NewButtonWithIcon(lang.L("Undo"), theme.ContentUndoIcon(), nil)
+	undo.Importance = widget.HighImportance
+	bar := container.NewBorder(widget.NewSeparator(), nil, nil, undo, text)
+	bar.Hide()
+
+	var timer *time.Timer
+	show := func(id, label string) {
+		if timer != nil {
+			timer.Stop()
+		}
+		text.SetText(lang.L("{{.Label}} moved to the trash", map[string]any{"Label": label}))
+		undo.OnTapped = func() {
+			bar.Hide()
+			if err := w.trash.Restore(id); err != nil {
+				dialog.ShowError(err, w.window)
+			}
+		}
+		bar.Show()
+		timer = time.AfterFunc(undoTimeout, bar.Hide)
+	}
+
+	return bar, show
+}
@@ -0,0 +1,32 @@
+package window
+
+import (
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/lang"
+	"fyne.io/fyne/v2/widget"
+)
+
+// createTraySettings creates form with settings of the tray icon.
+func (w *Settings[T]) createTraySettings() *fyne.Container {
+	titles := make([]string, 0, len(trayStatusIntervals))
+	selected := 0
+	for i, v := range trayStatusIntervals {
+		titles = append(titles, lang.L(v.title))
+		if v.interval == w.tray.TrayStatusInterval() {
+			selected = i
+		}
+	}
+
+	interval := widget.NewSelect(titles, nil)
+	interval.SetSelectedIndex(selected)
+	interval.OnChanged = func(string) {
+		w.tray.SetTrayStatusInterval(trayStatusIntervals[interval.SelectedIndex()].interval)
+	}
+
+	graph := widget.NewCheck(lang.L("Traffic graph in the tray icon"), nil)
+	graph.SetChecked(w.tray.TrayIconGraph())
+	graph.OnChanged = w.tray.SetTrayIconGraph
+
+	return container.NewHBox(widget.NewLabel(lang.L("Speed in the tray tooltip")), interval, graph)
+}
@@ -0,0 +1,272 @@
+package window
+
+import (
+	"fmt"
+	"image/color"
+	"strconv"
+	"strings"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/lang"
+	"fyne.io/fyne/v2/layout"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+
+	customtheme "github.com/goxray/desktop/theme"
+	customwidget "github.com/goxray/desktop/window/widget"
+)
+
+const (
+	// usageDays is number of days shown in the daily usage chart.
+	usageDays = 30
+	// usageRefreshInterval is how often the usage tab is updated while the window is open.
+	usageRefreshInterval = 5 * time.Second
+	bytesInGB            = 1e9
+)
+
+// defaultQuotaThresholds are suggested for a new quota.
+var defaultQuotaThresholds = []int{80, 95}
+
+// createUsageContainer creates tab with persisted traffic usage of the selected connection.
+func (w *Settings[T]) createUsageContainer() *fyne.Container {
+	selectItem := widget.NewSelect(nil, nil)
+	selectItem.PlaceHolder = lang.L("Select connection")
+	items := w.bindItemOptions(selectItem.SetOptions)
+
+	totals := widget.NewRichText()
+	quotaBar := widget.NewProgressBar()
+	quotaBar.Hide()
+	bars := customwidget.NewUsageBars(fyne.NewSize(600, 150))
+	firstDay := widget.NewLabel("")
+
+	selected := func() (T, bool) {
+		i := selectItem.SelectedIndex()
+		if i < 0 || i >= len(*items) {
+			var empty T
+			return empty, false
+		}
+
+		return (*items)[i], true
+	}
+	refresh := func() {
+		item, ok := selected()
+		if !ok {
+			totals.ParseMarkdown("")
+			quotaBar.Hide()
+			bars.Update(nil, nil)
+			return
+		}
+
+		id, now := item.ID(), time.Now()
+		dailyRead, dailyWritten := w.usage.Daily(id, now, usageDays)
+		todayRead, todayWritten := dailyRead[usageDays-1], dailyWritten[usageDays-1]
+		monthRead, monthWritten := w.usage.Month(id, now)
+		lifetimeRead, lifetimeWritten := w.usage.Lifetime(id)
+
+		totals.ParseMarkdown(fmt.Sprintf(
+			"**%s:** ↑%s ↓%s\n\n**%s:** ↑%s ↓%s\n\n**%s:** ↑%s ↓%s",
+			lang.L("Today"), customwidget.FormatBytes(todayRead), customwidget.FormatBytes(todayWritten),
+			lang.L("This month"), customwidget.FormatBytes(monthRead), customwidget.FormatBytes(monthWritten),
+			lang.L("All time"), customwidget.FormatBytes(lifetimeRead), customwidget.FormatBytes(lifetimeWritten),
+		))
+		bars.Update(dailyRead, dailyWritten)
+
+		q, hasQuota := w.quotas.Quota(id)
+		used, _ := w.quotas.QuotaUsage(id)
+		quotaBar.Hidden = !hasQuota
+		if hasQuota {
+			quotaBar.TextFormatter = func() string {
+				return lang.L("{{.Used}} of {{.Limit}} in this billing cycle", map[string]any{
+					"Used": customwidget.FormatBytes(used), "Limit": customwidget.FormatBytes(q.Limit)})
+			}
+			quotaBar.SetValue(min(float64(used)/float64(q.Limit), 1))
+		}
+		firstDay.SetText(now.AddDate(0, 0, 1-usageDays).Format(time.DateOnly))
+	}
+	quotaForm, loadQuota := w.createQuotaForm(selected, refresh)
+	selectItem.OnChanged = func(string) {
+		loadQuota()
+		refresh()
+	}
+	refresh()
+
+	go func() {
+		for {
+			select {
+			case <-w.ctx.Done():
+				return
+			case <-time.After(usageRefreshInterval):
+				refresh()
+			}
+		}
+	}()
+
+	return container.NewBorder(nil, nil, nil,
+		container.NewHBox(widget.NewSeparator(), container.NewVBox(quotaForm, widget.NewSeparator(), w.createMetricsToggle())),
+		container.NewVBox(
+			selectItem,
+			totals,
+			quotaBar,
+			widget.NewSeparator(),
+			widget.NewLabel(lang.L("Daily usage for the last 30 days:")),
+			container.NewCenter(bars.Container()),
+			container.NewHBox(firstDay, layout.NewSpacer(), widget.NewLabel(lang.L("Today"))),
+			container.NewBorder(nil, nil, nil, widget.NewButtonWithIcon(lang.L("Export..."), theme.DocumentSaveIcon(), func() {
+				if item, ok := selected(); ok {
+					w.showUsageExportDialog(item)
+				}
+			})),
+		),
+	)
+}
+
+// createQuotaForm creates form to edit quota of the selected item, load fills the form for the newly selected item.
+func (w *Settings[T]) createQuotaForm(selected func() (T, bool), onChanged func()) (*fyne.Container, func()) {
+	limit := &widget.Entry{PlaceHolder: lang.L("Limit, GB")}
+	resetDay := &widget.Entry{PlaceHolder: lang.L("Reset day of month")}
+	thresholds := &widget.Entry{PlaceHolder: lang.L("Warn at, %")}
+	autoDisconnect := widget.NewCheck(lang.L("Disconnect when exceeded"), nil)
+	errLabel := &widget.Label{Importance: widget.DangerImportance, Wrapping: fyne.TextWrapWord}
+	errLabel.Hide()
+
+	load := func() {
+		errLabel.Hide()
+		item, ok := selected()
+		if !ok {
+			return
+		}
+		q, ok := w.quotas.Quota(item.ID())
+		if !ok {
+			q = Quota{ResetDay: 1, Thresholds: defaultQuotaThresholds}
+			limit.SetText("")
+		} else {
+			limit.SetText(strconv.FormatFloat(float64(q.Limit)/bytesInGB, 'f', -1, 64))
+		}
+		resetDay.SetText(strconv.Itoa(q.ResetDay))
+		percents := make([]string, 0, len(q.Thresholds))
+		for _, t := range q.Thresholds {
+			percents = append(percents, strconv.Itoa(t))
+		}
+		thresholds.SetText(strings.Join(percents, ", "))
+		autoDisconnect.SetChecked(q.AutoDisconnect)
+	}
+
+	showErr := func(err error) {
+		if err == nil {
+			errLabel.Hide()
+			onChanged()
+			return
+		}
+		errLabel.SetText(err.Error())
+		errLabel.Show()
+	}
+	save := &widget.Button{
+		Icon: theme.DocumentSaveIcon(),
+		Text: lang.L("Save"),
+		OnTapped: func() {
+			item, ok := selected()
+			if !ok {
+				showErr(errNoItemSelected)
+				return
+			}
+			q, err := parseQuota(limit.Text, resetDay.Text, thresholds.Text)
+			if err != nil {
+				showErr(err)
+				return
+			}
+			q.AutoDisconnect = autoDisconnect.Checked
+			showErr(w.quotas.SetQuota(item.ID(), q))
+		},
+		Importance: widget.HighImportance,
+	}
+	remove := widget.NewButtonWithIcon(lang.L("Remove"), theme.DeleteIcon(), func() {
+		if item, ok := selected(); ok {
+			w.quotas.RemoveQuota(item.ID())
+			load()
+			onChanged()
+		}
+	})
+
+	return container.NewVBox(
+		widget.NewLabel(lang.L("Traffic quota")),
+		limit,
+		resetDay,
+		thresholds,
+		autoDisconnect,
+		errLabel,
+		container.NewBorder(nil, nil, remove, save),
+	), load
+}
+
+// parseQuota parses quota form values: limit in GB, reset day and comma separated thresholds in percent.
+func parseQuota(limit, resetDay, thresholds string) (Quota, error) {
+	gb, err := strconv.ParseFloat(strings.TrimSpace(limit), 64)
+	if err != nil {
+		return Quota{}, fmt.Errorf("%w: %q", errInvalidNumber, limit)
+	}
+	day, err := strconv.Atoi(strings.TrimSpace(resetDay))
+	if err != nil {
+		return Quota{}, fmt.Errorf("%w: %q", errInvalidNumber, resetDay)
+	}
+
+	q := Quota{Limit: int64(gb * bytesInGB), ResetDay: day}
+	for _, t := range strings.Split(thresholds, ",") {
+		if t = strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(t), "%")); t == "" {
+			continue
+		}
+		percent, err := strconv.Atoi(t)
+		if err != nil {
+			return Quota{}, fmt.Errorf("%w: %q", errInvalidNumber, t)
+		}
+		q.Thresholds = append(q.Thresholds, percent)
+	}
+
+	return q, nil
+}
+
+// createQuotaBadges returns badge with used percent of the quota once its first threshold is reached.
+func (w *Settings[T]) createQuotaBadges(val ListItem) []fyne.CanvasObject {
+	q, ok := w.quotas.Quota(val.ID())
+	if !ok || q.Limit <= 0 {
+		return nil
+	}
+	used, _ := w.quotas.QuotaUsage(val.ID())
+
+	percent := int(used * 100 / q.Limit)
+	var clr color.Color
+	switch {
+	case percent >= 100:
+		clr = theme.Color(customtheme.ColorNameTextErrorMuted)
+	case len(q.Thresholds) > 0 && percent >= q.Thresholds[0]:
+		clr = theme.Color(theme.ColorNameWarning)
+	default:
+		return nil
+	}
+
+	return []fyne.CanvasObject{customwidget.NewBadge(fmt.Sprintf("%d%%", percent), clr)}
+}
+
+// createMetricsToggle creates check to enable the local Prometheus metrics endpoint.
+func (w *Settings[T]) createMetricsToggle() *fyne.Container {
+	errLabel := &widget.Label{Importance: widget.DangerImportance, Wrapping: fyne.TextWrapWord}
+	errLabel.Hide()
+	url := widget.NewLabel(w.metrics.MetricsURL())
+	url.Hidden = !w.metrics.MetricsEnabled()
+
+	toggle := widget.NewCheck(lang.L("Serve Prometheus metrics"), nil)
+	toggle.SetChecked(w.metrics.MetricsEnabled())
+	toggle.OnChanged = func(enabled bool) {
+		if err := w.metrics.SetMetricsEnabled(enabled); err != nil {
+			errLabel.SetText(err.Error())
+			errLabel.Show()
+		} else {
+			errLabel.Hide()
+		}
+		url.Hidden = !w.metrics.MetricsEnabled()
+		url.Refresh()
+	}
+
+	return container.NewVBox(toggle, url, errLabel)
+}
@@ -0,0 +1,107 @@
+package window
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/lang"
+	"fyne.io/fyne/v2/widget"
+
+	customwidget "github.com/goxray/desktop/window/widget"
+)
+
+// showUsageExportDialog asks what traffic of item to export and saves it to the chosen file.
+func (w *Settings[T]) showUsageExportDialog(item T) {
+	sourceTitles := make([]string, 0, len(usageExportSources))
+	for _, s := range usageExportSources {
+		sourceTitles = append(sourceTitles, lang.L(s.title))
+	}
+	source := widget.NewSelect(sourceTitles, nil)
+
+	now := time.Now()
+	from := widget.NewEntry()
+	from.SetText(now.AddDate(0, 0, 1-usageDays).Format(time.DateOnly))
+	to := widget.NewEntry()
+	to.SetText(now.Format(time.DateOnly))
+
+	rangeTitles := make([]string, 0, len(customwidget.ChartRanges))
+	for _, r := range customwidget.ChartRanges {
+		rangeTitles = append(rangeTitles, r.Title)
+	}
+	span := widget.NewSelect(rangeTitles, nil)
+	span.SetSelectedIndex(len(rangeTitles) - 1)
+
+	format := widget.NewRadioGroup(usageExportFormats, nil)
+	format.Horizontal = true
+	format.SetSelected(usageExportFormats[0])
+
+	source.OnChanged = func(string) {
+		live := usageExportSources[source.SelectedIndex()].name == "live"
+		for _, e := range []*widget.Entry{from, to} {
+			if live {
+				e.Disable()
+			} else {
+				e.Enable()
+			}
+		}
+		if live {
+			span.Enable()
+		} else {
+			span.Disable()
+		}
+	}
+	source.SetSelectedIndex(0)
+
+	dialog.ShowForm(lang.L("Export traffic"), lang.L("Export"), lang.L("Cancel"), []*widget.FormItem{
+		widget.NewFormItem(lang.L("Data"), source),
+		widget.NewFormItem(lang.L("From"), from),
+		widget.NewFormItem(lang.L("To"), to),
+		widget.NewFormItem(lang.L("Range"), span),
+		widget.NewFormItem(lang.L("Format"), format),
+	}, func(ok bool) {
+		if !ok {
+			return
+		}
+
+		req := UsageExport{
+			ItemID: item.ID(),
+			Source: usageExportSources[source.SelectedIndex()].name,
+			Span:   customwidget.ChartRanges[span.SelectedIndex()].Span,
+			Format: format.Selected,
+		}
+		var err error
+		if req.From, err = time.ParseInLocation(time.DateOnly, strings.TrimSpace(from.Text), time.Local); err != nil {
+			dialog.ShowError(fmt.Errorf("%w: %q", errInvalidDate, from.Text), w.window)
+			return
+		}
+		if req.To, err = time.ParseInLocation(time.DateOnly, strings.TrimSpace(to.Text), time.Local); err != nil {
+			dialog.ShowError(fmt.Errorf("%w: %q", errInvalidDate, to.Text), w.window)
+			return
+		}
+
+		w.saveUsageExport(item.Label(), req)
+	}, w.window)
+}
+
+// saveUsageExport asks for a file and writes the export to it.
+func (w *Settings[T]) saveUsageExport(label string, req UsageExport) {
+	save := dialog.NewFileSave(func(file fyne.URIWriteCloser, err error) {
+		if err != nil {
+			dialog.ShowError(err, w.window)
+			return
+		}
+		if file == nil { // Canceled.
+			return
+		}
+		defer file.Close()
+
+		if err := w.usage.ExportUsage(file, req); err != nil {
+			dialog.ShowError(err, w.window)
+		}
+	}, w.window)
+	save.SetFileName(fmt.Sprintf("traffic-%s-%s.%s", label, req.Source, req.Format))
+	save.Show()
+}
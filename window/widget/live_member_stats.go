@@ -0,0 +1,68 @@
+package widget
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/data/binding"
+	"fyne.io/fyne/v2/lang"
+	"fyne.io/fyne/v2/theme"
+
+	customtheme "github.com/goxray/desktop/theme"
+)
+
+type MemberStats interface {
+	// Members returns labels of the members.
+	Members() []string
+	// MemberTraffic returns number of bytes for uplink and downlink of the i-th member.
+	MemberTraffic(i int) (read int, written int)
+	// MemberHealth returns the last health check result of the i-th member, observed is false if not checked yet.
+	MemberHealth(i int) (observed, alive bool, delay time.Duration)
+	// Updates should notify listeners when the stats change.
+	Updates() binding.DataItem
+}
+
+// NewLiveMemberStats creates container with traffic and health status for each member of the source,
+// updated on source updates till ctx is done.
+func NewLiveMemberStats(ctx context.Context, source MemberStats) *fyne.Container {
+	cnt := container.NewVBox()
+	mutedColor := theme.Color(customtheme.ColorNameTextMuted)
+
+	prev := ""
+	listen(ctx, source.Updates(), func() {
+		rows := make([]fyne.CanvasObject, 0, len(source.Members()))
+		current := ""
+		for i, label := range source.Members() {
+			read, written := source.MemberTraffic(i)
+			row := fmt.Sprintf("%s ↑%s ↓%s %s", label,
+				bytesToHumanFriendlyString(read), bytesToHumanFriendlyString(written), memberHealth(source, i))
+			current += row
+			rows = append(rows, canvas.NewText(row, mutedColor))
+		}
+
+		// Small optimization to not rerender widget if no changes occurred to the values.
+		if current != prev {
+			prev = current
+			cnt.Objects = rows
+			cnt.Refresh()
+		}
+	})
+
+	return cnt
+}
+
+func memberHealth(source MemberStats, i int) string {
+	observed, alive, delay := source.MemberHealth(i)
+	switch {
+	case !observed:
+		return ""
+	case !alive:
+		return "● " + lang.L("down")
+	}
+
+	return fmt.Sprintf("● %d %s", delay.Milliseconds(), lang.L("ms"))
+}
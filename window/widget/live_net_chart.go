@@ -2,17 +2,39 @@ package widget
 
 import (
 	"context"
-	"image/color"
+	"io"
 	"log/slog"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/data/binding"
 	"fyne.io/fyne/v2/theme"
 
 	"github.com/goxray/desktop/internal/netchart"
 	customtheme "github.com/goxray/desktop/theme"
 )
 
+// maxChartPoints limits number of points drawn, longer ranges are downsampled.
+const maxChartPoints = 120
+
+// ChartRange is a selectable time range of the network chart.
+type ChartRange struct {
+	Title string
+	Span  time.Duration
+}
+
+// ChartRanges are ranges offered to the user, the first one is the default.
+var ChartRanges = []ChartRange{
+	{Title: "1m", Span: time.Minute},
+	{Title: "10m", Span: 10 * time.Minute},
+	{Title: "1h", Span: time.Hour},
+	{Title: "24h", Span: 24 * time.Hour},
+	{Title: "30d", Span: 30 * 24 * time.Hour},
+}
+
 type Recorder interface {
 	// Read should return values for uplink for each previous RecordInterval.
 	// Number of values returned must match Written.
@@ -21,79 +43,98 @@ type Recorder interface {
 	// Number of values returned must match Written.
 	Written() []float64
 	RecordInterval() time.Duration
+	// Range should return uplink and downlink values covering the last span and their interval.
+	Range(span time.Duration) (read, written []float64, interval time.Duration)
+	// Unit should return the unit of recorded values.
+	Unit() netchart.Unit
+	// Updates should notify listeners when new values are recorded.
+	Updates() binding.DataItem
 }
 
-// NewLiveNetworkChart creates new chart container with automatically updated net statistics from recorder.
-//
-// This method spawns a new goroutine to update the chart in background, so be sure to close the ctx when you are done.
-func NewLiveNetworkChart(ctx context.Context, upLabel, downLabel string, size fyne.Size, recorder Recorder) *fyne.Container {
-	data := map[string][]float64{upLabel: {}, downLabel: {}}
-	r, g, b, _ := theme.Color(customtheme.ColorNameGraphGreen).RGBA()
-	colorGreen := color.RGBA{R: uint8(r), G: uint8(g), B: uint8(b), A: 255}
-	r, g, b, _ = theme.Color(customtheme.ColorNameGraphBlue).RGBA()
-	colorBlue := color.RGBA{R: uint8(r), G: uint8(g), B: uint8(b), A: 255}
-	colors := map[string]color.RGBA{upLabel: colorGreen, downLabel: colorBlue}
+// LiveNetworkChart is a chart of recorder values for the selected range.
+type LiveNetworkChart struct {
+	container *fyne.Container
+	span      atomic.Int64
 
-	chart := netchart.New(float64(size.Width), float64(size.Height), 0.6)
+	mu   sync.Mutex
+	draw func(forced bool) // Must be called with mu held.
+}
+
+// NewLiveNetworkChart creates new chart with net statistics from recorder, updated on recorder updates till ctx is done.
+func NewLiveNetworkChart(ctx context.Context, upLabel, downLabel string, size fyne.Size, recorder Recorder) *LiveNetworkChart {
+	chart := netchart.New(size, recorder.Unit())
+	live := &LiveNetworkChart{container: container.NewStack(chart)}
+	live.span.Store(int64(ChartRanges[0].Span))
 
 	ctx, cancel := context.WithCancel(ctx)
-	updateChart := func() {
-		if err := chart.UpdateNamed(data, colors, []string{upLabel, downLabel}); err != nil {
+	// update loads data for the current range into the chart and returns how often it changes.
+	update := func() time.Duration {
+		interval, err := updateNetworkChart(chart, upLabel, downLabel, time.Duration(live.span.Load()), recorder)
+		if err != nil {
 			slog.Error(err.Error())
 			cancel()
 		}
+
+		return max(interval, recorder.RecordInterval())
 	}
 
-	// Initialize the chart with initial data.
-	data[upLabel] = recorder.Read()
-	data[downLabel] = recorder.Written()
-	updateChart()
-
-	go func() {
-		emptyDrawn := false
-		for {
-			select {
-			case <-ctx.Done():
-				return
-			case <-time.After(recorder.RecordInterval()):
-			}
-
-			data[upLabel] = recorder.Read()
-			data[downLabel] = recorder.Written()
-
-			if allMapZeroes(data) { // Optimization: no need to rerender stale zeroed chart.
-				if !emptyDrawn { // draw empty chart just once
-					updateChart()
-					emptyDrawn = true
-				}
-
-				continue
-			}
-
-			updateChart()
-			emptyDrawn = false
+	var interval time.Duration
+	var fetched time.Time
+	live.draw = func(forced bool) {
+		// Long ranges change less often than the recorder is updated.
+		if !forced && interval > recorder.RecordInterval() && time.Since(fetched) < interval {
+			return
 		}
-	}()
+		interval, fetched = update(), time.Now()
+	}
 
-	return chart.Container()
+	// Initialize the chart with initial data.
+	live.draw(true)
+
+	listen(ctx, recorder.Updates(), func() {
+		live.mu.Lock()
+		defer live.mu.Unlock()
+		live.draw(false)
+	})
+
+	return live
 }
 
-func allMapZeroes(data map[string][]float64) bool {
-	for _, v := range data {
-		if !allZeroes(v) {
-			return false
-		}
+func (c *LiveNetworkChart) Container() *fyne.Container {
+	return c.container
+}
+
+// ExportNetworkChart renders a chart of recorder values for the last span off-screen and writes it in format.
+func ExportNetworkChart(w io.Writer, format netchart.ImageFormat, upLabel, downLabel string, span time.Duration,
+	size fyne.Size, recorder Recorder,
+) error {
+	chart := netchart.New(size, recorder.Unit())
+	if _, err := updateNetworkChart(chart, upLabel, downLabel, span, recorder); err != nil {
+		return err
 	}
 
-	return true
+	return chart.Export(w, size, format)
 }
 
-func allZeroes(vals []float64) bool {
-	for _, v := range vals {
-		if v != 0 {
-			return false
-		}
+// updateNetworkChart draws recorder values for the last span on chart and returns their interval.
+func updateNetworkChart(chart *netchart.Chart, upLabel, downLabel string, span time.Duration, recorder Recorder,
+) (time.Duration, error) {
+	read, written, interval := recorder.Range(span)
+	group := netchart.DownsampleGroup(len(read), maxChartPoints)
+
+	return interval, chart.Update([]netchart.Series{
+		{Name: upLabel, Color: theme.Color(customtheme.ColorNameGraphGreen), Values: netchart.Downsample(read, maxChartPoints)},
+		{Name: downLabel, Color: theme.Color(customtheme.ColorNameGraphBlue), Values: netchart.Downsample(written, maxChartPoints)},
+	}, time.Now(), interval*time.Duration(group))
+}
+
+// SetSpan changes the chart range and redraws it.
+func (c *LiveNetworkChart) SetSpan(span time.Duration) {
+	if time.Duration(c.span.Swap(int64(span))) == span {
+		return
 	}
 
-	return true
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.draw(true)
 }
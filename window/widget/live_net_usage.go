@@ -3,11 +3,11 @@ package widget
 import (
 	"context"
 	"fmt"
-	"time"
 
 	"fyne.io/fyne/v2"
 	"fyne.io/fyne/v2/canvas"
 	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/data/binding"
 	"fyne.io/fyne/v2/lang"
 	"fyne.io/fyne/v2/theme"
 
@@ -19,52 +19,54 @@ type NetStats interface {
 	BytesWritten() int
 	// BytesRead should return total number of bytes read from the connection.
 	BytesRead() int
-	RecordInterval() time.Duration
+	// Updates should notify listeners when the numbers change.
+	Updates() binding.DataItem
 }
 
+// NewLiveNetworkStats creates uplink and downlink counters updated on source updates till ctx is done.
 func NewLiveNetworkStats(ctx context.Context, source NetStats) *fyne.Container {
 	cnt := container.NewHBox(
 		container.NewPadded(canvas.NewText("↑... "+lang.L("GB"), theme.Color(customtheme.ColorNameTextMuted))),
 		container.NewPadded(canvas.NewText("↓... "+lang.L("GB"), theme.Color(customtheme.ColorNameTextMuted))),
 	)
 
-	go func() {
-		prevReadBytes, prevWrittenBytes := "", ""
+	prevReadBytes, prevWrittenBytes := "", ""
+	// Small optimization to not rerender widget if no changes occurred to the values.
+	shouldUpdateUI := func(readBytes, writtenBytes string) bool {
+		noChange := readBytes == prevReadBytes && writtenBytes == prevWrittenBytes
+		if noChange {
+			return false
+		}
 
-		// Small optimization to not rerender widget if no changes occurred to the values.
-		shouldUpdateUI := func(readBytes, writtenBytes string) bool {
-			noChange := readBytes == prevReadBytes && writtenBytes == prevWrittenBytes
-			if noChange {
-				return false
-			}
+		prevReadBytes, prevWrittenBytes = readBytes, writtenBytes
 
-			prevReadBytes, prevWrittenBytes = readBytes, writtenBytes
+		return true
+	}
 
-			return true
-		}
+	listen(ctx, source.Updates(), func() {
+		readBytes := fmt.Sprintf("↑%s", bytesToHumanFriendlyString(source.BytesRead()))
+		writtenBytes := fmt.Sprintf("↓%s", bytesToHumanFriendlyString(source.BytesWritten()))
+		if shouldUpdateUI(readBytes, writtenBytes) {
+			readText := cnt.Objects[0].(*fyne.Container)
+			writeText := cnt.Objects[1].(*fyne.Container)
 
-		for {
-			readBytes := fmt.Sprintf("↑%s", bytesToHumanFriendlyString(source.BytesRead()))
-			writtenBytes := fmt.Sprintf("↓%s", bytesToHumanFriendlyString(source.BytesWritten()))
-			if shouldUpdateUI(readBytes, writtenBytes) {
-				readText := cnt.Objects[0].(*fyne.Container)
-				writeText := cnt.Objects[1].(*fyne.Container)
-
-				readText.Objects[0] = canvas.NewText(readBytes, theme.Color(customtheme.ColorNameTextMuted))
-				writeText.Objects[0] = canvas.NewText(writtenBytes, theme.Color(customtheme.ColorNameTextMuted))
-			}
-
-			select {
-			case <-ctx.Done():
-				return
-			case <-time.After(source.RecordInterval()):
-			}
+			readText.Objects[0] = canvas.NewText(readBytes, theme.Color(customtheme.ColorNameTextMuted))
+			writeText.Objects[0] = canvas.NewText(writtenBytes, theme.Color(customtheme.ColorNameTextMuted))
+			readText.Refresh()
+			writeText.Refresh()
 		}
-	}()
+	})
 
 	return cnt
 }
 
+// listen calls fn on each change of data till ctx is done, fn is also called right away.
+func listen(ctx context.Context, data binding.DataItem, fn func()) {
+	listener := binding.NewDataListener(fn)
+	data.AddListener(listener)
+	context.AfterFunc(ctx, func() { data.RemoveListener(listener) })
+}
+
 // bytesToHumanFriendlyString returns short string representation of bytes, starting from Mb and ending in GB.
 func bytesToHumanFriendlyString(bytes int) string {
 	const bytesToMegabit = 125000
@@ -78,3 +80,20 @@ func bytesToHumanFriendlyString(bytes int) string {
 
 	return fmt.Sprintf("%.2f %s", value, postfix)
 }
+
+// byteUnits are translation keys of units used by FormatBytes.
+var byteUnits = []string{"B", "KB", "MB", "GB", "TB"}
+
+// FormatBytes returns short string representation of bytes in the largest fitting unit, e.g. "1.25 GB".
+func FormatBytes(bytes int64) string {
+	value, unit := float64(bytes), 0
+	for value >= 1000 && unit < len(byteUnits)-1 {
+		value /= 1000
+		unit++
+	}
+	if unit == 0 {
+		return fmt.Sprintf("%d %s", bytes, lang.L(byteUnits[unit]))
+	}
+
+	return fmt.Sprintf("%.2f %s", value, lang.L(byteUnits[unit]))
+}
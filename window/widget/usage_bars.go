@@ -0,0 +1,61 @@
+package widget
+
+import (
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/theme"
+
+	customtheme "github.com/goxray/desktop/theme"
+)
+
+// usageBarGap is a gap between groups of bars in pixels.
+const usageBarGap = 2
+
+// UsageBars is a bar chart with uplink and downlink bars for each period (e.g. day).
+type UsageBars struct {
+	container *fyne.Container
+	size      fyne.Size
+}
+
+// NewUsageBars creates empty chart of the given size, use Update to draw values.
+func NewUsageBars(size fyne.Size) *UsageBars {
+	return &UsageBars{container: container.NewWithoutLayout(), size: size}
+}
+
+func (b *UsageBars) Container() *fyne.Container {
+	return b.container
+}
+
+// Update redraws the chart, the number of bars is len(read), written must have the same length.
+func (b *UsageBars) Update(read, written []int64) {
+	bg := canvas.NewRectangle(theme.Color(theme.ColorNameInputBackground))
+	bg.SetMinSize(b.size)
+	bg.Resize(b.size)
+	objects := []fyne.CanvasObject{bg}
+
+	var top int64
+	for i := range read {
+		top = max(top, read[i], written[i])
+	}
+
+	if n := len(read); n > 0 && top > 0 {
+		groupWidth := b.size.Width / float32(n)
+		barWidth := max((groupWidth-usageBarGap)/2, 1)
+		bar := func(val int64, x float32, colorName fyne.ThemeColorName) {
+			height := b.size.Height * float32(val) / float32(top)
+			rect := canvas.NewRectangle(theme.Color(colorName))
+			rect.Resize(fyne.NewSize(barWidth, height))
+			rect.Move(fyne.NewPos(x, b.size.Height-height))
+			objects = append(objects, rect)
+		}
+		for i := range read {
+			x := float32(i) * groupWidth
+			bar(read[i], x, customtheme.ColorNameGraphGreen)
+			bar(written[i], x+barWidth, customtheme.ColorNameGraphBlue)
+		}
+	}
+
+	b.container.Objects = objects
+	b.container.Refresh()
+}